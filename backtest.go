@@ -0,0 +1,356 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EntrySignal decides whether to open a position at the close of the last candle in history
+type EntrySignal func(history []Candle) (side string, ok bool)
+
+// alwaysLong enters a long position whenever the backtest is flat
+func alwaysLong(history []Candle) (string, bool) {
+	return SideLong, true
+}
+
+// BacktestTrade is a round trip simulated by the backtester
+type BacktestTrade struct {
+	Side       string
+	EntryPrice float64
+	Quantity   float64
+	OpenedAt   time.Time
+	ClosedAt   time.Time
+	// Profit net of entry and exit fees
+	NetProfit float64
+	// Net profit as a fraction of equity at entry
+	Return float64
+}
+
+// BacktestResult summarizes a backtest run
+type BacktestResult struct {
+	InitialEquity float64
+	FinalEquity   float64
+	// Final equity over initial equity, minus one
+	TotalReturn float64
+	// Largest peak-to-trough decline of the equity curve as a fraction
+	MaxDrawdown float64
+	WinRate     float64
+	TradeCount  int
+	// Sharpe ratio of the per-trade returns
+	Sharpe      float64
+	Trades      []BacktestTrade
+	EquityCurve []float64
+}
+
+// Backtester replays candles through the production sizing, tier and risk logic
+type Backtester struct {
+	config *Config
+	signal EntrySignal
+}
+
+// NewBacktester creates a backtester of TradingPair, applying its risk overrides; a nil
+// signal enters long whenever flat
+func NewBacktester(config *Config, signal EntrySignal) *Backtester {
+	if signal == nil {
+		signal = alwaysLong
+	}
+	return &Backtester{config: config.ForSymbol(config.Trading.TradingPair), signal: signal}
+}
+
+// backtestPosition is the open position of a backtest
+type backtestPosition struct {
+	side          string
+	entryPrice    float64
+	quantity      float64
+	remaining     float64
+	stopPrice     float64
+	breakEven     bool
+	targets       []float64
+	tierQuantity  []float64
+	tierHit       []bool
+	openedAt      time.Time
+	equityAtEntry float64
+	netProfit     float64
+}
+
+// direction returns 1 for longs and -1 for shorts
+func (p *backtestPosition) direction() float64 {
+	if isLong(p.side) {
+		return 1
+	}
+	return -1
+}
+
+// Run simulates the candles in order. Entries fill at the signal candle's close and exits are
+// checked from the next tradable candle on, stops before tier targets so results stay conservative.
+func (b *Backtester) Run(candles []Candle) (*BacktestResult, error) {
+	c := b.config
+	candles = ApplyZeroVolumePolicy(candles, c.Backtest.ZeroVolumePolicy)
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("no tradable candles to backtest")
+	}
+
+	var now time.Time
+	losses := c.NewLossTracker()
+	losses.now = func() time.Time { return now }
+	daily := c.NewDailyLossGuard()
+	drawdown := c.NewDrawdownMonitor()
+	drawdown.now = func() time.Time { return now }
+	cooldowns := c.NewCooldownTracker()
+
+	result := &BacktestResult{InitialEquity: c.FixedCapital.TotalCapital}
+	balance := c.FixedCapital.TotalCapital
+	var position *backtestPosition
+
+	closeQuantity := func(price, quantity float64) {
+		profit := position.direction()*(price-position.entryPrice)*quantity - price*quantity*c.exitFee()
+		position.netProfit += profit
+		position.remaining -= quantity
+		balance += profit
+	}
+	finishTrade := func() {
+		trade := BacktestTrade{
+			Side:       position.side,
+			EntryPrice: position.entryPrice,
+			Quantity:   position.quantity,
+			OpenedAt:   position.openedAt,
+			ClosedAt:   now,
+			NetProfit:  position.netProfit,
+			Return:     position.netProfit / position.equityAtEntry,
+		}
+		result.Trades = append(result.Trades, trade)
+		losses.RecordTrade(trade.NetProfit)
+		cooldowns.RecordClose(c.Trading.TradingPair, now)
+		position = nil
+	}
+
+	for i, candle := range candles {
+		now = candle.OpenTime
+		if !candle.Tradable() {
+			result.EquityCurve = append(result.EquityCurve, b.markEquity(balance, position, candle.Close))
+			continue
+		}
+
+		if position != nil {
+			b.applyExits(position, candle, closeQuantity)
+			if position.remaining <= position.quantity*tierPercentageEpsilon {
+				finishTrade()
+			}
+		}
+
+		equity := b.markEquity(balance, position, candle.Close)
+		riskEquity := equity
+		if c.RiskManagement.RiskEquityBasis == EquityRealized {
+			riskEquity = balance
+		}
+		drawdown.Record(riskEquity)
+		canTrade := daily.CanTrade(now, riskEquity)
+
+		if position == nil && canTrade && b.riskAllowsEntry(losses, drawdown) && cooldowns.CanEnter(c.Trading.TradingPair, now) {
+			if side, ok := b.signal(candles[:i+1]); ok {
+				position = b.openPosition(side, candle, equity)
+				if position != nil {
+					balance -= candle.Close * position.quantity * c.entryFee()
+					position.netProfit -= candle.Close * position.quantity * c.entryFee()
+				}
+			}
+		}
+
+		result.EquityCurve = append(result.EquityCurve, b.markEquity(balance, position, candle.Close))
+	}
+
+	if position != nil {
+		closeQuantity(candles[len(candles)-1].Close, position.remaining)
+		finishTrade()
+		result.EquityCurve[len(result.EquityCurve)-1] = balance
+	}
+
+	result.FinalEquity = balance
+	result.TotalReturn = balance/result.InitialEquity - 1
+	result.TradeCount = len(result.Trades)
+	result.MaxDrawdown = MaxDrawdown(result.EquityCurve)
+	returns := make([]float64, len(result.Trades))
+	wins := 0
+	for i, trade := range result.Trades {
+		returns[i] = trade.Return
+		if trade.NetProfit > 0 {
+			wins++
+		}
+	}
+	if result.TradeCount > 0 {
+		result.WinRate = float64(wins) / float64(result.TradeCount)
+	}
+	result.Sharpe = Sharpe(returns, 0)
+
+	return result, nil
+}
+
+// riskAllowsEntry applies the consecutive loss pause and the drawdown limit
+func (b *Backtester) riskAllowsEntry(losses *LossTracker, drawdown *DrawdownMonitor) bool {
+	if paused, _ := losses.ShouldPause(); paused {
+		return false
+	}
+	if b.config.RiskManagement.DrawdownMonitoringEnabled && !drawdown.WithinLimit() {
+		return false
+	}
+	return true
+}
+
+// openPosition sizes an entry at the candle's close, returning nil when the size is below the
+// minimum order. Without a stop loss percentage the position has no stop and is sized by
+// CalculatePositionSizeQuote.
+func (b *Backtester) openPosition(side string, candle Candle, equity float64) *backtestPosition {
+	c := b.config
+	entry := candle.Close
+	stop := c.StopLossPrice(entry, side)
+	var size float64
+	if stop > 0 {
+		size = c.CalculatePositionSize(equity, entry, stop, side)
+	} else {
+		_, size = c.CalculatePositionSizeQuote(equity, entry)
+	}
+	quantity := math.Min(size, c.Trading.MaxOrderQuantity)
+	if quantity < c.Trading.MinOrderQuantity {
+		return nil
+	}
+
+	position := &backtestPosition{
+		side:          side,
+		entryPrice:    entry,
+		quantity:      quantity,
+		remaining:     quantity,
+		stopPrice:     stop,
+		openedAt:      candle.OpenTime,
+		equityAtEntry: equity,
+	}
+	if c.MultiTier.Enabled {
+		tierQuantity, err := c.MultiTier.CalculateTierQuantities(quantity)
+		if err == nil {
+			position.targets = c.MultiTier.TierTargetPrices(entry, side)
+			position.tierQuantity = tierQuantity
+			position.tierHit = make([]bool, len(tierQuantity))
+		}
+	}
+	return position
+}
+
+// applyExits checks the stop, tier targets, break-even and hold time against a candle. A stop
+// fills at its price, or at the open when the candle gaps through it.
+func (b *Backtester) applyExits(p *backtestPosition, candle Candle, closeQuantity func(price, quantity float64)) {
+	c := b.config
+	long := isLong(p.side)
+	favorable, adverse := candle.High, candle.Low
+	if !long {
+		favorable, adverse = candle.Low, candle.High
+	}
+
+	if p.stopPrice > 0 && ((long && adverse <= p.stopPrice) || (!long && adverse >= p.stopPrice)) {
+		// A candle opening through the stop gapped past it and fills at the open
+		fill := p.stopPrice
+		if candle.Open > 0 && ((long && candle.Open < fill) || (!long && candle.Open > fill)) {
+			fill = candle.Open
+		}
+		closeQuantity(fill, p.remaining)
+		return
+	}
+
+	for i, target := range p.targets {
+		if p.tierHit[i] || p.tierQuantity[i] == 0 || target == 0 {
+			continue
+		}
+		if (long && favorable >= target) || (!long && favorable <= target) {
+			p.tierHit[i] = true
+			closeQuantity(target, math.Min(p.tierQuantity[i], p.remaining))
+		}
+	}
+	if p.remaining <= p.quantity*tierPercentageEpsilon {
+		return
+	}
+
+	if !p.breakEven && c.BreakEvenTriggered(p.entryPrice, favorable, p.side) {
+		p.breakEven = true
+		p.stopPrice = c.CalculateBreakEvenStop(p.entryPrice, p.side)
+	}
+
+	if c.NewPositionTimer(p.openedAt).ShouldClose(candle.OpenTime) {
+		closeQuantity(candle.Close, p.remaining)
+	}
+}
+
+// markEquity returns the balance plus the unrealized profit of the open position at price
+func (b *Backtester) markEquity(balance float64, p *backtestPosition, price float64) float64 {
+	if p == nil {
+		return balance
+	}
+	return balance + p.direction()*(price-p.entryPrice)*p.remaining
+}
+
+// LoadCandlesCSVFile loads candles from a CSV file; see LoadCandlesCSV
+func LoadCandlesCSVFile(path string) ([]Candle, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening candle file %s: %v", path, err)
+	}
+	defer file.Close()
+	return LoadCandlesCSV(file)
+}
+
+// LoadCandlesCSV reads candles with the columns open_time, open, high, low, close, volume.
+// Extra columns such as those of Binance kline exports are ignored, an optional header row
+// is skipped, and open_time may be Unix milliseconds or RFC 3339.
+func LoadCandlesCSV(r io.Reader) ([]Candle, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	var candles []Candle
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return candles, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading candles: %v", err)
+		}
+		if len(record) < 6 {
+			return nil, fmt.Errorf("candle line %d has %d columns, expected at least 6", line, len(record))
+		}
+		if line == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "open_time") {
+			continue
+		}
+
+		candle, err := parseCandleRecord(record)
+		if err != nil {
+			return nil, fmt.Errorf("candle line %d: %v", line, err)
+		}
+		candles = append(candles, candle)
+	}
+}
+
+// parseCandleRecord parses one CSV candle record
+func parseCandleRecord(record []string) (Candle, error) {
+	var candle Candle
+	openTime := strings.TrimSpace(record[0])
+	if ms, err := strconv.ParseInt(openTime, 10, 64); err == nil {
+		candle.OpenTime = time.UnixMilli(ms).UTC()
+	} else if t, err := time.Parse(time.RFC3339, openTime); err == nil {
+		candle.OpenTime = t.UTC()
+	} else {
+		return Candle{}, fmt.Errorf("invalid open time %q", openTime)
+	}
+
+	values := []*float64{&candle.Open, &candle.High, &candle.Low, &candle.Close, &candle.Volume}
+	for i, value := range values {
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(record[i+1]), 64)
+		if err != nil {
+			return Candle{}, fmt.Errorf("invalid value %q in column %d", record[i+1], i+2)
+		}
+		*value = parsed
+	}
+	return candle, nil
+}
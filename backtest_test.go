@@ -0,0 +1,127 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// backtestConfig returns a config trading one pair with a 10% stop and no tiers, break-even or hold limit
+func backtestConfig() *Config {
+	c := DefaultConfig()
+	c.Trading.TradingPair = "BNBUSDT"
+	c.Trading.MakerFee = 0
+	c.Trading.TakerFee = 0
+	c.Trading.MakerRebateRate = 0
+	c.RiskManagement.StopLossPercentage = 0.1
+	c.RiskManagement.BreakEvenThreshold = 0
+	c.MultiTier.Enabled = false
+	c.MultiTier.CloseOnTimeout = false
+	return c
+}
+
+// candleAt builds a tradable candle i minutes after a fixed start
+func candleAt(i int, open, high, low, close float64) Candle {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return Candle{OpenTime: start.Add(time.Duration(i) * time.Minute), Open: open, High: high, Low: low, Close: close, Volume: 1}
+}
+
+// enterOnce enters long on the first candle only
+func enterOnce(history []Candle) (string, bool) {
+	return SideLong, len(history) == 1
+}
+
+func TestBacktestStopFillsAtGapOpen(t *testing.T) {
+	c := backtestConfig()
+	candles := []Candle{
+		candleAt(0, 100, 100, 100, 100),
+		// Gaps from 100 through the 90 stop, opening at 80
+		candleAt(1, 80, 82, 78, 81),
+	}
+	result, err := NewBacktester(c, enterOnce).Run(candles)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(result.Trades) != 1 {
+		t.Fatalf("trades = %d, want 1", len(result.Trades))
+	}
+	trade := result.Trades[0]
+	if want := (80 - 100) * trade.Quantity; math.Abs(trade.NetProfit-want) > 1e-9 {
+		t.Errorf("net profit = %f, want %f from a fill at the gap open", trade.NetProfit, want)
+	}
+}
+
+func TestBacktestShortStopFillsAtGapOpen(t *testing.T) {
+	c := backtestConfig()
+	enterShort := func(history []Candle) (string, bool) { return SideShort, len(history) == 1 }
+	candles := []Candle{
+		candleAt(0, 100, 100, 100, 100),
+		// Gaps from 100 through the 110 stop, opening at 120
+		candleAt(1, 120, 121, 118, 119),
+	}
+	result, err := NewBacktester(c, enterShort).Run(candles)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	trade := result.Trades[0]
+	if want := (100 - 120) * trade.Quantity; math.Abs(trade.NetProfit-want) > 1e-9 {
+		t.Errorf("net profit = %f, want %f from a fill at the gap open", trade.NetProfit, want)
+	}
+}
+
+func TestBacktestStopFillsAtStopWithoutGap(t *testing.T) {
+	c := backtestConfig()
+	candles := []Candle{
+		candleAt(0, 100, 100, 100, 100),
+		candleAt(1, 99, 99, 85, 88),
+	}
+	result, err := NewBacktester(c, enterOnce).Run(candles)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	trade := result.Trades[0]
+	if want := (90 - 100) * trade.Quantity; math.Abs(trade.NetProfit-want) > 1e-9 {
+		t.Errorf("net profit = %f, want %f from a fill at the stop", trade.NetProfit, want)
+	}
+}
+
+func TestBacktestTradesWithoutStopLoss(t *testing.T) {
+	c := backtestConfig()
+	c.RiskManagement.StopLossPercentage = 0
+	candles := []Candle{
+		candleAt(0, 100, 100, 100, 100),
+		candleAt(1, 100, 100, 50, 60),
+		candleAt(2, 60, 70, 60, 70),
+	}
+	result, err := NewBacktester(c, enterOnce).Run(candles)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(result.Trades) != 1 {
+		t.Fatalf("trades = %d, want 1 without a stop loss", len(result.Trades))
+	}
+	_, want := c.CalculatePositionSizeQuote(c.FixedCapital.TotalCapital, 100)
+	trade := result.Trades[0]
+	if math.Abs(trade.Quantity-want) > 1e-9 {
+		t.Errorf("quantity = %f, want %f", trade.Quantity, want)
+	}
+	// Without a stop the position rides the drop and closes at the last candle
+	if !trade.ClosedAt.Equal(candles[2].OpenTime) {
+		t.Errorf("closed at %s, want the last candle", trade.ClosedAt)
+	}
+}
+
+func TestLoadCandlesCSV(t *testing.T) {
+	csv := "open_time,open,high,low,close,volume\n1700000000000,1,2,0.5,1.5,10\n2024-01-01T00:00:00Z,1.5,2,1,1.8,0\n"
+	candles, err := LoadCandlesCSV(strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("LoadCandlesCSV: %v", err)
+	}
+	if len(candles) != 2 || candles[0].High != 2 || candles[1].Close != 1.8 || candles[1].Tradable() {
+		t.Errorf("candles = %+v", candles)
+	}
+	if _, err := LoadCandlesCSV(strings.NewReader("1700000000000,1,2\n")); err == nil {
+		t.Error("short record was accepted")
+	}
+}
@@ -0,0 +1,542 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Binance REST base URLs
+const (
+	binanceSpotURL           = "https://api.binance.com"
+	binanceSpotTestnetURL    = "https://testnet.binance.vision"
+	binanceFuturesURL        = "https://fapi.binance.com"
+	binanceFuturesTestnetURL = "https://testnet.binancefuture.com"
+)
+
+const (
+	// binanceRecvWindow is how long in milliseconds a signed request stays valid after its timestamp
+	binanceRecvWindow = 5000
+	// binanceRequestTimeout bounds a single REST request
+	binanceRequestTimeout = 10 * time.Second
+	// binanceMaxAttempts is how often read-only requests are tried before giving up
+	binanceMaxAttempts = 3
+	// binanceRetryBackoff is the delay before the first retry of a read-only request
+	binanceRetryBackoff = 500 * time.Millisecond
+	// binanceInvalidTimestamp is the error code of a request whose timestamp is outside the recv window
+	binanceInvalidTimestamp = -1021
+)
+
+// binanceEndpoint is a REST path and its request weight on spot and on USDⓈ-M futures; an
+// empty futures path means the endpoint only exists on spot
+type binanceEndpoint struct {
+	spot          string
+	futures       string
+	spotWeight    int
+	futuresWeight int
+}
+
+var (
+	binanceTimeEndpoint           = binanceEndpoint{"/api/v3/time", "/fapi/v1/time", 1, 1}
+	binanceOrderEndpoint          = binanceEndpoint{"/api/v3/order", "/fapi/v1/order", 1, 1}
+	binanceQueryOrderEndpoint     = binanceEndpoint{"/api/v3/order", "/fapi/v1/order", 4, 1}
+	binanceOCOEndpoint            = binanceEndpoint{"/api/v3/orderList/oco", "", 1, 0}
+	binanceOrderListEndpoint      = binanceEndpoint{"/api/v3/orderList", "", 1, 0}
+	binanceQueryOrderListEndpoint = binanceEndpoint{"/api/v3/orderList", "", 4, 0}
+	binanceAccountEndpoint        = binanceEndpoint{"/api/v3/account", "/fapi/v2/account", 20, 5}
+	binancePriceEndpoint          = binanceEndpoint{"/api/v3/ticker/price", "/fapi/v1/ticker/price", 2, 1}
+)
+
+// BinanceClient is a signed Binance REST client for spot or, in FuturesMode, USDⓈ-M futures.
+// Signed requests are timestamped by a ServerClock so minor local clock drift is corrected,
+// and every request waits for its weight on a RateLimiter.
+type BinanceClient struct {
+	config       *Config
+	baseURL      string
+	apiKey       string
+	apiSecret    string
+	futures      bool
+	http         *http.Client
+	clock        *ServerClock
+	limiter      *RateLimiter
+	classifier   *ErrorClassifier
+	retryBackoff time.Duration
+}
+
+// NewBinanceClient creates a client for the configured market, network and credentials
+func (c *Config) NewBinanceClient() *BinanceClient {
+	baseURL := binanceSpotURL
+	switch {
+	case c.FuturesMode && c.Trading.TestnetEnabled:
+		baseURL = binanceFuturesTestnetURL
+	case c.FuturesMode:
+		baseURL = binanceFuturesURL
+	case c.Trading.TestnetEnabled:
+		baseURL = binanceSpotTestnetURL
+	}
+	client := &BinanceClient{
+		config:       c,
+		baseURL:      baseURL,
+		apiKey:       c.Trading.APIKey,
+		apiSecret:    c.Trading.APISecret,
+		futures:      c.FuturesMode,
+		http:         &http.Client{Timeout: binanceRequestTimeout},
+		limiter:      c.NewRateLimiter(),
+		classifier:   NewErrorClassifier(),
+		retryBackoff: binanceRetryBackoff,
+	}
+	client.clock = c.NewServerClock(client.ServerTime)
+	return client
+}
+
+// Clock returns the server clock timestamping signed requests; Sync it before trading and Run
+// it to keep the offset fresh
+func (b *BinanceClient) Clock() *ServerClock {
+	return b.clock
+}
+
+// binanceOrderResponse is an order as returned by the spot and futures order endpoints
+type binanceOrderResponse struct {
+	Symbol      string `json:"symbol"`
+	OrderID     int64  `json:"orderId"`
+	Side        string `json:"side"`
+	Type        string `json:"type"`
+	Status      string `json:"status"`
+	Price       string `json:"price"`
+	OrigQty     string `json:"origQty"`
+	ExecutedQty string `json:"executedQty"`
+	// Spot reports the executed quote quantity, futures the average price
+	CummulativeQuoteQty string `json:"cummulativeQuoteQty"`
+	AvgPrice            string `json:"avgPrice"`
+	TransactTime        int64  `json:"transactTime"`
+	UpdateTime          int64  `json:"updateTime"`
+	Fills               []struct {
+		Commission string `json:"commission"`
+	} `json:"fills"`
+}
+
+// fill converts the response into the cumulative fill of order
+func (r binanceOrderResponse) fill(order Order) Fill {
+	quantity := parseDecimal(r.ExecutedQty)
+	price := parseDecimal(r.AvgPrice)
+	if price == 0 && quantity > 0 {
+		price = parseDecimal(r.CummulativeQuoteQty) / quantity
+	}
+	var fee float64
+	for _, fill := range r.Fills {
+		fee += parseDecimal(fill.Commission)
+	}
+	at := r.TransactTime
+	if at == 0 {
+		at = r.UpdateTime
+	}
+	fillTime := time.Now()
+	if at > 0 {
+		fillTime = time.UnixMilli(at)
+	}
+	return Fill{
+		OrderID:  strconv.FormatInt(r.OrderID, 10),
+		Order:    order,
+		Price:    price,
+		Quantity: quantity,
+		Fee:      fee,
+		Time:     fillTime,
+	}
+}
+
+// order reconstructs the submitted order from the response
+func (r binanceOrderResponse) order() Order {
+	order := Order{
+		Symbol:   r.Symbol,
+		Side:     strings.ToLower(r.Side),
+		Quantity: parseDecimal(r.OrigQty),
+		Price:    parseDecimal(r.Price),
+		Type:     OrderTypeMarket,
+	}
+	switch r.Type {
+	case "LIMIT", "LIMIT_MAKER":
+		order.Type = OrderTypeLimit
+	case "STOP_LOSS_LIMIT", "STOP":
+		order.Type = OrderTypeStopLimit
+	}
+	return order
+}
+
+// ServerTime returns the exchange server time
+func (b *BinanceClient) ServerTime(ctx context.Context) (time.Time, error) {
+	var response struct {
+		ServerTime int64 `json:"serverTime"`
+	}
+	if err := b.do(ctx, http.MethodGet, binanceTimeEndpoint, url.Values{}, false, &response); err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(response.ServerTime), nil
+}
+
+// Submit places order and returns its fill so far: complete for market orders, possibly
+// partial or empty for limit orders still resting on the book
+func (b *BinanceClient) Submit(ctx context.Context, order Order) (Fill, error) {
+	params := url.Values{}
+	params.Set("symbol", order.Symbol)
+	params.Set("side", binanceSide(order.Side))
+	params.Set("quantity", formatDecimal(order.Quantity))
+	switch order.Type {
+	case OrderTypeLimit:
+		params.Set("type", "LIMIT")
+		params.Set("price", formatDecimal(order.Price))
+		params.Set("timeInForce", string(binanceTimeInForce(order.TimeInForce)))
+	case OrderTypeStopLimit:
+		params.Set("type", "STOP_LOSS_LIMIT")
+		if b.futures {
+			params.Set("type", "STOP")
+		}
+		params.Set("price", formatDecimal(order.Price))
+		params.Set("stopPrice", formatDecimal(order.StopPrice))
+		params.Set("timeInForce", string(binanceTimeInForce(order.TimeInForce)))
+	default:
+		params.Set("type", "MARKET")
+	}
+	if b.futures {
+		if order.ReduceOnly {
+			params.Set("reduceOnly", "true")
+		}
+		params.Set("newOrderRespType", "RESULT")
+	} else {
+		params.Set("newOrderRespType", "FULL")
+	}
+
+	var response binanceOrderResponse
+	if err := b.do(ctx, http.MethodPost, binanceOrderEndpoint, params, true, &response); err != nil {
+		return Fill{}, fmt.Errorf("error placing %s %s order for %s: %w", order.Side, order.Type, order.Symbol, err)
+	}
+	return response.fill(order), nil
+}
+
+// CancelOrder cancels the unfilled remainder of an open order
+func (b *BinanceClient) CancelOrder(ctx context.Context, symbol, orderID string) error {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("orderId", orderID)
+	if err := b.do(ctx, http.MethodDelete, binanceOrderEndpoint, params, true, nil); err != nil {
+		return fmt.Errorf("error cancelling order %s for %s: %w", orderID, symbol, err)
+	}
+	return nil
+}
+
+// QueryOrder returns the cumulative fill of an order
+func (b *BinanceClient) QueryOrder(ctx context.Context, symbol, orderID string) (Fill, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("orderId", orderID)
+	var response binanceOrderResponse
+	if err := b.do(ctx, http.MethodGet, binanceQueryOrderEndpoint, params, true, &response); err != nil {
+		return Fill{}, fmt.Errorf("error querying order %s for %s: %w", orderID, symbol, err)
+	}
+	return response.fill(response.order()), nil
+}
+
+// PlaceStopOrder places a stop-market order for order's symbol, side and quantity that
+// triggers at stopPrice, reduce-only on futures when order is
+func (b *BinanceClient) PlaceStopOrder(ctx context.Context, order Order, stopPrice float64) (string, error) {
+	params := url.Values{}
+	params.Set("symbol", order.Symbol)
+	params.Set("side", binanceSide(order.Side))
+	params.Set("quantity", formatDecimal(order.Quantity))
+	params.Set("stopPrice", formatDecimal(stopPrice))
+	if b.futures {
+		params.Set("type", "STOP_MARKET")
+		if order.ReduceOnly {
+			params.Set("reduceOnly", "true")
+		}
+	} else {
+		params.Set("type", "STOP_LOSS")
+	}
+
+	var response binanceOrderResponse
+	if err := b.do(ctx, http.MethodPost, binanceOrderEndpoint, params, true, &response); err != nil {
+		return "", fmt.Errorf("error placing stop order for %s at %f: %w", order.Symbol, stopPrice, err)
+	}
+	return strconv.FormatInt(response.OrderID, 10), nil
+}
+
+// binanceOrderList is an OCO order list as returned by the spot order list endpoints
+type binanceOrderList struct {
+	OrderListID     int64  `json:"orderListId"`
+	ListOrderStatus string `json:"listOrderStatus"`
+	Orders          []struct {
+		Symbol  string `json:"symbol"`
+		OrderID int64  `json:"orderId"`
+	} `json:"orders"`
+}
+
+// PlaceOCO places order as a spot OCO list of a limit-maker take profit and a stop-loss leg.
+// Futures have no OCO orders.
+func (b *BinanceClient) PlaceOCO(ctx context.Context, order OCOOrder) (string, error) {
+	if b.futures {
+		return "", fmt.Errorf("OCO orders are not supported on futures")
+	}
+	params := url.Values{}
+	params.Set("symbol", order.Symbol)
+	params.Set("side", binanceSide(order.Side))
+	params.Set("quantity", formatDecimal(order.Quantity))
+	// A sell closes a long with the take profit above the market and the stop below it; a buy
+	// closes a short the other way round
+	takeProfit, stop := "above", "below"
+	if isBuy(order.Side) {
+		takeProfit, stop = "below", "above"
+	}
+	params.Set(takeProfit+"Type", "LIMIT_MAKER")
+	params.Set(takeProfit+"Price", formatDecimal(order.TakeProfitPrice))
+	params.Set(stop+"Type", "STOP_LOSS")
+	params.Set(stop+"StopPrice", formatDecimal(order.StopPrice))
+
+	var response binanceOrderList
+	if err := b.do(ctx, http.MethodPost, binanceOCOEndpoint, params, true, &response); err != nil {
+		return "", fmt.Errorf("error placing OCO for %s: %w", order.Symbol, err)
+	}
+	return strconv.FormatInt(response.OrderListID, 10), nil
+}
+
+// CancelOCO cancels both legs of an OCO list
+func (b *BinanceClient) CancelOCO(ctx context.Context, symbol, listID string) error {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	params.Set("orderListId", listID)
+	if err := b.do(ctx, http.MethodDelete, binanceOrderListEndpoint, params, true, nil); err != nil {
+		return fmt.Errorf("error cancelling OCO %s for %s: %w", listID, symbol, err)
+	}
+	return nil
+}
+
+// QueryOCO reports whether an OCO list is done and, when a leg executed, that leg's fill
+func (b *BinanceClient) QueryOCO(ctx context.Context, symbol, listID string) (Fill, bool, error) {
+	params := url.Values{}
+	params.Set("orderListId", listID)
+	var response binanceOrderList
+	if err := b.do(ctx, http.MethodGet, binanceQueryOrderListEndpoint, params, true, &response); err != nil {
+		return Fill{}, false, fmt.Errorf("error querying OCO %s for %s: %w", listID, symbol, err)
+	}
+	if response.ListOrderStatus != "ALL_DONE" {
+		return Fill{}, false, nil
+	}
+	for _, leg := range response.Orders {
+		fill, err := b.QueryOrder(ctx, leg.Symbol, strconv.FormatInt(leg.OrderID, 10))
+		if err != nil {
+			return Fill{}, false, err
+		}
+		if fill.Quantity > 0 {
+			return fill, true, nil
+		}
+	}
+	return Fill{}, true, nil
+}
+
+// Price returns the latest traded price of symbol
+func (b *BinanceClient) Price(ctx context.Context, symbol string) (float64, error) {
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	var response struct {
+		Price string `json:"price"`
+	}
+	if err := b.do(ctx, http.MethodGet, binancePriceEndpoint, params, false, &response); err != nil {
+		return 0, fmt.Errorf("error fetching price of %s: %w", symbol, err)
+	}
+	price := parseDecimal(response.Price)
+	if price <= 0 {
+		return 0, fmt.Errorf("invalid price %q for %s", response.Price, symbol)
+	}
+	return price, nil
+}
+
+// TotalEquity returns the account's equity in the quote currency, open positions included. On
+// futures it is the margin balance, the wallet balance plus unrealized P&L. On spot it is the
+// quote asset balance plus the base asset balances of the configured pairs at their latest
+// price; other assets are ignored.
+func (b *BinanceClient) TotalEquity(ctx context.Context) (float64, error) {
+	if b.futures {
+		var response struct {
+			TotalMarginBalance string `json:"totalMarginBalance"`
+		}
+		if err := b.do(ctx, http.MethodGet, binanceAccountEndpoint, url.Values{}, true, &response); err != nil {
+			return 0, fmt.Errorf("error fetching futures account: %w", err)
+		}
+		return parseDecimal(response.TotalMarginBalance), nil
+	}
+
+	params := url.Values{}
+	params.Set("omitZeroBalances", "true")
+	var response struct {
+		Balances []struct {
+			Asset  string `json:"asset"`
+			Free   string `json:"free"`
+			Locked string `json:"locked"`
+		} `json:"balances"`
+	}
+	if err := b.do(ctx, http.MethodGet, binanceAccountEndpoint, params, true, &response); err != nil {
+		return 0, fmt.Errorf("error fetching spot account: %w", err)
+	}
+
+	quote := QuoteAsset(b.config.Trading.TradingPair)
+	pairs := make(map[string]string)
+	for _, symbol := range b.config.Pairs() {
+		if QuoteAsset(symbol) == quote {
+			pairs[BaseAsset(symbol)] = symbol
+		}
+	}
+	var equity float64
+	for _, balance := range response.Balances {
+		amount := parseDecimal(balance.Free) + parseDecimal(balance.Locked)
+		if balance.Asset == quote {
+			equity += amount
+			continue
+		}
+		symbol, ok := pairs[balance.Asset]
+		if !ok || amount == 0 {
+			continue
+		}
+		price, err := b.Price(ctx, symbol)
+		if err != nil {
+			return 0, err
+		}
+		equity += amount * price
+	}
+	return equity, nil
+}
+
+// do sends a request to endpoint, signing it when signed, and decodes the JSON response into
+// out when it is not nil. Read-only requests are retried on retryable errors; any request
+// rejected for its timestamp is resent once after resyncing the server clock, as the exchange
+// did not act on it.
+func (b *BinanceClient) do(ctx context.Context, method string, endpoint binanceEndpoint, params url.Values, signed bool, out interface{}) error {
+	path, weight := endpoint.spot, endpoint.spotWeight
+	if b.futures {
+		path, weight = endpoint.futures, endpoint.futuresWeight
+	}
+	if path == "" {
+		return fmt.Errorf("endpoint %s is not available on futures", endpoint.spot)
+	}
+
+	send := func(ctx context.Context) error {
+		err := b.send(ctx, method, path, weight, params, signed, out)
+		var exchangeErr *ExchangeError
+		if signed && errors.As(err, &exchangeErr) && exchangeErr.Code == binanceInvalidTimestamp {
+			if syncErr := b.clock.Sync(ctx); syncErr != nil {
+				return fmt.Errorf("%w (clock resync failed: %v)", err, syncErr)
+			}
+			err = b.send(ctx, method, path, weight, params, signed, out)
+		}
+		return err
+	}
+	if method != http.MethodGet {
+		return send(ctx)
+	}
+	return RetryWithBackoff(ctx, binanceMaxAttempts, b.retryBackoff, send)
+}
+
+// send sends one request, waiting for its weight on the rate limiter
+func (b *BinanceClient) send(ctx context.Context, method, path string, weight int, params url.Values, signed bool, out interface{}) error {
+	if err := b.limiter.Wait(ctx, weight); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	for key, values := range params {
+		query[key] = values
+	}
+	if signed {
+		query.Set("recvWindow", strconv.Itoa(binanceRecvWindow))
+		query.Set("timestamp", strconv.FormatInt(b.clock.Timestamp(), 10))
+	}
+	// The signature covers the query exactly as sent, so it is appended last
+	encoded := query.Encode()
+	if signed {
+		encoded += "&signature=" + b.sign(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, b.baseURL+path+"?"+encoded, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", err)
+	}
+	if b.apiKey != "" {
+		req.Header.Set("X-MBX-APIKEY", b.apiKey)
+	}
+
+	resp, err := b.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request to %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response from %s: %v", path, err)
+	}
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Code int    `json:"code"`
+			Msg  string `json:"msg"`
+		}
+		if err := json.Unmarshal(body, &apiErr); err != nil || apiErr.Msg == "" {
+			apiErr.Msg = strings.TrimSpace(string(body))
+		}
+		exchangeErr := NewExchangeError(b.classifier, resp.StatusCode, apiErr.Code, apiErr.Msg)
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			exchangeErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return exchangeErr
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error decoding response from %s: %v", path, err)
+	}
+	return nil
+}
+
+// sign returns the hex HMAC-SHA256 signature of a query string
+func (b *BinanceClient) sign(query string) string {
+	mac := hmac.New(sha256.New, []byte(b.apiSecret))
+	mac.Write([]byte(query))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// binanceSide returns the Binance order side of an order or position side
+func binanceSide(side string) string {
+	if isBuy(side) {
+		return "BUY"
+	}
+	return "SELL"
+}
+
+// binanceTimeInForce returns tif, defaulting to GTC
+func binanceTimeInForce(tif TimeInForce) TimeInForce {
+	if tif == "" {
+		return TimeInForceGTC
+	}
+	return tif
+}
+
+// formatDecimal formats a quantity or price without exponent or trailing zeros
+func formatDecimal(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// parseDecimal parses a decimal string from the API, returning 0 when it is empty or invalid
+func parseDecimal(s string) float64 {
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return value
+}
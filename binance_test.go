@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestBinanceClient returns a spot client with test credentials talking to server
+func newTestBinanceClient(server *httptest.Server) *BinanceClient {
+	c := DefaultConfig()
+	c.Trading.APIKey = "test-key"
+	c.Trading.APISecret = "test-secret"
+	client := c.NewBinanceClient()
+	client.baseURL = server.URL
+	client.retryBackoff = time.Millisecond
+	return client
+}
+
+// validSignature reports whether the request's signature is the HMAC of its other parameters
+func validSignature(r *http.Request, secret string) bool {
+	query := r.URL.RawQuery
+	i := strings.LastIndex(query, "&signature=")
+	if i < 0 {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(query[:i]))
+	return hex.EncodeToString(mac.Sum(nil)) == query[i+len("&signature="):]
+}
+
+func TestBinanceClientTimestampsRequestsWithServerOffset(t *testing.T) {
+	offset := 3 * time.Second
+	var timestamp int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/time":
+			fmt.Fprintf(w, `{"serverTime":%d}`, time.Now().Add(offset).UnixMilli())
+		case "/api/v3/order":
+			if r.Header.Get("X-MBX-APIKEY") != "test-key" || !validSignature(r, "test-secret") {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"code":-1022,"msg":"Signature for this request is not valid."}`)
+				return
+			}
+			timestamp, _ = strconv.ParseInt(r.URL.Query().Get("timestamp"), 10, 64)
+			fmt.Fprint(w, `{"symbol":"BNBUSDT","orderId":42,"status":"FILLED","executedQty":"2","cummulativeQuoteQty":"601","transactTime":1700000000000,"fills":[{"commission":"0.001"}]}`)
+		}
+	}))
+	defer server.Close()
+	client := newTestBinanceClient(server)
+
+	if err := client.Clock().Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	fill, err := client.Submit(context.Background(), Order{Symbol: "BNBUSDT", Side: SideBuy, Quantity: 2, Price: 300, Type: OrderTypeMarket})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+
+	// The request is stamped with server time, not local time
+	drift := time.Duration(timestamp-time.Now().Add(offset).UnixMilli()) * time.Millisecond
+	if drift < -time.Second || drift > time.Second {
+		t.Errorf("request timestamp is %s from server time, want it corrected by the %s offset", drift, offset)
+	}
+	if fill.OrderID != "42" || fill.Quantity != 2 || fill.Price != 300.5 || fill.Fee != 0.001 {
+		t.Errorf("fill = %+v, want order 42 filling 2 at 300.5 with fee 0.001", fill)
+	}
+}
+
+func TestBinanceClientRejectsGrossClockSkew(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"serverTime":%d}`, time.Now().Add(time.Hour).UnixMilli())
+	}))
+	defer server.Close()
+	client := newTestBinanceClient(server)
+
+	if err := client.Clock().Sync(context.Background()); err == nil {
+		t.Fatal("expected an hour of clock skew to fail the sync")
+	}
+	if client.Clock().Offset() != 0 {
+		t.Errorf("offset = %s, want the rejected offset left unapplied", client.Clock().Offset())
+	}
+}
+
+func TestBinanceClientClassifiesErrorResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"code":-2010,"msg":"Account has insufficient balance for requested action."}`)
+	}))
+	defer server.Close()
+	client := newTestBinanceClient(server)
+
+	_, err := client.Submit(context.Background(), Order{Symbol: "BNBUSDT", Side: SideBuy, Quantity: 1, Type: OrderTypeMarket})
+	var exchangeErr *ExchangeError
+	if !errors.As(err, &exchangeErr) || !exchangeErr.Rejected() || exchangeErr.Code != -2010 {
+		t.Fatalf("err = %v, want a rejected exchange error with code -2010", err)
+	}
+}
+
+func TestBinanceClientRetriesReadsAndCountsWeight(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"symbol":"BNBUSDT","price":"612.5"}`)
+	}))
+	defer server.Close()
+	client := newTestBinanceClient(server)
+
+	price, err := client.Price(context.Background(), "BNBUSDT")
+	if err != nil {
+		t.Fatalf("Price: %v", err)
+	}
+	if price != 612.5 || requests != 2 {
+		t.Errorf("price = %f after %d requests, want 612.5 after one retry", price, requests)
+	}
+	if used := client.config.Trading.APIWeightLimit - client.limiter.RemainingWeight(); used != 2*binancePriceEndpoint.spotWeight {
+		t.Errorf("used weight = %d, want %d for two price requests", used, 2*binancePriceEndpoint.spotWeight)
+	}
+}
+
+func TestBinanceClientResyncsClockOnInvalidTimestamp(t *testing.T) {
+	orders := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/time":
+			fmt.Fprintf(w, `{"serverTime":%d}`, time.Now().UnixMilli())
+		case "/api/v3/order":
+			orders++
+			if orders == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"code":-1021,"msg":"Timestamp for this request is outside of the recvWindow."}`)
+				return
+			}
+			fmt.Fprint(w, `{"orderId":7,"executedQty":"1","cummulativeQuoteQty":"300"}`)
+		}
+	}))
+	defer server.Close()
+	client := newTestBinanceClient(server)
+
+	fill, err := client.Submit(context.Background(), Order{Symbol: "BNBUSDT", Side: SideSell, Quantity: 1, Type: OrderTypeMarket})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if orders != 2 || fill.OrderID != "7" {
+		t.Errorf("%d order requests, fill %+v; want the rejected order resent once after resyncing", orders, fill)
+	}
+}
+
+func TestBinanceClientSpotTotalEquityValuesHoldings(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/account":
+			fmt.Fprint(w, `{"balances":[{"asset":"USDT","free":"400","locked":"100"},{"asset":"BNB","free":"1.5","locked":"0.5"},{"asset":"DOGE","free":"1000","locked":"0"}]}`)
+		case "/api/v3/ticker/price":
+			fmt.Fprint(w, `{"symbol":"BNBUSDT","price":"250"}`)
+		}
+	}))
+	defer server.Close()
+	client := newTestBinanceClient(server)
+
+	equity, err := client.TotalEquity(context.Background())
+	if err != nil {
+		t.Fatalf("TotalEquity: %v", err)
+	}
+	// 500 USDT plus 2 BNB at 250; DOGE is not a configured pair
+	if equity != 1000 {
+		t.Errorf("TotalEquity = %f, want 1000", equity)
+	}
+}
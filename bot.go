@@ -0,0 +1,987 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Bot coordinates order execution, open positions, risk state, persistence and logging
+type Bot struct {
+	mu sync.Mutex
+	// closeMu serializes closes so one position is never closed twice at once, without holding
+	// mu across exchange calls
+	closeMu sync.Mutex
+	// copyMu serializes copies so each one's delay spaces its order from the previous copy and
+	// the position limit is checked against every earlier copy
+	copyMu sync.Mutex
+	// Active configuration, swapped by Reconfigure
+	config     atomic.Pointer[Config]
+	executor   OrderExecutor
+	logger     *Logger
+	state      BotStateStore
+	pairs      map[string]*pairRisk
+	daily      *DailyLossGuard
+	drawdown   *DrawdownMonitor
+	protector  *EquityProtector
+	cooldowns  *CooldownTracker
+	reentry    *ReEntryGate
+	softLoss   *SoftLossMonitor
+	positions  map[string]*Position
+	lastPrices map[string]float64
+	paused     bool
+	oco        *OCOExits
+	deadman    *DeadMansSwitch
+	improver   *EntryImprover
+	leaders    *LeaderScorer
+	capital    *CapitalBase
+	volume     *VolumeTracker
+	fees       *FeeLedger
+	limiter    *PositionLimiter
+	throttle   *CopyThrottle
+	filters    []CopyFilter
+	metrics    *Metrics
+	trades     TradeStore
+	watchdog   *LivenessWatchdog
+	notifier   *Notifier
+	// Notifications still being sent, awaited on shutdown, and the completion of the latest one,
+	// which the next waits for so they arrive in order
+	notifications    sync.WaitGroup
+	lastNotification chan struct{}
+	// Stop management of each open position, created when it is first managed
+	exits map[string]*ExitManager
+	// Chasers of resting entries with nothing filled, created when they are first chased
+	chasers map[string]*EntryChaser
+}
+
+// NewBot creates a bot and restores any state saved in store by a previous run. Logger and
+// store may be nil to disable logging and persistence.
+func NewBot(config *Config, executor OrderExecutor, logger *Logger, store StateStore) (*Bot, error) {
+	b := &Bot{
+		executor:   executor,
+		logger:     logger,
+		pairs:      make(map[string]*pairRisk),
+		daily:      config.NewPersistentDailyLossGuard(store),
+		drawdown:   config.NewDrawdownMonitor(),
+		protector:  config.NewEquityProtector(),
+		cooldowns:  config.NewCooldownTracker(),
+		reentry:    config.NewReEntryGate(),
+		leaders:    config.NewLeaderScorer(),
+		capital:    NewCapitalBase(config.FixedCapital),
+		volume:     NewVolumeTracker(),
+		fees:       config.NewFeeLedger(),
+		limiter:    config.NewPositionLimiter(),
+		throttle:   config.NewCopyThrottle(),
+		filters:    config.NewCopyFilters(),
+		notifier:   config.NewNotifier(),
+		exits:      make(map[string]*ExitManager),
+		chasers:    make(map[string]*EntryChaser),
+		positions:  make(map[string]*Position),
+		lastPrices: make(map[string]float64),
+	}
+	b.watchdog = config.NewLivenessWatchdog(time.Now(), b.notifier)
+	b.config.Store(config)
+	b.softLoss = config.NewSoftLossMonitor(func(positionID string, lossFraction float64) {
+		b.logf("⚠️  Position %s unrealized loss %.2f%% crossed the soft loss threshold, holding new entries in its symbol for review", positionID, lossFraction*100)
+	})
+	if executor, ok := executor.(*filteredExecutor); ok {
+		executor.account = b
+	}
+	if config.Logging.LogConfigOnStart {
+		b.logf("Effective configuration:\n%s", config.Summary())
+	}
+	if store != nil {
+		b.state = NewBotStateStore(store)
+		if err := b.restore(); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// restore loads the saved bot state, starting fresh when none exists
+func (b *Bot) restore() error {
+	state, err := b.state.Load()
+	if errors.Is(err, ErrStateNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error restoring bot state: %v", err)
+	}
+
+	for _, p := range state.OpenPositions {
+		b.initTierState(p)
+		b.positions[p.ID] = p
+	}
+	for symbol, losses := range state.PairConsecutiveLosses {
+		b.pairRisk(symbol).losses.Restore(losses)
+	}
+	b.drawdown.Restore(state.PeakEquity, state.PeakEquityTime)
+	b.capital.RecordRealizedPnL(state.RealizedPnL)
+	b.volume.Restore(state.DailyVolume)
+	b.logf("Restored %d open positions from saved state", len(state.OpenPositions))
+	return nil
+}
+
+// SaveState persists the open positions and risk state
+func (b *Bot) SaveState() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.saveState()
+}
+
+func (b *Bot) saveState() error {
+	if b.state == nil {
+		return nil
+	}
+	peak, peakTime := b.drawdown.Peak()
+	state := BotState{
+		OpenPositions:         b.openPositions(),
+		PairConsecutiveLosses: b.pairConsecutiveLosses(),
+		PeakEquity:            peak,
+		PeakEquityTime:        peakTime,
+		RealizedPnL:           b.capital.RealizedPnL(),
+		DailyVolume:           b.volume.Days(),
+	}
+	if err := b.state.Save(state); err != nil {
+		return fmt.Errorf("error saving bot state: %v", err)
+	}
+	return nil
+}
+
+// DailyLossGuard returns the bot's daily loss guard
+func (b *Bot) DailyLossGuard() *DailyLossGuard {
+	return b.daily
+}
+
+// DrawdownMonitor returns the bot's drawdown monitor
+func (b *Bot) DrawdownMonitor() *DrawdownMonitor {
+	return b.drawdown
+}
+
+// LeaderScorer returns the bot's leader performance scorer
+func (b *Bot) LeaderScorer() *LeaderScorer {
+	return b.leaders
+}
+
+// ScaleSignal returns the quantity copying signal at price, scaled by myEquity relative to the
+// leader's equity and by the leader's performance weight
+func (b *Bot) ScaleSignal(signal CopySignal, myEquity, price float64) float64 {
+	weight := b.leaders.WeightFor(signal.LeaderAddress)
+	return b.config.Load().ScaleLeaderTrade(signal.LeaderSize, signal.LeaderEquity, myEquity, price, weight)
+}
+
+// CapitalBase returns the capital base the bot sizes positions from
+func (b *Bot) CapitalBase() *CapitalBase {
+	return b.capital
+}
+
+// SizePosition returns the quantity of a new position in symbol, sized from the effective
+// capital of the capital base so realized profits compound, stay fixed or are swept per
+// CompoundingMode, and from the symbol's own trade outcomes
+func (b *Bot) SizePosition(symbol string, entryPrice, stopLossPrice float64, side string) float64 {
+	return b.config.Load().SizePosition(symbol, b.capital.EffectiveCapital(), entryPrice, stopLossPrice, side, b.WinRateTracker(symbol))
+}
+
+// VolumeTracker returns the bot's trailing 30-day trading volume
+func (b *Bot) VolumeTracker() *VolumeTracker {
+	return b.volume
+}
+
+// feeConfig returns the config whose fee tier is selected by the tracked 30-day volume
+func (b *Bot) feeConfig() *Config {
+	return b.config.Load().WithVolume(b.volume.Volume30d())
+}
+
+// FeeLedger returns the fees paid and maker rebates accrued by the bot's fills
+func (b *Bot) FeeLedger() *FeeLedger {
+	return b.fees
+}
+
+// submit submits an order through the executor, recording the attempt with the liveness
+// watchdog whether or not it succeeds and the fees of what filled. In observe mode a rejected
+// order is logged as the decision it stopped.
+func (b *Bot) submit(ctx context.Context, order Order) (Fill, error) {
+	b.watchdog.RecordActivity(time.Now())
+	fill, err := b.executor.Submit(ctx, order)
+	if err == nil {
+		b.recordFees(fill)
+	} else if observe := observer(b.executor); observe != nil {
+		observe.LogDecision(order.Symbol, fmt.Sprintf("would not %s %f at %f: %v", order.Side, order.Quantity, order.Price, err))
+	}
+	b.mu.Lock()
+	metrics := b.metrics
+	b.mu.Unlock()
+	if metrics != nil {
+		metrics.RecordOrder(order.Side, err)
+	}
+	return fill, err
+}
+
+// recordFees records the fee and any maker rebate of a fill with the fee ledger
+func (b *Bot) recordFees(fill Fill) {
+	if fill.Quantity > 0 {
+		b.fees.RecordFill(fill.Price*fill.Quantity, fill.Order.IsMaker())
+	}
+}
+
+// CheckLiveness flags the bot as degraded, sending a WARN notification, when no order has been
+// attempted for longer than LivenessTimeout while it is not paused
+func (b *Bot) CheckLiveness(ctx context.Context, now time.Time) bool {
+	return b.watchdog.Check(ctx, now, !b.Paused())
+}
+
+// Degraded reports whether the liveness watchdog flags the bot as degraded
+func (b *Bot) Degraded() bool {
+	return b.watchdog.Degraded()
+}
+
+// CooldownTracker returns the bot's re-entry cooldown tracker
+func (b *Bot) CooldownTracker() *CooldownTracker {
+	return b.cooldowns
+}
+
+// EnableOCOExits has the bot keep exits' OCO lists in step with its positions, re-placing
+// them after each partial close. Open positions are bracketed immediately.
+func (b *Bot) EnableOCOExits(ctx context.Context, exits *OCOExits) error {
+	b.mu.Lock()
+	b.oco = exits
+	b.mu.Unlock()
+	return exits.Sync(ctx, b.OpenPositions())
+}
+
+// EnableTradeHistory has the bot record every closed trade, including partial closes, in store
+func (b *Bot) EnableTradeHistory(store TradeStore) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trades = store
+}
+
+// EnableEntryImprovement has the bot enter copies through an EntryImprover, resting each
+// entry OffsetPercentage better than the leader's price and tracking it through orders
+func (b *Bot) EnableEntryImprovement(orders OrderManager) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.improver = b.config.Load().NewEntryImprover(OrderExecutorFunc(b.submit), orders)
+}
+
+// Pause stops new positions from opening; open positions keep being managed
+func (b *Bot) Pause() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.paused {
+		b.paused = true
+		b.logf("Paused: no new positions will open until resumed")
+	}
+}
+
+// Resume lets new positions open again
+func (b *Bot) Resume() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.paused {
+		b.paused = false
+		b.logf("Resumed: new positions may open")
+	}
+}
+
+// Paused reports whether new positions are paused
+func (b *Bot) Paused() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.paused
+}
+
+// AllowEntry reports whether signal may open a new position. Every signal is refused while
+// paused, while equity protection is engaged and past the daily loss or drawdown limit; so are
+// signals from leaders not followed, in symbols excluded from copying, in pairs paused after
+// consecutive losses, in symbols still cooling down after a close or with a position held past
+// its soft loss threshold, from stopped-out setups without a fresh signal, copies that would take
+// the exposure to their base asset past MaxBaseAssetExposure or the total exposure past
+// MaxTotalExposurePercentage of equity and signals the entry filters reject.
+func (b *Bot) AllowEntry(signal CopySignal) bool {
+	if b.Paused() {
+		b.logf("Skipping copy of %s %s from %s: bot is paused", signal.Side, signal.Symbol, signal.LeaderAddress)
+		return false
+	}
+	if paused, resumeAt := b.LossTracker(signal.Symbol).ShouldPause(); paused {
+		b.logf("Skipping copy of %s %s from %s: %s is paused after consecutive losses until %s", signal.Side, signal.Symbol, signal.LeaderAddress, signal.Symbol, resumeAt.Format(time.RFC3339))
+		return false
+	}
+	if remaining := b.cooldowns.Remaining(signal.Symbol, time.Now()); remaining > 0 {
+		b.logf("Skipping copy of %s %s from %s: %s closed recently, re-entry cooldown ends in %s", signal.Side, signal.Symbol, signal.LeaderAddress, signal.Symbol, remaining.Round(time.Second))
+		return false
+	}
+	if held := b.heldForReview(signal.Symbol); held != "" {
+		b.logf("Skipping copy of %s %s from %s: position %s is held for review past its soft loss threshold", signal.Side, signal.Symbol, signal.LeaderAddress, held)
+		return false
+	}
+	if !b.reentry.CanEnter(reEntrySetupID(signal.LeaderAddress, signal.Symbol, signal.Side)) {
+		b.logf("Skipping copy of %s %s from %s: setup was stopped out, re-entry needs a fresh signal within %d re-entries", signal.Side, signal.Symbol, signal.LeaderAddress, b.config.Load().RiskManagement.MaxReEntries)
+		return false
+	}
+	if !b.config.Load().IsLeaderFollowed(signal.LeaderAddress) {
+		b.logf("Skipping copy of %s %s from %s: leader is not followed", signal.Side, signal.Symbol, signal.LeaderAddress)
+		return false
+	}
+	if !b.config.Load().IsSymbolAllowed(signal.Symbol) {
+		b.logf("Skipping copy of %s %s from %s: symbol is not allowed", signal.Side, signal.Symbol, signal.LeaderAddress)
+		return false
+	}
+	if notional := b.copyNotional(signal); notional > 0 {
+		asset, equity := BaseAsset(signal.Symbol), b.Equity()
+		exposure := b.OpenExposure()
+		if !b.config.Load().CanOpenBaseAsset(exposure, signal.Symbol, notional, equity) {
+			b.logf("Skipping copy of %s %s from %s: %f more %s exposure on %f already open exceeds %.2f%% of equity %f", signal.Side, signal.Symbol, signal.LeaderAddress, notional, asset, exposure.BaseAssetExposure(asset), b.config.Load().RiskManagement.MaxBaseAssetExposure*100, equity)
+			return false
+		}
+		if !b.config.Load().CanOpen(exposure, notional, equity) {
+			b.logf("Skipping copy of %s %s from %s: %f more exposure on %f already open exceeds %.2f%% of equity %f", signal.Side, signal.Symbol, signal.LeaderAddress, notional, exposure.TotalExposure(), b.config.Load().RiskManagement.MaxTotalExposurePercentage*100, equity)
+			return false
+		}
+	}
+	if ok, reason := b.protector.Check(b.RiskEquity()); !ok {
+		b.logf("Skipping copy of %s %s from %s: equity protection: %s", signal.Side, signal.Symbol, signal.LeaderAddress, reason)
+		return false
+	}
+	if ok, reason := b.CheckEquityGates(time.Now()); !ok {
+		b.logf("Skipping copy of %s %s from %s: %s", signal.Side, signal.Symbol, signal.LeaderAddress, reason)
+		return false
+	}
+	b.mu.Lock()
+	filters := b.filters
+	b.mu.Unlock()
+	if ok, reason := AllowCopy(filters, signal); !ok {
+		b.logf("Skipping copy of %s %s from %s: %s", signal.Side, signal.Symbol, signal.LeaderAddress, reason)
+		return false
+	}
+	return true
+}
+
+// heldForReview returns the ID of an open position in symbol held for review after crossing
+// its soft loss threshold, or an empty string when there is none
+func (b *Bot) heldForReview(symbol string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, p := range b.openPositions() {
+		if p.Symbol == symbol && b.softLoss.Alerted(p.ID) {
+			return p.ID
+		}
+	}
+	return ""
+}
+
+// HandleSignal copies a leader signal. It is skipped unless AllowEntry admits it, a position
+// slot is free, queueing it under the queue policy, and the market is still within
+// MaxCopyPriceDeviation of the leader's fill. The copy is sized by ScaleSignal from the
+// effective capital and entered with the configured order type. It returns the opened
+// position, or nil when the signal was skipped.
+func (b *Bot) HandleSignal(ctx context.Context, signal CopySignal) (*Position, error) {
+	signal.Symbol = strings.ToUpper(signal.Symbol)
+	if !isKnownSide(signal.Side) {
+		return nil, fmt.Errorf("signal for %s has unknown side %q", signal.Symbol, signal.Side)
+	}
+	b.copyMu.Lock()
+	defer b.copyMu.Unlock()
+	if signal.ReceivedAt.IsZero() {
+		signal.ReceivedAt = time.Now()
+	}
+	setup := reEntrySetupID(signal.LeaderAddress, signal.Symbol, signal.Side)
+	b.reentry.RecordSignalAt(setup, signal.ReceivedAt)
+	if !b.AllowEntry(signal) {
+		return nil, nil
+	}
+	if open := len(b.OpenPositions()); !b.limiter.Admit(signal, open) {
+		b.logf("Holding back copy of %s %s from %s: %d positions already open (%s policy)", signal.Side, signal.Symbol, signal.LeaderAddress, open, b.config.Load().RiskManagement.MaxPositionsPolicy)
+		return nil, nil
+	}
+	// Mirror the leader after the copy delay and within MaxCopiesPerMinute, at the price then
+	if err := b.config.Load().WaitCopyDelay(ctx); err != nil {
+		return nil, fmt.Errorf("copy of %s %s from %s abandoned: %v", signal.Side, signal.Symbol, signal.LeaderAddress, err)
+	}
+	if err := b.throttle.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("copy of %s %s from %s abandoned: %v", signal.Side, signal.Symbol, signal.LeaderAddress, err)
+	}
+	price, ok := b.LastPrice(signal.Symbol)
+	if !ok {
+		price = signal.Price
+	}
+	if !b.config.Load().WithinCopyPriceDeviation(signal, price) {
+		return nil, nil
+	}
+	quantity := b.ScaleSignal(signal, b.capital.EffectiveCapital(), price)
+	if quantity <= 0 {
+		b.logf("Skipping copy of %s %s from %s: scaled size is below the minimum", signal.Side, signal.Symbol, signal.LeaderAddress)
+		return nil, nil
+	}
+
+	side, orderSide := SideShort, SideSell
+	if isBuy(signal.Side) {
+		side, orderSide = SideLong, SideBuy
+	}
+	if config := b.config.Load(); config.MultiTier.Enabled {
+		if _, err := config.MultiTier.NewTierState(price, side); err != nil {
+			b.logf("Skipping copy of %s %s from %s: %v", signal.Side, signal.Symbol, signal.LeaderAddress, err)
+			return nil, nil
+		}
+	}
+	fills, improved, err := b.enter(ctx, signal.Symbol, orderSide, quantity, price, signal.Price)
+	if err != nil {
+		err = fmt.Errorf("error copying %s %s from %s: %v", signal.Side, signal.Symbol, signal.LeaderAddress, err)
+	}
+	if len(fills) == 0 {
+		return nil, err
+	}
+	fill := fills[0]
+	if len(fills) == 1 && fill.Quantity <= 0 && fill.OrderID == "" {
+		// Observed only, nothing rests on the book
+		return nil, err
+	}
+
+	p := NewPositionFromFill(fmt.Sprintf("%s-%d", signal.Symbol, time.Now().UnixNano()), side, fill)
+	notional := fill.Price * fill.Quantity
+	for _, more := range fills[1:] {
+		p.ApplyFill(more)
+		notional += more.Price * more.Quantity
+	}
+	if improved {
+		// The improver has cancelled whatever it did not fill
+		p.Quantity = p.FilledQuantity
+		if p.FilledQuantity <= 0 {
+			return nil, err
+		}
+	}
+	if p.EntryPrice <= 0 {
+		// A resting entry with nothing filled yet is anchored to its limit price until it fills
+		p.EntryPrice = fill.Order.Price
+	}
+	p.LeaderAddress = signal.LeaderAddress
+
+	b.mu.Lock()
+	b.initTierState(p)
+	b.positions[p.ID] = p
+	b.volume.Record(notional)
+	saveErr := b.saveState()
+	b.mu.Unlock()
+	b.reentry.RecordEntry(setup)
+	b.logf("Opened position %s: %s %f of %f %s at %f copying %s", p.ID, p.Side, p.FilledQuantity, p.Quantity, p.Symbol, p.EntryPrice, signal.LeaderAddress)
+	b.notify(fmt.Sprintf("Opened %s %s %f at %f copying %s", p.Symbol, p.Side, p.FilledQuantity, p.EntryPrice, signal.LeaderAddress))
+	b.placeExits(ctx, p.ID)
+	return p, errors.Join(err, saveErr)
+}
+
+// enter submits the entry of a copy at price and returns its fills, reporting whether it went
+// through the entry improver. An improved entry rests better than leaderPrice and is complete
+// once enter returns; it may return fills alongside an error.
+func (b *Bot) enter(ctx context.Context, symbol, side string, quantity, price, leaderPrice float64) ([]Fill, bool, error) {
+	b.mu.Lock()
+	improver := b.improver
+	b.mu.Unlock()
+	if improver != nil {
+		fills, err := improver.Enter(ctx, symbol, side, quantity, leaderPrice)
+		return fills, true, err
+	}
+	fill, err := b.submit(ctx, b.config.Load().EntryOrder(symbol, side, quantity, price))
+	if err != nil {
+		return nil, false, err
+	}
+	return []Fill{fill}, false, nil
+}
+
+// TrackPosition adds an open position, snapshotting the profit tiers it will close at
+func (b *Bot) TrackPosition(p *Position) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.initTierState(p)
+	b.positions[p.ID] = p
+}
+
+// initTierState snapshots the configured tiers for a position without tier state, such as a
+// new position or one saved before tiers were tracked. A position whose absolute tier targets
+// are on the wrong side of its entry is left to its stop, and the error logged.
+func (b *Bot) initTierState(p *Position) {
+	config := b.config.Load()
+	if p.TierState.Tiers != nil || !config.MultiTier.Enabled {
+		return
+	}
+	state, err := config.MultiTier.NewTierState(p.EntryPrice, p.Side)
+	if err != nil {
+		b.logf("Position %s (%s %s) has no profit tiers: %v", p.ID, p.Symbol, p.Side, err)
+		return
+	}
+	p.TierState = state
+}
+
+// UntrackPosition removes a closed position
+func (b *Bot) UntrackPosition(id string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.positions, id)
+	delete(b.exits, id)
+	delete(b.chasers, id)
+	b.softLoss.Reset(id)
+}
+
+// OpenPositions returns the open positions ordered by opening time
+func (b *Bot) OpenPositions() []*Position {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.openPositions()
+}
+
+func (b *Bot) openPositions() []*Position {
+	positions := make([]*Position, 0, len(b.positions))
+	for _, p := range b.positions {
+		positions = append(positions, p)
+	}
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].OpenedAt.Before(positions[j].OpenedAt)
+	})
+	return positions
+}
+
+// ApplyFill adds a fill of a tracked position's entry order
+func (b *Bot) ApplyFill(id string, fill Fill) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p, ok := b.positions[id]
+	if !ok {
+		return fmt.Errorf("no open position %s", id)
+	}
+	p.ApplyFill(fill)
+	b.volume.Record(fill.Price * fill.Quantity)
+	return nil
+}
+
+// CancelUnfilledEntries cancels the unfilled remainder of entry orders open longer than
+// OrderTimeout, shrinking each position to its filled quantity. Positions with nothing
+// filled are dropped. The cancels are sent without holding mu.
+func (b *Bot) CancelUnfilledEntries(ctx context.Context, canceler OrderCanceler) error {
+	type timedOutEntry struct {
+		id, symbol, orderID string
+	}
+	timeout := time.Duration(b.config.Load().Trading.OrderTimeout) * time.Second
+	b.mu.Lock()
+	var due []timedOutEntry
+	for _, p := range b.openPositions() {
+		if !p.FullyFilled() && time.Since(p.OpenedAt) >= timeout {
+			due = append(due, timedOutEntry{id: p.ID, symbol: p.Symbol, orderID: p.EntryOrderID})
+		}
+	}
+	b.mu.Unlock()
+
+	var errs []error
+	for _, entry := range due {
+		if entry.orderID != "" {
+			if err := canceler.CancelOrder(ctx, entry.symbol, entry.orderID); err != nil {
+				errs = append(errs, fmt.Errorf("error cancelling entry order of position %s: %v", entry.id, err))
+				continue
+			}
+		}
+
+		b.mu.Lock()
+		p, ok := b.positions[entry.id]
+		if ok {
+			b.logf("Cancelled unfilled %f of position %s (%s), %f filled", p.UnfilledQuantity(), p.ID, p.Symbol, p.FilledQuantity)
+			p.Quantity = p.FilledQuantity
+			delete(b.chasers, p.ID)
+			if p.FilledQuantity == 0 {
+				delete(b.positions, p.ID)
+			}
+		}
+		b.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// CloseTimedOutPositions market-closes every position held longer than MaxHoldTime when
+// CloseOnTimeout is set, whatever tiers it reached, and returns the closed positions
+func (b *Bot) CloseTimedOutPositions(ctx context.Context, now time.Time) ([]*Position, error) {
+	b.closeMu.Lock()
+	defer b.closeMu.Unlock()
+
+	b.mu.Lock()
+	var due []*Position
+	for _, p := range b.openPositions() {
+		if b.config.Load().NewPositionTimer(p.OpenedAt).ShouldClose(now) && p.FilledQuantity > 0 {
+			due = append(due, p)
+		}
+	}
+	b.mu.Unlock()
+
+	var closed []*Position
+	var errs []error
+	for _, p := range due {
+		fill, err := b.submitClose(ctx, p.ID, math.Inf(1))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error closing timed out position %s: %v", p.ID, err))
+			continue
+		}
+		if fill.Quantity > 0 {
+			closed = append(closed, p)
+			b.logf("Closed %f of position %s (%s %s) after reaching the max hold time", fill.Quantity, p.ID, p.Symbol, p.Side)
+		}
+	}
+	return closed, errors.Join(errs...)
+}
+
+// UpdatePrice records the latest market price of a symbol
+func (b *Bot) UpdatePrice(symbol string, price float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastPrices[symbol] = price
+}
+
+// LastPrice returns the latest recorded market price of a symbol
+func (b *Bot) LastPrice(symbol string) (float64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	price, ok := b.lastPrices[symbol]
+	return price, ok
+}
+
+// closeOrder builds the market order closing quantity of p at the last known price, falling
+// back to its entry price, capped at the filled quantity. Must be called with mu held.
+func (b *Bot) closeOrder(p *Position, quantity float64) (Order, error) {
+	quantity = math.Min(quantity, p.FilledQuantity)
+	if quantity <= 0 {
+		return Order{}, fmt.Errorf("position %s has no filled quantity to close", p.ID)
+	}
+	price, ok := b.lastPrices[p.Symbol]
+	if !ok {
+		price = p.EntryPrice
+	}
+	side := SideSell
+	if !isBuy(p.Side) {
+		side = SideBuy
+	}
+	return b.config.Load().ReduceOnlyExit(Order{Symbol: p.Symbol, Side: side, Quantity: quantity, Price: price, Type: OrderTypeMarket, Exit: true}, p.FilledQuantity)
+}
+
+// submitClose submits the market order closing quantity of position id, capped at its filled
+// quantity, and shrinks the position by the quantity the exchange reports filled, untracking it
+// once nothing is left. An order accepted without a fill leaves the position unchanged. The
+// position's OCO exit, if any, is re-placed for what remains. Must be called with closeMu held
+// and mu not held; the order is submitted without holding mu.
+func (b *Bot) submitClose(ctx context.Context, id string, quantity float64) (Fill, error) {
+	b.mu.Lock()
+	p, ok := b.positions[id]
+	if !ok {
+		b.mu.Unlock()
+		return Fill{}, fmt.Errorf("no open position %s", id)
+	}
+	order, err := b.closeOrder(p, quantity)
+	deadman, oco := b.deadman, b.oco
+	b.mu.Unlock()
+	if err != nil {
+		return Fill{}, err
+	}
+
+	// Resting exchange exits lock the balance on spot and could fill alongside the close on
+	// futures, so they are cancelled first and re-placed for whatever remains
+	if deadman != nil {
+		if err := deadman.Release(ctx, id); err != nil {
+			return Fill{}, err
+		}
+	}
+	if oco != nil {
+		if err := oco.Cancel(ctx, id); err != nil {
+			return Fill{}, err
+		}
+	}
+	fill, err := b.submit(ctx, order)
+	if err != nil {
+		b.placeExits(ctx, id)
+		return Fill{}, err
+	}
+	if fill.Quantity <= 0 {
+		b.logf("Close order %s of position %s accepted without a fill", fill.OrderID, p.ID)
+	}
+	b.applyCloseFill(p, fill)
+	b.placeExits(ctx, id)
+	return fill, nil
+}
+
+// placeExits places or moves the exchange exits of an open position when enabled: the
+// protective stop at its current stop, or the OCO exit at its stop and take profit prices.
+// Failures are logged.
+func (b *Bot) placeExits(ctx context.Context, id string) {
+	b.mu.Lock()
+	p, ok := b.positions[id]
+	if !ok {
+		b.mu.Unlock()
+		return
+	}
+	deadman, oco, snapshot := b.deadman, b.oco, *p
+	var stop float64
+	if exits, ok := b.exits[id]; ok {
+		stop = exits.StopPrice()
+	}
+	b.mu.Unlock()
+
+	if deadman != nil {
+		if err := deadman.Protect(ctx, &snapshot, stop); err != nil {
+			b.logf("Error protecting position %s: %v", id, err)
+		}
+	}
+	if oco != nil {
+		if err := oco.Place(ctx, &snapshot); err != nil {
+			b.logf("Error placing OCO exit of position %s: %v", id, err)
+		}
+	}
+}
+
+// bookExitFills books the fills of exchange exits that executed without the bot, by position
+// ID, as closes of their positions and re-places the exits of what remains. Must be called with
+// closeMu held.
+func (b *Bot) bookExitFills(ctx context.Context, fills map[string]Fill, exit string) error {
+	if len(fills) == 0 {
+		return nil
+	}
+	for id, fill := range fills {
+		b.mu.Lock()
+		p, ok := b.positions[id]
+		b.mu.Unlock()
+		if !ok {
+			continue
+		}
+		b.recordFees(fill)
+		b.applyCloseFill(p, fill)
+		b.logf("The %s of position %s (%s %s) filled %f at %f", exit, id, p.Symbol, p.Side, fill.Quantity, fill.Price)
+		b.placeExits(ctx, id)
+	}
+	return b.SaveState()
+}
+
+// applyCloseFill shrinks p by a close fill, dropping it and starting its symbol's re-entry
+// cooldown once nothing remains, and books the realized profit. Must be called with mu not held.
+func (b *Bot) applyCloseFill(p *Position, fill Fill) {
+	b.mu.Lock()
+	filled := fill.Quantity
+	p.FilledQuantity = math.Max(p.FilledQuantity-filled, 0)
+	p.Quantity = math.Max(p.Quantity-filled, p.FilledQuantity)
+	if p.FilledQuantity <= 0 {
+		delete(b.positions, p.ID)
+		delete(b.exits, p.ID)
+		b.softLoss.Reset(p.ID)
+		b.cooldowns.RecordClose(p.Symbol, time.Now())
+	}
+	remaining := *p
+	b.mu.Unlock()
+
+	if filled > 0 {
+		b.recordClose(&remaining, fill)
+	}
+}
+
+// recordClose books the realized profit of a close fill of p against the capital base, the
+// risk state of its pair and the leader it copies
+func (b *Bot) recordClose(p *Position, fill Fill) {
+	price := fill.Price
+	if price <= 0 {
+		price = fill.Order.Price
+	}
+	profit := b.feeConfig().RealizedProfit(p, price, fill.Quantity)
+	b.volume.Record(price * fill.Quantity)
+	b.capital.RecordRealizedPnL(profit)
+	// Closes move the equity the protection latches on, so it engages or recovers with them
+	if ok, reason := b.protector.Check(b.RiskEquity()); !ok {
+		b.logf("🛡️  Equity protection holding new entries after closing position %s: %s", p.ID, reason)
+	}
+	b.mu.Lock()
+	pair := b.pairRisk(p.Symbol)
+	metrics, trades := b.metrics, b.trades
+	b.mu.Unlock()
+	if metrics != nil {
+		metrics.RecordTrade(profit)
+	}
+	if trades != nil {
+		move := price - p.EntryPrice
+		if !isBuy(p.Side) {
+			move = -move
+		}
+		trade := TradeRecord{
+			Symbol:        p.Symbol,
+			Side:          p.Side,
+			EntryPrice:    p.EntryPrice,
+			ExitPrice:     price,
+			Quantity:      fill.Quantity,
+			Fees:          move*fill.Quantity - profit,
+			NetProfit:     profit,
+			OpenedAt:      p.OpenedAt,
+			ClosedAt:      time.Now(),
+			LeaderAddress: p.LeaderAddress,
+		}
+		if _, err := trades.RecordTrade(trade); err != nil {
+			b.logf("Error recording closed trade of position %s: %v", p.ID, err)
+		}
+	}
+	pair.losses.RecordTrade(profit)
+	pair.outcomes.RecordTrade(profit)
+	if p.LeaderAddress != "" {
+		b.leaders.RecordLeaderTrade(p.LeaderAddress, profit)
+	}
+	b.notify(fmt.Sprintf("Closed %s %s %f at %f, net profit %f", p.Symbol, p.Side, fill.Quantity, price, profit))
+}
+
+// notify sends message through the webhook notifier in the background, after every earlier
+// message, so a slow webhook never holds up an open or a close. Must be called with mu not held.
+func (b *Bot) notify(message string) {
+	done := make(chan struct{})
+	b.mu.Lock()
+	notifier, previous := b.notifier, b.lastNotification
+	b.lastNotification = done
+	b.mu.Unlock()
+	b.notifications.Add(1)
+	go func() {
+		defer b.notifications.Done()
+		defer close(done)
+		if previous != nil {
+			<-previous
+		}
+		if err := notifier.Send(context.Background(), message); err != nil {
+			b.logf("Error sending notification: %v", err)
+		}
+	}()
+}
+
+// ClosePosition market-closes quantity of an open position, such as a profit tier
+func (b *Bot) ClosePosition(ctx context.Context, id string, quantity float64) (Fill, error) {
+	b.closeMu.Lock()
+	defer b.closeMu.Unlock()
+	fill, err := b.submitClose(ctx, id, quantity)
+	if err != nil {
+		return Fill{}, fmt.Errorf("error closing position %s: %v", id, err)
+	}
+	return fill, nil
+}
+
+// ExecuteNextTier closes the share of the next profit tier of a position reached at price,
+// marking the tier executed and saving state so it never fires twice. A position whose absolute
+// take profit price is reached closes in full instead. A close accepted without a fill leaves
+// the tier pending. It reports whether a tier or the take profit fired.
+func (b *Bot) ExecuteNextTier(ctx context.Context, id string, price float64) (bool, error) {
+	b.closeMu.Lock()
+	defer b.closeMu.Unlock()
+
+	b.mu.Lock()
+	p, ok := b.positions[id]
+	if !ok {
+		b.mu.Unlock()
+		return false, fmt.Errorf("no open position %s", id)
+	}
+	takeProfit := p.AbsoluteTakeProfitPrice
+	if takeProfit > 0 && ((isLong(p.Side) && price >= takeProfit) || (!isLong(p.Side) && price <= takeProfit)) {
+		b.mu.Unlock()
+		fill, err := b.submitClose(ctx, id, math.Inf(1))
+		if err != nil {
+			return false, fmt.Errorf("error closing position %s at its take profit %f: %v", id, takeProfit, err)
+		}
+		if fill.Quantity <= 0 {
+			return false, nil
+		}
+		b.logf("Position %s (%s) closed %f at its take profit %f", id, p.Symbol, fill.Quantity, takeProfit)
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return true, b.saveState()
+	}
+	profitPct := -unrealizedLossFraction(p.EntryPrice, price, p.Side) * 100
+	tier, index, due := p.TierState.NextTier(profitPct)
+	var quantity float64
+	if due {
+		quantity = p.TierState.CloseQuantity(index, p.FilledQuantity)
+	}
+	b.mu.Unlock()
+	if !due {
+		return false, nil
+	}
+
+	fill, err := b.submitClose(ctx, id, quantity)
+	if err != nil {
+		return false, fmt.Errorf("error closing tier %d of position %s: %v", index, id, err)
+	}
+	if fill.Quantity <= 0 {
+		return false, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	p.TierState.MarkExecuted(index)
+	b.logf("Tier %d of position %s (%s) closed %f at %.2f%% profit", index, id, p.Symbol, fill.Quantity, tier.ProfitPercentage)
+	return true, b.saveState()
+}
+
+// Shutdown stops the bot, optionally market-closing every open position, then persists
+// state and flushes logs. Positions shrink by the quantity each close actually filled. It stops
+// closing positions once ctx is done and returns the positions that remain open.
+func (b *Bot) Shutdown(ctx context.Context, closePositions bool) ([]*Position, error) {
+	b.closeMu.Lock()
+	defer b.closeMu.Unlock()
+
+	var errs []error
+	if closePositions {
+		// Snapshot the positions under the lock; the closes are submitted without it
+		type openPosition struct {
+			id, symbol, side string
+			quantity         float64
+		}
+		b.mu.Lock()
+		var open []openPosition
+		for _, p := range b.openPositions() {
+			open = append(open, openPosition{id: p.ID, symbol: p.Symbol, side: p.Side, quantity: p.FilledQuantity})
+		}
+		b.mu.Unlock()
+
+		for _, p := range open {
+			if ctx.Err() != nil {
+				errs = append(errs, fmt.Errorf("shutdown deadline reached with positions open: %v", ctx.Err()))
+				break
+			}
+			if p.quantity <= 0 {
+				continue
+			}
+			fill, err := b.submitClose(ctx, p.id, p.quantity)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error closing position %s: %v", p.id, err))
+				continue
+			}
+			if fill.Quantity < p.quantity*(1-tierPercentageEpsilon) {
+				errs = append(errs, fmt.Errorf("close of position %s filled %f of %f on shutdown", p.id, fill.Quantity, p.quantity))
+				continue
+			}
+			b.logf("Closed position %s (%s %s %f) on shutdown", p.id, p.symbol, p.side, fill.Quantity)
+		}
+	}
+
+	b.mu.Lock()
+	remaining := b.openPositions()
+	err := b.saveState()
+	b.mu.Unlock()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	b.notifications.Wait()
+	b.logf("Shutdown complete with %d open positions; fees paid %f, maker rebates %f, net fees %f",
+		len(remaining), b.fees.Fees(), b.fees.Rebates(), b.fees.NetFeesAfterRebates())
+
+	if b.logger != nil {
+		if err := b.logger.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("error closing logger: %v", err))
+		}
+	}
+
+	return remaining, errors.Join(errs...)
+}
+
+// logf logs an informational message when a logger is configured
+func (b *Bot) logf(format string, args ...interface{}) {
+	if b.logger != nil {
+		b.logger.Info(format, args...)
+	}
+}
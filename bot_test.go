@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// fakeExecutor records submitted orders and fills each with fillRatio of its quantity
+type fakeExecutor struct {
+	mu        sync.Mutex
+	orders    []Order
+	fillRatio float64
+}
+
+func (e *fakeExecutor) Submit(ctx context.Context, order Order) (Fill, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.orders = append(e.orders, order)
+	return Fill{OrderID: "1", Order: order, Price: order.Price, Quantity: order.Quantity * e.fillRatio}, nil
+}
+
+func newTestBot(t *testing.T, executor OrderExecutor) *Bot {
+	t.Helper()
+	c := DefaultConfig()
+	c.Logging.LogConfigOnStart = false
+	c.CopyTrading.CopyDelay = 0
+	c.CopyTrading.CopyDelayJitter = 0
+	// A test copy of 1 BNB at 300 is 30% of the default capital, past the default base asset cap
+	c.RiskManagement.MaxBaseAssetExposure = 1
+	bot, err := NewBot(c, executor, nil, nil)
+	if err != nil {
+		t.Fatalf("NewBot: %v", err)
+	}
+	return bot
+}
+
+func TestClosePositionReducesByReportedFill(t *testing.T) {
+	executor := &fakeExecutor{fillRatio: 0.5}
+	bot := newTestBot(t, executor)
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 4, FilledQuantity: 4})
+
+	if _, err := bot.ClosePosition(context.Background(), "p", 4); err != nil {
+		t.Fatalf("ClosePosition: %v", err)
+	}
+	positions := bot.OpenPositions()
+	if len(positions) != 1 || positions[0].FilledQuantity != 2 {
+		t.Fatalf("after a half fill got %+v, want 2 left open", positions)
+	}
+	if !executor.orders[0].ReduceOnly {
+		t.Error("close order is not reduce-only")
+	}
+}
+
+func TestClosePositionIgnoresZeroFill(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 0})
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 4, FilledQuantity: 4})
+
+	if _, err := bot.ClosePosition(context.Background(), "p", 4); err != nil {
+		t.Fatalf("ClosePosition: %v", err)
+	}
+	positions := bot.OpenPositions()
+	if len(positions) != 1 || positions[0].FilledQuantity != 4 {
+		t.Fatalf("an unfilled close changed the position: %+v", positions)
+	}
+}
+
+func TestClosePositionFullFillUntracks(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideShort, EntryPrice: 100, Quantity: 4, FilledQuantity: 4})
+
+	if _, err := bot.ClosePosition(context.Background(), "p", 4); err != nil {
+		t.Fatalf("ClosePosition: %v", err)
+	}
+	if positions := bot.OpenPositions(); len(positions) != 0 {
+		t.Fatalf("fully closed position still open: %+v", positions)
+	}
+}
+
+// lockCheckingExecutor fails the test when the bot's state lock is held during Submit
+type lockCheckingExecutor struct {
+	t   *testing.T
+	bot *Bot
+}
+
+func (e *lockCheckingExecutor) Submit(ctx context.Context, order Order) (Fill, error) {
+	if !e.bot.mu.TryLock() {
+		e.t.Error("bot state lock held while submitting an order")
+	} else {
+		e.bot.mu.Unlock()
+	}
+	return Fill{Order: order, Price: order.Price, Quantity: order.Quantity}, nil
+}
+
+func newTierBot(t *testing.T, executor OrderExecutor) *Bot {
+	t.Helper()
+	bot := newTestBot(t, executor)
+	bot.config.Load().MultiTier.Enabled = true
+	bot.config.Load().MultiTier.Tiers = []TierProfit{
+		{ProfitPercentage: 1, ClosePercentage: 0.5, Enabled: true},
+		{ProfitPercentage: 2, ClosePercentage: 0.5, Enabled: true},
+	}
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 4, FilledQuantity: 4})
+	return bot
+}
+
+func TestExecuteNextTierSubmitsWithoutHoldingLock(t *testing.T) {
+	executor := &lockCheckingExecutor{t: t}
+	bot := newTierBot(t, executor)
+	executor.bot = bot
+
+	fired, err := bot.ExecuteNextTier(context.Background(), "p", 101)
+	if err != nil || !fired {
+		t.Fatalf("ExecuteNextTier = %v, %v; want the first tier to fire", fired, err)
+	}
+	if p := bot.OpenPositions()[0]; p.FilledQuantity != 2 || !p.TierState.Executed[0] {
+		t.Errorf("after the first tier got %+v", p)
+	}
+}
+
+func TestExecuteNextTierKeepsTierPendingWithoutFill(t *testing.T) {
+	bot := newTierBot(t, &fakeExecutor{fillRatio: 0})
+
+	fired, err := bot.ExecuteNextTier(context.Background(), "p", 101)
+	if err != nil || fired {
+		t.Fatalf("ExecuteNextTier = %v, %v; want no tier fired", fired, err)
+	}
+	if p := bot.OpenPositions()[0]; p.TierState.Executed[0] || p.FilledQuantity != 4 {
+		t.Errorf("an unfilled tier close changed the position: %+v", p)
+	}
+}
+
+func TestShutdownReconcilesWithActualFills(t *testing.T) {
+	executor := &lockCheckingExecutor{t: t}
+	bot := newTestBot(t, executor)
+	executor.bot = bot
+	bot.TrackPosition(&Position{ID: "full", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 1, FilledQuantity: 1})
+
+	remaining, err := bot.Shutdown(context.Background(), true)
+	if err != nil || len(remaining) != 0 {
+		t.Fatalf("Shutdown = %+v, %v; want everything closed", remaining, err)
+	}
+
+	partial := newTestBot(t, &fakeExecutor{fillRatio: 0.5})
+	partial.TrackPosition(&Position{ID: "half", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 2, FilledQuantity: 2})
+	remaining, err = partial.Shutdown(context.Background(), true)
+	if err == nil {
+		t.Error("expected an error for a partially filled close")
+	}
+	if len(remaining) != 1 || remaining[0].FilledQuantity != 1 {
+		t.Errorf("remaining = %+v, want the unfilled half still open", remaining)
+	}
+}
+
+func TestBotRestoresStateAfterRestart(t *testing.T) {
+	store, err := NewFileStateStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStateStore: %v", err)
+	}
+	c := DefaultConfig()
+	c.Logging.LogConfigOnStart = false
+	bot, err := NewBot(c, &fakeExecutor{fillRatio: 1}, nil, store)
+	if err != nil {
+		t.Fatalf("NewBot: %v", err)
+	}
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 2, FilledQuantity: 1})
+	bot.LossTracker("BNBUSDT").RecordTrade(-1)
+	if err := bot.SaveState(); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+
+	restored, err := NewBot(c, &fakeExecutor{fillRatio: 1}, nil, store)
+	if err != nil {
+		t.Fatalf("NewBot after restart: %v", err)
+	}
+	positions := restored.OpenPositions()
+	if len(positions) != 1 || positions[0].FilledQuantity != 1 {
+		t.Errorf("restored positions = %+v, want the partially filled position", positions)
+	}
+	if got := restored.LossTracker("BNBUSDT").ConsecutiveLosses(); got != 1 {
+		t.Errorf("restored consecutive losses = %d, want 1", got)
+	}
+}
+
+// sizesAfterWins sizes a position, then closes three winning trades and sizes again
+func sizesAfterWins(t *testing.T, mode CompoundingMode) (before, after float64) {
+	t.Helper()
+	c := DefaultConfig()
+	c.Logging.LogConfigOnStart = false
+	c.FixedCapital.CompoundingMode = mode
+	c.FixedCapital.MaxCapitalPerTrade = 1e9
+	c.RiskManagement.MaxPositionSize = 1
+	bot, err := NewBot(c, &fakeExecutor{fillRatio: 1}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewBot: %v", err)
+	}
+
+	before = bot.SizePosition("BNBUSDT", 100, 95, SideLong)
+	for _, id := range []string{"a", "b", "c"} {
+		bot.TrackPosition(&Position{ID: id, Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 2, FilledQuantity: 2})
+		bot.UpdatePrice("BNBUSDT", 150)
+		if _, err := bot.ClosePosition(context.Background(), id, 2); err != nil {
+			t.Fatalf("ClosePosition: %v", err)
+		}
+	}
+	if got := bot.WinRateTracker("BNBUSDT").Wins(); got != 3 {
+		t.Fatalf("recorded wins = %d, want 3", got)
+	}
+	return before, bot.SizePosition("BNBUSDT", 100, 95, SideLong)
+}
+
+func TestSizePositionGrowsUnderCompound(t *testing.T) {
+	before, after := sizesAfterWins(t, CompoundingCompound)
+	if after <= before {
+		t.Errorf("size after wins = %f, want more than %f under COMPOUND", after, before)
+	}
+}
+
+func TestSizePositionStaysFlatUnderFixed(t *testing.T) {
+	before, after := sizesAfterWins(t, CompoundingFixed)
+	if after != before {
+		t.Errorf("size after wins = %f, want %f under FIXED", after, before)
+	}
+}
+
+func TestBotRestoresRealizedPnL(t *testing.T) {
+	store, err := NewFileStateStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStateStore: %v", err)
+	}
+	c := DefaultConfig()
+	c.Logging.LogConfigOnStart = false
+	c.FixedCapital.CompoundingMode = CompoundingCompound
+	bot, err := NewBot(c, &fakeExecutor{fillRatio: 1}, nil, store)
+	if err != nil {
+		t.Fatalf("NewBot: %v", err)
+	}
+	bot.CapitalBase().RecordRealizedPnL(250)
+	if err := bot.SaveState(); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+
+	restored, err := NewBot(c, &fakeExecutor{fillRatio: 1}, nil, store)
+	if err != nil {
+		t.Fatalf("NewBot after restart: %v", err)
+	}
+	if got := restored.CapitalBase().EffectiveCapital(); got != 1250 {
+		t.Errorf("restored effective capital = %f, want 1250", got)
+	}
+}
+
+// cancelFunc adapts a function to an OrderCanceler
+type cancelFunc func(ctx context.Context, symbol, orderID string) error
+
+func (f cancelFunc) CancelOrder(ctx context.Context, symbol, orderID string) error {
+	return f(ctx, symbol, orderID)
+}
+
+func TestCancelUnfilledEntriesCancelsWithoutHoldingTheLock(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	bot.config.Load().Trading.OrderTimeout = 0
+	bot.TrackPosition(&Position{ID: "partial", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 4, FilledQuantity: 1, EntryOrderID: "1"})
+	bot.TrackPosition(&Position{ID: "unfilled", Symbol: "ETHUSDT", Side: SideLong, EntryPrice: 100, Quantity: 2, EntryOrderID: "2"})
+	bot.TrackPosition(&Position{ID: "filled", Symbol: "SOLUSDT", Side: SideLong, EntryPrice: 100, Quantity: 2, FilledQuantity: 2})
+
+	// A canceler that calls back into the bot would deadlock if the lock were held
+	var cancelled []string
+	canceler := cancelFunc(func(ctx context.Context, symbol, orderID string) error {
+		bot.OpenPositions()
+		cancelled = append(cancelled, orderID)
+		return nil
+	})
+	if err := bot.CancelUnfilledEntries(context.Background(), canceler); err != nil {
+		t.Fatalf("CancelUnfilledEntries: %v", err)
+	}
+
+	if len(cancelled) != 2 {
+		t.Errorf("cancelled %v, want the two unfilled entries", cancelled)
+	}
+	positions := bot.OpenPositions()
+	if len(positions) != 2 {
+		t.Fatalf("positions = %+v, want the unfilled one dropped", positions)
+	}
+	for _, p := range positions {
+		if p.Quantity != p.FilledQuantity {
+			t.Errorf("position %s quantity %f, want shrunk to its filled %f", p.ID, p.Quantity, p.FilledQuantity)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Enabled reports whether the swap copier runs, which it does once a master wallet is set
+func (b BSCConfig) Enabled() bool {
+	return b.MasterWalletAddress != ""
+}
+
+// ActiveNodeURL returns the node URL of the configured network
+func (b BSCConfig) ActiveNodeURL() string {
+	if b.Testnet {
+		return b.TestnetURL
+	}
+	return b.NodeURL
+}
+
+// GasPrice returns the gas price in wei
+func (b BSCConfig) GasPrice() *big.Int {
+	wei, _ := new(big.Float).Mul(big.NewFloat(b.GasPriceGwei), big.NewFloat(1e9)).Int(nil)
+	return wei
+}
+
+// validate checks the swap copier settings
+func (b BSCConfig) validate() error {
+	if b.ActiveNodeURL() == "" {
+		return fmt.Errorf("BSC node URL must be specified")
+	}
+	if !common.IsHexAddress(b.MasterWalletAddress) {
+		return fmt.Errorf("invalid master wallet address %q", b.MasterWalletAddress)
+	}
+	if _, err := crypto.HexToECDSA(trimHexPrefix(b.FollowerPrivateKey)); err != nil {
+		return fmt.Errorf("invalid follower private key: %v", err)
+	}
+	if b.CopyPercentage <= 0 || b.CopyPercentage > 100 {
+		return fmt.Errorf("copy percentage must be greater than 0 and at most 100, got %f", b.CopyPercentage)
+	}
+	for _, address := range b.TokenAddresses {
+		if !common.IsHexAddress(address) {
+			return fmt.Errorf("invalid token address %q", address)
+		}
+	}
+	if !common.IsHexAddress(b.RouterAddress) {
+		return fmt.Errorf("invalid router address %q", b.RouterAddress)
+	}
+	if !common.IsHexAddress(b.WBNBAddress) {
+		return fmt.Errorf("invalid WBNB address %q", b.WBNBAddress)
+	}
+	if b.GasPriceGwei <= 0 {
+		return fmt.Errorf("gas price must be positive, got %f", b.GasPriceGwei)
+	}
+	if b.GasLimit <= 0 {
+		return fmt.Errorf("gas limit must be positive, got %d", b.GasLimit)
+	}
+	return nil
+}
+
+// trimHexPrefix strips the 0x prefix of a hex string
+func trimHexPrefix(s string) string {
+	if len(s) >= 2 && (s[:2] == "0x" || s[:2] == "0X") {
+		return s[2:]
+	}
+	return s
+}
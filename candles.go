@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Candle represents a single OHLCV bar
+type Candle struct {
+	OpenTime time.Time
+	Open     float64
+	High     float64
+	Low      float64
+	Close    float64
+	Volume   float64
+}
+
+// Tradable reports whether a fill can be simulated on this candle
+func (c Candle) Tradable() bool {
+	return c.Volume > 0
+}
+
+// ZeroVolumePolicy controls how zero-volume candles are treated
+type ZeroVolumePolicy string
+
+const (
+	// ZeroVolumeSkip drops zero-volume candles entirely
+	ZeroVolumeSkip ZeroVolumePolicy = "SKIP"
+	// ZeroVolumeCarryForward replaces zero-volume candles with a flat bar at the previous close
+	ZeroVolumeCarryForward ZeroVolumePolicy = "CARRY_FORWARD"
+)
+
+// ParseZeroVolumePolicy parses a zero-volume policy case-insensitively
+func ParseZeroVolumePolicy(s string) (ZeroVolumePolicy, error) {
+	switch policy := ZeroVolumePolicy(strings.ToUpper(strings.TrimSpace(s))); policy {
+	case ZeroVolumeSkip, ZeroVolumeCarryForward:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown zero volume policy %q", s)
+	}
+}
+
+// ApplyZeroVolumePolicy returns the candles with zero-volume bars skipped or carried forward.
+// Carried-forward bars keep zero volume so they are never tradable.
+func ApplyZeroVolumePolicy(candles []Candle, policy ZeroVolumePolicy) []Candle {
+	result := make([]Candle, 0, len(candles))
+	for _, candle := range candles {
+		if candle.Tradable() {
+			result = append(result, candle)
+			continue
+		}
+		if policy != ZeroVolumeCarryForward || len(result) == 0 {
+			continue
+		}
+		previousClose := result[len(result)-1].Close
+		result = append(result, Candle{
+			OpenTime: candle.OpenTime,
+			Open:     previousClose,
+			High:     previousClose,
+			Low:      previousClose,
+			Close:    previousClose,
+		})
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestApplyZeroVolumePolicy(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := []Candle{
+		{OpenTime: start, Open: 1, High: 1, Low: 1, Close: 1},
+		{OpenTime: start.Add(time.Minute), Open: 1, High: 3, Low: 1, Close: 2, Volume: 5},
+		{OpenTime: start.Add(2 * time.Minute), Open: 9, High: 9, Low: 9, Close: 9},
+		{OpenTime: start.Add(3 * time.Minute), Open: 2, High: 4, Low: 2, Close: 3, Volume: 1},
+	}
+
+	skipped := ApplyZeroVolumePolicy(candles, ZeroVolumeSkip)
+	if len(skipped) != 2 || skipped[0].Close != 2 || skipped[1].Close != 3 {
+		t.Errorf("SKIP = %+v, want the two traded candles", skipped)
+	}
+
+	carried := ApplyZeroVolumePolicy(candles, ZeroVolumeCarryForward)
+	if len(carried) != 3 {
+		t.Fatalf("CARRY_FORWARD kept %d candles, want 3: a leading zero-volume candle has nothing to carry", len(carried))
+	}
+	flat := carried[1]
+	if !flat.OpenTime.Equal(candles[2].OpenTime) || flat.Open != 2 || flat.High != 2 || flat.Low != 2 || flat.Close != 2 {
+		t.Errorf("carried candle = %+v, want a flat bar at the previous close 2", flat)
+	}
+	if flat.Tradable() {
+		t.Error("carried candle is tradable")
+	}
+}
+
+func TestParseZeroVolumePolicy(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ZeroVolumePolicy
+		wantErr bool
+	}{
+		{"skip", ZeroVolumeSkip, false},
+		{" Carry_Forward ", ZeroVolumeCarryForward, false},
+		{"interpolate", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseZeroVolumePolicy(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseZeroVolumePolicy(%q) = %q, %v", tt.input, got, err)
+		}
+	}
+}
+
+func TestBacktestNeverFillsOnCarriedCandles(t *testing.T) {
+	c := backtestConfig()
+	c.Backtest.ZeroVolumePolicy = ZeroVolumeCarryForward
+	candles := []Candle{
+		candleAt(0, 100, 100, 100, 100),
+		// A zero-volume candle through the stop is carried forward flat and cannot stop out
+		{OpenTime: candleAt(1, 0, 0, 0, 0).OpenTime, Open: 50, High: 50, Low: 50, Close: 50},
+		candleAt(2, 100, 101, 99, 100),
+	}
+	result, err := NewBacktester(c, enterOnce).Run(candles)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(result.EquityCurve) != 3 {
+		t.Errorf("equity curve has %d points, want 3", len(result.EquityCurve))
+	}
+	if len(result.Trades) != 1 || result.Trades[0].NetProfit != 0 {
+		t.Errorf("trades = %+v, want one flat trade closed at the end", result.Trades)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// CompoundingMode controls how realized profits affect the capital base
+type CompoundingMode string
+
+const (
+	// CompoundingCompound adds realized PnL to the capital base
+	CompoundingCompound CompoundingMode = "COMPOUND"
+	// CompoundingFixed keeps the original capital base
+	CompoundingFixed CompoundingMode = "FIXED"
+	// CompoundingSweep removes profits above the original capital base
+	CompoundingSweep CompoundingMode = "SWEEP"
+)
+
+// ParseCompoundingMode parses a compounding mode case-insensitively
+func ParseCompoundingMode(s string) (CompoundingMode, error) {
+	switch mode := CompoundingMode(strings.ToUpper(strings.TrimSpace(s))); mode {
+	case CompoundingCompound, CompoundingFixed, CompoundingSweep:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown compounding mode %q", s)
+	}
+}
+
+// CapitalBase tracks the effective capital used for sizing as realized PnL accrues
+type CapitalBase struct {
+	mu          sync.Mutex
+	mode        CompoundingMode
+	initial     float64
+	realizedPnL float64
+}
+
+// NewCapitalBase creates a capital base from the fixed capital configuration
+func NewCapitalBase(cfg FixedCapitalConfig) *CapitalBase {
+	return &CapitalBase{
+		mode:    cfg.CompoundingMode,
+		initial: cfg.TotalCapital,
+	}
+}
+
+// reconfigure switches the capital base to the compounding mode of cfg. The initial capital is
+// kept, since the Reconciler may have corrected the equity it anchors.
+func (b *CapitalBase) reconfigure(cfg FixedCapitalConfig) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mode = cfg.CompoundingMode
+}
+
+// RecordRealizedPnL adds the realized profit or loss of a closed trade
+func (b *CapitalBase) RecordRealizedPnL(pnl float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.realizedPnL += pnl
+}
+
+// EffectiveCapital returns the capital base to size positions from
+func (b *CapitalBase) EffectiveCapital() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.mode {
+	case CompoundingCompound:
+		return b.initial + b.realizedPnL
+	case CompoundingSweep:
+		if b.realizedPnL < 0 {
+			return b.initial + b.realizedPnL
+		}
+		return b.initial
+	default:
+		return b.initial
+	}
+}
+
+// RealizedPnL returns the realized PnL booked so far
+func (b *CapitalBase) RealizedPnL() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.realizedPnL
+}
+
+// Equity returns the initial capital plus all realized PnL
+func (b *CapitalBase) Equity() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.initial + b.realizedPnL
+}
+
+// SetEquity corrects the realized PnL so Equity matches an authoritative value, such as the
+// exchange equity found by the Reconciler
+func (b *CapitalBase) SetEquity(equity float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.realizedPnL = equity - b.initial
+}
+
+// Swept returns the profits removed from the capital base under SWEEP mode
+func (b *CapitalBase) Swept() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.mode != CompoundingSweep || b.realizedPnL <= 0 {
+		return 0
+	}
+	return b.realizedPnL
+}
+
+// AllocatedCapital scales capital linearly from baseCapital at MinWinRateForIncrease up to
+// MaxCapitalPerTrade at MaxWinRateThreshold, never exceeding MaxCapitalPerTrade. It returns
+// baseCapital unchanged when dynamic allocation is disabled or below the minimum win rate.
+func (c *Config) AllocatedCapital(currentWinRate float64, baseCapital float64) float64 {
+	fc := c.FixedCapital
+	if !fc.DynamicAllocation || currentWinRate < fc.MinWinRateForIncrease {
+		return baseCapital
+	}
+
+	progress := 1.0
+	if span := fc.MaxWinRateThreshold - fc.MinWinRateForIncrease; span > 0 && currentWinRate < fc.MaxWinRateThreshold {
+		progress = (currentWinRate - fc.MinWinRateForIncrease) / span
+	}
+	if baseCapital >= fc.MaxCapitalPerTrade {
+		return fc.MaxCapitalPerTrade
+	}
+	return baseCapital + (fc.MaxCapitalPerTrade-baseCapital)*progress
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAllocatedCapitalScalesBetweenThresholds(t *testing.T) {
+	c := DefaultConfig()
+	c.FixedCapital.DynamicAllocation = true
+	c.FixedCapital.MinWinRateForIncrease = 0.5
+	c.FixedCapital.MaxWinRateThreshold = 0.9
+	c.FixedCapital.MaxCapitalPerTrade = 500
+	tests := []struct {
+		name    string
+		winRate float64
+		want    float64
+	}{
+		{"below minimum", 0.4, 100},
+		{"at minimum", 0.5, 100},
+		{"halfway", 0.7, 300},
+		{"at maximum", 0.9, 500},
+		{"above maximum", 0.99, 500},
+	}
+	for _, tt := range tests {
+		if got := c.AllocatedCapital(tt.winRate, 100); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: AllocatedCapital(%f) = %f, want %f", tt.name, tt.winRate, got, tt.want)
+		}
+	}
+}
+
+func TestAllocatedCapitalDisabledReturnsBase(t *testing.T) {
+	c := DefaultConfig()
+	c.FixedCapital.DynamicAllocation = false
+	if got := c.AllocatedCapital(0.99, 100); got != 100 {
+		t.Errorf("AllocatedCapital without dynamic allocation = %f, want 100", got)
+	}
+}
+
+func TestAllocatedCapitalClampsBaseAboveMax(t *testing.T) {
+	c := DefaultConfig()
+	c.FixedCapital.DynamicAllocation = true
+	c.FixedCapital.MaxCapitalPerTrade = 500
+	if got := c.AllocatedCapital(c.FixedCapital.MinWinRateForIncrease, 800); got != 500 {
+		t.Errorf("AllocatedCapital of a base above the cap = %f, want 500", got)
+	}
+}
+
+func TestCapitalBaseModes(t *testing.T) {
+	tests := []struct {
+		mode       CompoundingMode
+		pnl        float64
+		wantBase   float64
+		wantSwept  float64
+		wantEquity float64
+	}{
+		{CompoundingCompound, 200, 1200, 0, 1200},
+		{CompoundingCompound, -200, 800, 0, 800},
+		{CompoundingFixed, 200, 1000, 0, 1200},
+		{CompoundingFixed, -200, 1000, 0, 800},
+		{CompoundingSweep, 200, 1000, 200, 1200},
+		{CompoundingSweep, -200, 800, 0, 800},
+	}
+	for _, tt := range tests {
+		base := NewCapitalBase(FixedCapitalConfig{TotalCapital: 1000, CompoundingMode: tt.mode})
+		base.RecordRealizedPnL(tt.pnl / 2)
+		base.RecordRealizedPnL(tt.pnl / 2)
+		if got := base.EffectiveCapital(); got != tt.wantBase {
+			t.Errorf("%s %+.0f: EffectiveCapital = %f, want %f", tt.mode, tt.pnl, got, tt.wantBase)
+		}
+		if got := base.Swept(); got != tt.wantSwept {
+			t.Errorf("%s %+.0f: Swept = %f, want %f", tt.mode, tt.pnl, got, tt.wantSwept)
+		}
+		if got := base.Equity(); got != tt.wantEquity {
+			t.Errorf("%s %+.0f: Equity = %f, want %f", tt.mode, tt.pnl, got, tt.wantEquity)
+		}
+	}
+}
+
+func TestCapitalBaseSetEquity(t *testing.T) {
+	base := NewCapitalBase(FixedCapitalConfig{TotalCapital: 1000, CompoundingMode: CompoundingCompound})
+	base.SetEquity(1100)
+	if got := base.RealizedPnL(); got != 100 {
+		t.Errorf("RealizedPnL after SetEquity = %f, want 100", got)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ServerTimeFunc returns the exchange server time
+type ServerTimeFunc func(ctx context.Context) (time.Time, error)
+
+// ServerClock tracks the offset between the local clock and the exchange server clock
+// so signed request timestamps tolerate minor local clock drift
+type ServerClock struct {
+	mu              sync.RWMutex
+	serverTime      ServerTimeFunc
+	maxSkew         time.Duration
+	refreshInterval time.Duration
+	offset          time.Duration
+	lastSync        time.Time
+	now             func() time.Time
+}
+
+// Sync measures the server time offset, using the request midpoint to cancel out latency
+func (c *ServerClock) Sync(ctx context.Context) error {
+	sent := c.now()
+	serverTime, err := c.serverTime(ctx)
+	if err != nil {
+		return fmt.Errorf("error fetching server time: %v", err)
+	}
+	received := c.now()
+
+	midpoint := sent.Add(received.Sub(sent) / 2)
+	offset := serverTime.Sub(midpoint)
+	if offset > c.maxSkew || offset < -c.maxSkew {
+		return fmt.Errorf("clock skew %s exceeds maximum of %s", offset, c.maxSkew)
+	}
+
+	c.mu.Lock()
+	c.offset = offset
+	c.lastSync = received
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Offset returns the last measured server time offset
+func (c *ServerClock) Offset() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.offset
+}
+
+// Now returns the local time corrected by the server offset
+func (c *ServerClock) Now() time.Time {
+	return c.now().Add(c.Offset())
+}
+
+// Timestamp returns the corrected time in milliseconds for signing requests
+func (c *ServerClock) Timestamp() int64 {
+	return c.Now().UnixMilli()
+}
+
+// Run refreshes the offset every refresh interval until the context is cancelled
+func (c *ServerClock) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Sync(ctx); err != nil {
+				log.Printf("Error syncing server time: %v", err)
+			}
+		}
+	}
+}
+
+// NewServerClock creates a server clock that fails on offsets larger than MaxClockSkew and
+// refreshes every ClockSyncInterval
+func (c *Config) NewServerClock(serverTime ServerTimeFunc) *ServerClock {
+	return &ServerClock{
+		serverTime:      serverTime,
+		maxSkew:         time.Duration(c.Trading.MaxClockSkew) * time.Millisecond,
+		refreshInterval: time.Duration(c.Trading.ClockSyncInterval) * time.Second,
+		now:             time.Now,
+	}
+}
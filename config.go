@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"log"
+	"math"
 	"os"
 	"strconv"
 	"strings"
@@ -26,12 +27,16 @@ type FixedCapitalConfig struct {
 	MinWinRateForIncrease float64
 	// Maximum winning rate threshold for allocation
 	MaxWinRateThreshold float64
+	// How realized profits affect the capital base: COMPOUND, FIXED or SWEEP
+	CompoundingMode CompoundingMode
 }
 
 // TierProfit defines a single tier in the multi-tier take profit strategy
 type TierProfit struct {
 	// Profit percentage to trigger this tier (e.g., 0.5 = 0.5%)
 	ProfitPercentage float64
+	// Absolute target price for a known entry; takes precedence over ProfitPercentage when set
+	TargetPrice float64
 	// Percentage of position to close at this tier (0.1 to 1.0)
 	ClosePercentage float64
 	// Whether this tier is enabled
@@ -50,6 +55,10 @@ type MultiTierConfig struct {
 	MaxHoldTime int
 	// Trailing stop loss trigger percentage
 	TrailingStopPercentage float64
+	// Sort tiers by profit percentage before validation
+	AutoSort bool
+	// Minimum gap between consecutive enabled tier profit percentages (0.1 = 0.1%)
+	MinSpacing float64
 }
 
 // RiskManagementConfig defines advanced risk management settings
@@ -62,18 +71,32 @@ type RiskManagementConfig struct {
 	PauseDuration int
 	// Maximum daily loss percentage allowed
 	MaxDailyLossPercentage float64
+	// UTC hour at which the daily loss limit resets
+	DailyResetHourUTC int
 	// Enable stop loss at percentage (e.g., 0.02 = 2% loss)
 	StopLossPercentage float64
+	// Stop distance in ATRs for ATR-based stops
+	ATRStopMultiplier float64
+	// Unrealized loss that triggers a one-time review alert without closing (0 disables)
+	SoftLossPercentage float64
 	// Enable break-even stop loss after reaching profit threshold
 	BreakEvenStopEnabled bool
 	// Profit percentage to trigger break-even stop
 	BreakEvenThreshold float64
+	// Trail the stop by MultiTier.TrailingStopPercentage once it has moved to break-even
+	BreakEvenTrailingEnabled bool
 	// Maximum position size as percentage of total capital
 	MaxPositionSize float64
+	// Maximum notional across pairs sharing a base asset as percentage of equity
+	MaxBaseAssetExposure float64
+	// Maximum notional across all open positions as a fraction of equity
+	MaxTotalExposurePercentage float64
 	// Enable correlation check for multiple positions
 	CorrelationCheckEnabled bool
 	// Maximum correlation allowed between positions
 	MaxCorrelationThreshold float64
+	// Number of price samples used for correlation
+	CorrelationWindow int
 	// Enable drawdown monitoring
 	DrawdownMonitoringEnabled bool
 	// Maximum allowed drawdown percentage
@@ -82,12 +105,36 @@ type RiskManagementConfig struct {
 	EquityProtectionEnabled bool
 	// Minimum equity level to stop trading
 	MinimumEquityLevel float64
+	// Fraction above MinimumEquityLevel equity must recover to before trading resumes
+	MinimumEquityRecoveryMargin float64
+	// Maximum re-entries per setup after a stop-out, each requiring a fresh signal
+	MaxReEntries int
+	// Seconds after closing a position before the same symbol may be entered again (0 disables)
+	ReEntryCooldown int
+	// Maximum number of positions open at once
+	MaxConcurrentPositions int
+	// What happens to copy signals at the position limit: queue or drop
+	MaxPositionsPolicy MaxPositionsPolicy
+	// Minimum ratio of first tier reward to stop loss risk
+	MinRewardRiskRatio float64
+	// Win rate assumed when checking the tiers for negative expectancy
+	ExpectedWinRate float64
+	// Equity the daily loss and drawdown checks measure: realized (cash only) or total (with unrealized PnL)
+	RiskEquityBasis EquityBasis
+	// Per-symbol overrides of the per-trade risk settings, applied by RiskFor
+	SymbolOverrides map[string]RiskOverride
 }
 
 // TradingConfig defines core trading parameters
 type TradingConfig struct {
-	// Trading pair to monitor (e.g., "BNBUSDT")
+	// Trading pair to monitor (e.g., "BNBUSDT"); kept for single-pair configurations
 	TradingPair string
+	// Trading pairs to monitor; takes precedence over TradingPair when set
+	TradingPairs []string
+	// Exchange step and tick sizes per symbol
+	SymbolFilters map[string]SymbolFilter
+	// How entry quantities are rounded to the step size: down, nearest or up
+	RoundingMode RoundingMode
 	// Exchange API key
 	APIKey string
 	// Exchange API secret
@@ -102,12 +149,38 @@ type TradingConfig struct {
 	SlippageTolerance float64
 	// Order timeout in seconds
 	OrderTimeout int
+	// Entry order type: market, limit or stop_limit
+	OrderType OrderType
+	// Distance of limit entries from the mid price (0.001 = 0.1%)
+	LimitOffsetPercentage float64
+	// Time in force of limit-type orders: GTC, IOC or FOK
+	TimeInForce TimeInForce
 	// Enable order validation before submission
 	OrderValidationEnabled bool
+	// Mark exits reduce-only and cap them at the open quantity
+	ReduceOnlyExits bool
+	// Submit each position's stop loss and take profit as a linked OCO pair on the exchange
+	UseOCOExits bool
 	// Maker fee percentage
 	MakerFee float64
 	// Taker fee percentage
 	TakerFee float64
+	// Maker rebate rate earned on maker fills (0 disables rebate tracking)
+	MakerRebateRate float64
+	// Volume-based fee tiers replacing MakerFee and TakerFee when set
+	FeeSchedule FeeSchedule
+	// Enable chasing resting limit entries toward the market
+	ChaseEnabled bool
+	// Maximum distance an entry may be chased from the signal price (0.005 = 0.5%)
+	MaxChaseDistance float64
+	// Maximum tolerated clock skew against the exchange server in milliseconds
+	MaxClockSkew int
+	// Interval in seconds between server time synchronizations
+	ClockSyncInterval int
+	// Number of workers evaluating symbols concurrently each tick
+	Workers int
+	// Exchange request weight allowed per minute
+	APIWeightLimit int
 }
 
 // LoggingConfig defines logging configuration
@@ -124,128 +197,627 @@ type LoggingConfig struct {
 	MaxLogFileSize int
 	// Number of backup log files to keep
 	MaxBackupFiles int
+	// Log the effective configuration summary when the bot starts
+	LogConfigOnStart bool
+}
+
+// KellyConfig defines Kelly criterion position sizing
+type KellyConfig struct {
+	// Fraction of the full Kelly bet to take (0.5 = half Kelly)
+	Fraction float64
+}
+
+// CopyTradingConfig defines which leaders are copied and how their trades are scaled
+type CopyTradingConfig struct {
+	// Copy the trades of the leaders below
+	Enabled bool
+	// Addresses of the leader traders to copy
+	LeaderAddresses []string
+	// Multiplier applied to leader position sizes after scaling to my equity
+	CopyRatio float64
+	// Maximum number of leaders copied at once
+	MaxLeaders int
+	// Leader positions smaller than this are ignored as dust
+	MinLeaderPositionSize float64
+	// Decay applied to a leader's past performance per day elapsed (1 never forgets)
+	LeaderScoreDecay float64
+	// Delay in milliseconds before mirroring a leader order
+	CopyDelay int
+	// Maximum random jitter in milliseconds added to the copy delay
+	CopyDelayJitter int
+	// Maximum copied orders per minute (0 disables throttling)
+	MaxCopiesPerMinute int
+	// Maximum move of the market price away from the leader's fill before a copy is skipped (0.01 = 1%)
+	MaxCopyPriceDeviation float64
+	// Absolute cap on the notional of any single copied order, whatever the scaling
+	MaxCopyNotional float64
+	// Symbols copied; empty copies every symbol
+	SymbolAllowlist []string
+	// Symbols never copied, even when allowlisted
+	SymbolBlocklist []string
+}
+
+// EntryFiltersConfig defines the optional indicator filters applied to copy signals
+type EntryFiltersConfig struct {
+	// Only copy longs while the fast SMA is above the slow SMA and shorts while below
+	TrendFilterEnabled bool
+	// Closes averaged by the fast SMA
+	TrendFastPeriod int
+	// Closes averaged by the slow SMA
+	TrendSlowPeriod int
+	// Block longs above the overbought RSI and shorts below the oversold RSI
+	RSIGuardEnabled bool
+	// Price changes smoothed by the RSI
+	RSIPeriod int
+	// RSI above which longs are blocked
+	RSIOverbought float64
+	// RSI below which shorts are blocked
+	RSIOversold float64
+}
+
+// EntryImprovementConfig defines limit entries placed at a better price than the leader's fill
+type EntryImprovementConfig struct {
+	// Rest copies as limits better than the leader's price before falling back to market
+	Enabled bool
+	// Distance of the limit from the leader's fill price in the favorable direction (0.001 = 0.1%)
+	OffsetPercentage float64
+	// Seconds the limit rests before the remainder is filled at market
+	Timeout int
+}
+
+// PaperTradingConfig defines the simulated paper trading account
+type PaperTradingConfig struct {
+	// Simulate fills against a persisted paper account instead of trading live
+	Enabled bool
+	// Quote balance a new paper account starts with
+	StartingBalance float64
+}
+
+// FuturesConfig defines perpetual futures margin settings, used when FuturesMode is enabled
+type FuturesConfig struct {
+	// Leverage applied to every position
+	Leverage int
+	// Maintenance margin rate used to estimate liquidation prices (0.004 = 0.4%)
+	MaintenanceMarginRate float64
+	// Highest leverage allowed per symbol
+	SymbolMaxLeverage map[string]int
+}
+
+// BacktestConfig defines backtesting behaviour
+type BacktestConfig struct {
+	// How zero-volume candles are handled: SKIP or CARRY_FORWARD
+	ZeroVolumePolicy ZeroVolumePolicy
+}
+
+// BSCConfig defines the on-chain PancakeSwap swap copier
+type BSCConfig struct {
+	// BSC mainnet node URL
+	NodeURL string
+	// BSC testnet node URL, used when Testnet is set
+	TestnetURL string
+	// Connect to the testnet instead of mainnet
+	Testnet bool
+	// Wallet whose swaps are copied; the copier runs only when it is set
+	MasterWalletAddress string
+	// Private key of the follower wallet (hex, with or without 0x)
+	FollowerPrivateKey string
+	// Percentage of the master's trade size to copy (100 = full size)
+	CopyPercentage float64
+	// Token addresses to monitor; empty monitors every token
+	TokenAddresses []string
+	// PancakeSwap router address
+	RouterAddress string
+	// Wrapped BNB token address
+	WBNBAddress string
+	// Gas price in Gwei
+	GasPriceGwei float64
+	// Gas limit of swap transactions
+	GasLimit int
 }
 
 // Config represents the complete bot configuration
 type Config struct {
-	FixedCapital    FixedCapitalConfig
-	MultiTier       MultiTierConfig
-	RiskManagement  RiskManagementConfig
-	Trading         TradingConfig
-	Logging         LoggingConfig
+	FixedCapital     FixedCapitalConfig
+	MultiTier        MultiTierConfig
+	RiskManagement   RiskManagementConfig
+	Trading          TradingConfig
+	Logging          LoggingConfig
+	Kelly            KellyConfig
+	CopyTrading      CopyTradingConfig
+	EntryFilters     EntryFiltersConfig
+	ScaleIn          ScaleInPlan
+	EntryImprovement EntryImprovementConfig
+	PaperTrading     PaperTradingConfig
+	Futures          FuturesConfig
+	Backtest         BacktestConfig
+	BSC              BSCConfig
+	// Position sizing strategy: FIXED_RISK or KELLY
+	SizingStrategy SizingStrategy
 	// Refresh interval in seconds for market data
 	RefreshInterval int
+	// Adapt the refresh interval to price volatility within the min/max bounds
+	AdaptiveRefresh bool
+	// Minimum adaptive refresh interval in seconds
+	MinRefreshInterval int
+	// Maximum adaptive refresh interval in seconds
+	MaxRefreshInterval int
+	// Smoothed per-refresh price move at which the minimum interval is used (0.005 = 0.5%)
+	AdaptiveRefreshVolatility float64
+	// Trade perpetual futures; funding payments are counted in profit
+	FuturesMode bool
+	// Market data source: ws streams over WebSocket with REST fallback, rest polls every refresh interval
+	MarketDataMode MarketDataMode
 	// Enable dry run mode (no actual trades)
 	DryRun bool
+	// Order execution mode: LIVE or OBSERVE
+	ExecutionMode ExecutionMode
+	// Minutes without an order attempt while active before the bot is flagged degraded (0 disables)
+	LivenessTimeout int
+	// Seconds between balance reconciliations against the exchange (0 disables)
+	ReconcileInterval int
+	// Drift between internal equity and the exchange balance tolerated before alerting (0.01 = 1%)
+	ReconcileTolerance float64
+	// Replace internal equity with the exchange balance when they diverge
+	ReconcileAutoCorrect bool
+	// Keep exchange-native stop orders on open positions so they stay protected if the bot dies
+	DeadMansSwitchEnabled bool
+	// State persistence backend
+	StateBackend string
+	// Directory for the file state backend
+	StateDir string
+	// SQLite database path for the closed trade history (empty disables it)
+	TradeHistoryPath string
+	// Serve Prometheus metrics
+	MetricsEnabled bool
+	// Port the metrics endpoint listens on
+	MetricsPort int
+	// Serve the /healthz and /readyz probes
+	HealthEnabled bool
+	// Port the health endpoints listen on
+	HealthPort int
+	// Bearer token authorizing POST /pause and /resume on the health server (empty disables them)
+	ControlToken string
 	// Notification webhook URL
 	WebhookURL string
+	// Webhook payload format: generic, slack or discord
+	WebhookFormat WebhookFormat
+	// Webhook request timeout in seconds
+	WebhookTimeout int
 	// Enable notifications
 	NotificationsEnabled bool
 }
 
+// tierSpacingTolerance absorbs float rounding so tiers exactly MinSpacing apart are accepted
+const tierSpacingTolerance = 1e-9
+
 // LoadConfig loads configuration from environment variables and defaults
 func LoadConfig() (*Config, error) {
 	// Load .env file if it exists
 	_ = godotenv.Load()
 
-	config := &Config{}
+	config := DefaultConfig()
+	if err := config.applyEnv(); err != nil {
+		return nil, err
+	}
 
-	// Load Fixed Capital Configuration
-	config.FixedCapital = FixedCapitalConfig{
-		TotalCapital:             getEnvFloat("FIXED_CAPITAL_TOTAL", 1000.0),
-		RiskPercentage:           getEnvFloat("FIXED_CAPITAL_RISK_PERCENT", 0.05),
-		MinimumCapital:           getEnvFloat("FIXED_CAPITAL_MINIMUM", 10.0),
-		MaxCapitalPerTrade:       getEnvFloat("FIXED_CAPITAL_MAX_PER_TRADE", 500.0),
-		DynamicAllocation:        getEnvBool("FIXED_CAPITAL_DYNAMIC_ALLOCATION", false),
-		MinWinRateForIncrease:    getEnvFloat("FIXED_CAPITAL_MIN_WIN_RATE", 0.55),
-		MaxWinRateThreshold:      getEnvFloat("FIXED_CAPITAL_MAX_WIN_RATE", 0.85),
+	// Sort tiers before validation so the ascending check passes
+	if config.MultiTier.AutoSort {
+		config.MultiTier.SortTiers()
 	}
 
-	// Load Multi-Tier Configuration
-	config.MultiTier = MultiTierConfig{
-		Enabled:                 getEnvBool("MULTI_TIER_ENABLED", true),
-		CloseOnTimeout:          getEnvBool("MULTI_TIER_CLOSE_ON_TIMEOUT", true),
-		MaxHoldTime:             getEnvInt("MULTI_TIER_MAX_HOLD_TIME", 240),
-		TrailingStopPercentage:  getEnvFloat("MULTI_TIER_TRAILING_STOP", 0.5),
-		Tiers: []TierProfit{
-			{
-				ProfitPercentage: 0.5,
-				ClosePercentage:  0.2,
-				Enabled:          true,
-			},
-			{
-				ProfitPercentage: 1.0,
-				ClosePercentage:  0.3,
-				Enabled:          true,
-			},
-			{
-				ProfitPercentage: 1.5,
-				ClosePercentage:  0.25,
-				Enabled:          true,
-			},
-			{
-				ProfitPercentage: 2.0,
-				ClosePercentage:  0.25,
-				Enabled:          true,
+	// Validate configuration
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// DefaultConfig returns the configuration used when no file or environment overrides are set
+func DefaultConfig() *Config {
+	return &Config{
+		FixedCapital: FixedCapitalConfig{
+			TotalCapital:          1000.0,
+			RiskPercentage:        0.02,
+			MinimumCapital:        10.0,
+			MaxCapitalPerTrade:    500.0,
+			DynamicAllocation:     false,
+			MinWinRateForIncrease: 0.55,
+			MaxWinRateThreshold:   0.85,
+			CompoundingMode:       CompoundingFixed,
+		},
+		MultiTier: MultiTierConfig{
+			Enabled:                true,
+			CloseOnTimeout:         true,
+			MaxHoldTime:            240,
+			TrailingStopPercentage: 0.5,
+			AutoSort:               false,
+			MinSpacing:             0.1,
+			Tiers: []TierProfit{
+				{
+					ProfitPercentage: 0.5,
+					ClosePercentage:  0.2,
+					Enabled:          true,
+				},
+				{
+					ProfitPercentage: 1.0,
+					ClosePercentage:  0.3,
+					Enabled:          true,
+				},
+				{
+					ProfitPercentage: 1.5,
+					ClosePercentage:  0.25,
+					Enabled:          true,
+				},
+				{
+					ProfitPercentage: 2.0,
+					ClosePercentage:  0.25,
+					Enabled:          true,
+				},
 			},
 		},
+		RiskManagement: RiskManagementConfig{
+			MaxRiskPercentage:           0.02,
+			MaxConsecutiveLosses:        5,
+			PauseDuration:               30,
+			MaxDailyLossPercentage:      0.05,
+			DailyResetHourUTC:           0,
+			StopLossPercentage:          0.03,
+			ATRStopMultiplier:           2.0,
+			SoftLossPercentage:          0,
+			BreakEvenStopEnabled:        true,
+			BreakEvenThreshold:          0.5,
+			MaxPositionSize:             0.1,
+			MaxBaseAssetExposure:        0.25,
+			MaxTotalExposurePercentage:  1.0,
+			CorrelationCheckEnabled:     true,
+			MaxCorrelationThreshold:     0.8,
+			CorrelationWindow:           50,
+			DrawdownMonitoringEnabled:   true,
+			MaxDrawdownPercentage:       0.15,
+			EquityProtectionEnabled:     true,
+			MinimumEquityLevel:          500.0,
+			MinimumEquityRecoveryMargin: 0.05,
+			MaxReEntries:                1,
+			ReEntryCooldown:             0,
+			MaxConcurrentPositions:      5,
+			MaxPositionsPolicy:          MaxPositionsDrop,
+			MinRewardRiskRatio:          1.0,
+			ExpectedWinRate:             0.5,
+			RiskEquityBasis:             EquityTotal,
+		},
+		Trading: TradingConfig{
+			TradingPair:            "BNBUSDT",
+			TestnetEnabled:         false,
+			MinOrderQuantity:       0.01,
+			MaxOrderQuantity:       1000.0,
+			SlippageTolerance:      0.01,
+			OrderTimeout:           30,
+			OrderType:              OrderTypeMarket,
+			LimitOffsetPercentage:  0.0005,
+			TimeInForce:            TimeInForceGTC,
+			RoundingMode:           RoundingDown,
+			OrderValidationEnabled: true,
+			ReduceOnlyExits:        true,
+			UseOCOExits:            false,
+			MakerFee:               0.001,
+			TakerFee:               0.001,
+			MakerRebateRate:        0,
+			ChaseEnabled:           false,
+			MaxChaseDistance:       0.005,
+			MaxClockSkew:           5000,
+			ClockSyncInterval:      300,
+			Workers:                4,
+			APIWeightLimit:         1200,
+		},
+		Logging: LoggingConfig{
+			LogLevel:         "INFO",
+			LogFilePath:      "./logs/bot.log",
+			ConsoleLogging:   true,
+			FileLogging:      true,
+			MaxLogFileSize:   10,
+			MaxBackupFiles:   5,
+			LogConfigOnStart: false,
+		},
+		Kelly: KellyConfig{
+			Fraction: 0.5,
+		},
+		CopyTrading: CopyTradingConfig{
+			Enabled:               false,
+			CopyRatio:             1.0,
+			MaxLeaders:            5,
+			MinLeaderPositionSize: 0.001,
+			LeaderScoreDecay:      0.9,
+			CopyDelay:             500,
+			CopyDelayJitter:       250,
+			MaxCopiesPerMinute:    10,
+			MaxCopyPriceDeviation: 0.01,
+			MaxCopyNotional:       1000,
+		},
+		EntryFilters: EntryFiltersConfig{
+			TrendFilterEnabled: false,
+			TrendFastPeriod:    10,
+			TrendSlowPeriod:    30,
+			RSIGuardEnabled:    false,
+			RSIPeriod:          14,
+			RSIOverbought:      70,
+			RSIOversold:        30,
+		},
+		ScaleIn: ScaleInPlan{
+			Entries:           1,
+			SpacingPercentage: 0.005,
+		},
+		EntryImprovement: EntryImprovementConfig{
+			Enabled:          false,
+			OffsetPercentage: 0.001,
+			Timeout:          30,
+		},
+		PaperTrading: PaperTradingConfig{
+			Enabled:         false,
+			StartingBalance: 1000.0,
+		},
+		Futures: FuturesConfig{
+			Leverage:              1,
+			MaintenanceMarginRate: 0.004,
+		},
+		Backtest: BacktestConfig{
+			ZeroVolumePolicy: ZeroVolumeSkip,
+		},
+		BSC: BSCConfig{
+			NodeURL:        "https://bsc-dataseed1.binance.org",
+			TestnetURL:     "https://data-seed-prebsc-1-s1.binance.org:8545",
+			Testnet:        false,
+			CopyPercentage: 100,
+			RouterAddress:  "0x10ED43C718714eb63d5aA57B78B54704E256024E", // PancakeSwap V2 Router
+			WBNBAddress:    "0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c",
+			GasPriceGwei:   5,
+			GasLimit:       300000,
+		},
+		SizingStrategy:            SizingFixedRisk,
+		RefreshInterval:           5,
+		AdaptiveRefresh:           false,
+		MinRefreshInterval:        1,
+		MaxRefreshInterval:        30,
+		AdaptiveRefreshVolatility: 0.005,
+		FuturesMode:               false,
+		MarketDataMode:            MarketDataREST,
+		DryRun:                    false,
+		ExecutionMode:             ExecutionLive,
+		LivenessTimeout:           60,
+		ReconcileInterval:         300,
+		ReconcileTolerance:        0.01,
+		ReconcileAutoCorrect:      false,
+		DeadMansSwitchEnabled:     false,
+		StateBackend:              StateBackendFile,
+		StateDir:                  "./state",
+		TradeHistoryPath:          "./state/trades.db",
+		MetricsEnabled:            false,
+		MetricsPort:               9090,
+		HealthEnabled:             false,
+		HealthPort:                8080,
+		WebhookFormat:             WebhookGeneric,
+		WebhookTimeout:            10,
+		NotificationsEnabled:      true,
 	}
+}
+
+// applyEnv overrides configuration values with any environment variables that are set
+func (c *Config) applyEnv() error {
+	// Load Fixed Capital Configuration
+	c.FixedCapital.TotalCapital = getEnvFloat("FIXED_CAPITAL_TOTAL", c.FixedCapital.TotalCapital)
+	c.FixedCapital.RiskPercentage = getEnvFloat("FIXED_CAPITAL_RISK_PERCENT", c.FixedCapital.RiskPercentage)
+	c.FixedCapital.MinimumCapital = getEnvFloat("FIXED_CAPITAL_MINIMUM", c.FixedCapital.MinimumCapital)
+	c.FixedCapital.MaxCapitalPerTrade = getEnvFloat("FIXED_CAPITAL_MAX_PER_TRADE", c.FixedCapital.MaxCapitalPerTrade)
+	c.FixedCapital.DynamicAllocation = getEnvBool("FIXED_CAPITAL_DYNAMIC_ALLOCATION", c.FixedCapital.DynamicAllocation)
+	c.FixedCapital.MinWinRateForIncrease = getEnvFloat("FIXED_CAPITAL_MIN_WIN_RATE", c.FixedCapital.MinWinRateForIncrease)
+	c.FixedCapital.MaxWinRateThreshold = getEnvFloat("FIXED_CAPITAL_MAX_WIN_RATE", c.FixedCapital.MaxWinRateThreshold)
+	c.FixedCapital.CompoundingMode = CompoundingMode(strings.ToUpper(getEnvString("FIXED_CAPITAL_COMPOUNDING_MODE", string(c.FixedCapital.CompoundingMode))))
+
+	// Load Multi-Tier Configuration
+	c.MultiTier.Enabled = getEnvBool("MULTI_TIER_ENABLED", c.MultiTier.Enabled)
+	c.MultiTier.CloseOnTimeout = getEnvBool("MULTI_TIER_CLOSE_ON_TIMEOUT", c.MultiTier.CloseOnTimeout)
+	c.MultiTier.MaxHoldTime = getEnvInt("MULTI_TIER_MAX_HOLD_TIME", c.MultiTier.MaxHoldTime)
+	c.MultiTier.TrailingStopPercentage = getEnvFloat("MULTI_TIER_TRAILING_STOP", c.MultiTier.TrailingStopPercentage)
+	c.MultiTier.AutoSort = getEnvBool("MULTI_TIER_AUTO_SORT", c.MultiTier.AutoSort)
+	c.MultiTier.MinSpacing = getEnvFloat("MULTI_TIER_MIN_SPACING", c.MultiTier.MinSpacing)
 
 	// Load Risk Management Configuration
-	config.RiskManagement = RiskManagementConfig{
-		MaxRiskPercentage:          getEnvFloat("RISK_MAX_RISK_PERCENT", 0.02),
-		MaxConsecutiveLosses:       getEnvInt("RISK_MAX_CONSECUTIVE_LOSSES", 5),
-		PauseDuration:              getEnvInt("RISK_PAUSE_DURATION_MINUTES", 30),
-		MaxDailyLossPercentage:     getEnvFloat("RISK_MAX_DAILY_LOSS_PERCENT", 0.05),
-		StopLossPercentage:         getEnvFloat("RISK_STOP_LOSS_PERCENT", 0.03),
-		BreakEvenStopEnabled:       getEnvBool("RISK_BREAK_EVEN_STOP_ENABLED", true),
-		BreakEvenThreshold:         getEnvFloat("RISK_BREAK_EVEN_THRESHOLD", 0.5),
-		MaxPositionSize:            getEnvFloat("RISK_MAX_POSITION_SIZE", 0.1),
-		CorrelationCheckEnabled:    getEnvBool("RISK_CORRELATION_CHECK_ENABLED", true),
-		MaxCorrelationThreshold:    getEnvFloat("RISK_MAX_CORRELATION_THRESHOLD", 0.8),
-		DrawdownMonitoringEnabled:  getEnvBool("RISK_DRAWDOWN_MONITORING_ENABLED", true),
-		MaxDrawdownPercentage:      getEnvFloat("RISK_MAX_DRAWDOWN_PERCENT", 0.15),
-		EquityProtectionEnabled:    getEnvBool("RISK_EQUITY_PROTECTION_ENABLED", true),
-		MinimumEquityLevel:         getEnvFloat("RISK_MINIMUM_EQUITY_LEVEL", 500.0),
-	}
+	c.RiskManagement.MaxRiskPercentage = getEnvFloat("RISK_MAX_RISK_PERCENT", c.RiskManagement.MaxRiskPercentage)
+	c.RiskManagement.MaxConsecutiveLosses = getEnvInt("RISK_MAX_CONSECUTIVE_LOSSES", c.RiskManagement.MaxConsecutiveLosses)
+	c.RiskManagement.PauseDuration = getEnvInt("RISK_PAUSE_DURATION_MINUTES", c.RiskManagement.PauseDuration)
+	c.RiskManagement.MaxDailyLossPercentage = getEnvFloat("RISK_MAX_DAILY_LOSS_PERCENT", c.RiskManagement.MaxDailyLossPercentage)
+	c.RiskManagement.DailyResetHourUTC = getEnvInt("RESET_HOUR_UTC", c.RiskManagement.DailyResetHourUTC)
+	c.RiskManagement.StopLossPercentage = getEnvFloat("RISK_STOP_LOSS_PERCENT", c.RiskManagement.StopLossPercentage)
+	c.RiskManagement.ATRStopMultiplier = getEnvFloat("ATR_STOP_MULTIPLIER", c.RiskManagement.ATRStopMultiplier)
+	c.RiskManagement.SoftLossPercentage = getEnvFloat("RISK_SOFT_LOSS_PERCENT", c.RiskManagement.SoftLossPercentage)
+	c.RiskManagement.BreakEvenStopEnabled = getEnvBool("RISK_BREAK_EVEN_STOP_ENABLED", c.RiskManagement.BreakEvenStopEnabled)
+	c.RiskManagement.BreakEvenThreshold = getEnvFloat("RISK_BREAK_EVEN_THRESHOLD", c.RiskManagement.BreakEvenThreshold)
+	c.RiskManagement.BreakEvenTrailingEnabled = getEnvBool("RISK_BREAK_EVEN_TRAILING_ENABLED", c.RiskManagement.BreakEvenTrailingEnabled)
+	c.RiskManagement.MaxPositionSize = getEnvFloat("RISK_MAX_POSITION_SIZE", c.RiskManagement.MaxPositionSize)
+	c.RiskManagement.MaxBaseAssetExposure = getEnvFloat("RISK_MAX_BASE_ASSET_EXPOSURE", c.RiskManagement.MaxBaseAssetExposure)
+	c.RiskManagement.MaxTotalExposurePercentage = getEnvFloat("RISK_MAX_TOTAL_EXPOSURE_PERCENT", c.RiskManagement.MaxTotalExposurePercentage)
+	c.RiskManagement.CorrelationCheckEnabled = getEnvBool("RISK_CORRELATION_CHECK_ENABLED", c.RiskManagement.CorrelationCheckEnabled)
+	c.RiskManagement.MaxCorrelationThreshold = getEnvFloat("RISK_MAX_CORRELATION_THRESHOLD", c.RiskManagement.MaxCorrelationThreshold)
+	c.RiskManagement.CorrelationWindow = getEnvInt("RISK_CORRELATION_WINDOW", c.RiskManagement.CorrelationWindow)
+	c.RiskManagement.DrawdownMonitoringEnabled = getEnvBool("RISK_DRAWDOWN_MONITORING_ENABLED", c.RiskManagement.DrawdownMonitoringEnabled)
+	c.RiskManagement.MaxDrawdownPercentage = getEnvFloat("RISK_MAX_DRAWDOWN_PERCENT", c.RiskManagement.MaxDrawdownPercentage)
+	c.RiskManagement.EquityProtectionEnabled = getEnvBool("RISK_EQUITY_PROTECTION_ENABLED", c.RiskManagement.EquityProtectionEnabled)
+	c.RiskManagement.MinimumEquityLevel = getEnvFloat("RISK_MINIMUM_EQUITY_LEVEL", c.RiskManagement.MinimumEquityLevel)
+	c.RiskManagement.MinimumEquityRecoveryMargin = getEnvFloat("MINIMUM_EQUITY_RECOVERY_MARGIN", c.RiskManagement.MinimumEquityRecoveryMargin)
+	c.RiskManagement.MaxReEntries = getEnvInt("RISK_MAX_RE_ENTRIES", c.RiskManagement.MaxReEntries)
+	c.RiskManagement.ReEntryCooldown = getEnvInt("RE_ENTRY_COOLDOWN_SECONDS", c.RiskManagement.ReEntryCooldown)
+	c.RiskManagement.MaxConcurrentPositions = getEnvInt("MAX_CONCURRENT_POSITIONS", c.RiskManagement.MaxConcurrentPositions)
+	c.RiskManagement.MaxPositionsPolicy = MaxPositionsPolicy(strings.ToLower(getEnvString("MAX_POSITIONS_POLICY", string(c.RiskManagement.MaxPositionsPolicy))))
+	c.RiskManagement.MinRewardRiskRatio = getEnvFloat("RISK_MIN_REWARD_RISK_RATIO", c.RiskManagement.MinRewardRiskRatio)
+	c.RiskManagement.ExpectedWinRate = getEnvFloat("RISK_EXPECTED_WIN_RATE", c.RiskManagement.ExpectedWinRate)
+	c.RiskManagement.RiskEquityBasis = EquityBasis(strings.ToLower(getEnvString("RISK_EQUITY_BASIS", string(c.RiskManagement.RiskEquityBasis))))
 
 	// Load Trading Configuration
-	config.Trading = TradingConfig{
-		TradingPair:            getEnvString("TRADING_PAIR", "BNBUSDT"),
-		APIKey:                 os.Getenv("API_KEY"),
-		APISecret:              os.Getenv("API_SECRET"),
-		TestnetEnabled:         getEnvBool("TRADING_TESTNET_ENABLED", false),
-		MinOrderQuantity:       getEnvFloat("TRADING_MIN_ORDER_QUANTITY", 0.01),
-		MaxOrderQuantity:       getEnvFloat("TRADING_MAX_ORDER_QUANTITY", 1000.0),
-		SlippageTolerance:      getEnvFloat("TRADING_SLIPPAGE_TOLERANCE", 0.01),
-		OrderTimeout:           getEnvInt("TRADING_ORDER_TIMEOUT_SECONDS", 30),
-		OrderValidationEnabled: getEnvBool("TRADING_ORDER_VALIDATION_ENABLED", true),
-		MakerFee:               getEnvFloat("TRADING_MAKER_FEE", 0.001),
-		TakerFee:               getEnvFloat("TRADING_TAKER_FEE", 0.001),
+	c.Trading.TradingPair = getEnvString("TRADING_PAIR", c.Trading.TradingPair)
+	c.Trading.TradingPairs = getEnvList("TRADING_PAIRS", c.Trading.TradingPairs)
+	c.Trading.APIKey = getEnvString("API_KEY", c.Trading.APIKey)
+	c.Trading.APISecret = getEnvString("API_SECRET", c.Trading.APISecret)
+	c.Trading.TestnetEnabled = getEnvBool("TRADING_TESTNET_ENABLED", c.Trading.TestnetEnabled)
+	c.Trading.MinOrderQuantity = getEnvFloat("TRADING_MIN_ORDER_QUANTITY", c.Trading.MinOrderQuantity)
+	c.Trading.MaxOrderQuantity = getEnvFloat("TRADING_MAX_ORDER_QUANTITY", c.Trading.MaxOrderQuantity)
+	c.Trading.SlippageTolerance = getEnvFloat("TRADING_SLIPPAGE_TOLERANCE", c.Trading.SlippageTolerance)
+	c.Trading.OrderTimeout = getEnvInt("TRADING_ORDER_TIMEOUT_SECONDS", c.Trading.OrderTimeout)
+	c.Trading.OrderType = OrderType(strings.ToLower(getEnvString("ORDER_TYPE", string(c.Trading.OrderType))))
+	c.Trading.LimitOffsetPercentage = getEnvFloat("LIMIT_OFFSET_PERCENTAGE", c.Trading.LimitOffsetPercentage)
+	c.Trading.TimeInForce = TimeInForce(strings.ToUpper(getEnvString("ORDER_TIME_IN_FORCE", string(c.Trading.TimeInForce))))
+	c.Trading.RoundingMode = RoundingMode(strings.ToLower(getEnvString("QUANTITY_ROUNDING_MODE", string(c.Trading.RoundingMode))))
+	c.Trading.OrderValidationEnabled = getEnvBool("TRADING_ORDER_VALIDATION_ENABLED", c.Trading.OrderValidationEnabled)
+	c.Trading.ReduceOnlyExits = getEnvBool("REDUCE_ONLY_EXITS", c.Trading.ReduceOnlyExits)
+	c.Trading.UseOCOExits = getEnvBool("USE_OCO_EXITS", c.Trading.UseOCOExits)
+	c.Trading.MakerFee = getEnvFloat("TRADING_MAKER_FEE", c.Trading.MakerFee)
+	c.Trading.TakerFee = getEnvFloat("TRADING_TAKER_FEE", c.Trading.TakerFee)
+	c.Trading.FeeSchedule.Tiers = getEnvFeeTiers("TRADING_FEE_TIERS", c.Trading.FeeSchedule.Tiers)
+	c.Trading.FeeSchedule.UseBNBDiscount = getEnvBool("TRADING_BNB_FEE_DISCOUNT", c.Trading.FeeSchedule.UseBNBDiscount)
+	c.Trading.FeeSchedule.Volume30d = getEnvFloat("TRADING_VOLUME_30D", c.Trading.FeeSchedule.Volume30d)
+	c.Trading.MakerRebateRate = getEnvFloat("TRADING_MAKER_REBATE_RATE", c.Trading.MakerRebateRate)
+	c.Trading.ChaseEnabled = getEnvBool("TRADING_CHASE_ENABLED", c.Trading.ChaseEnabled)
+	c.Trading.MaxChaseDistance = getEnvFloat("TRADING_MAX_CHASE_DISTANCE", c.Trading.MaxChaseDistance)
+	c.Trading.MaxClockSkew = getEnvInt("TRADING_MAX_CLOCK_SKEW_MS", c.Trading.MaxClockSkew)
+	c.Trading.ClockSyncInterval = getEnvInt("TRADING_CLOCK_SYNC_INTERVAL_SECONDS", c.Trading.ClockSyncInterval)
+	c.Trading.Workers = getEnvInt("TRADING_WORKERS", c.Trading.Workers)
+	c.Trading.APIWeightLimit = getEnvInt("API_WEIGHT_LIMIT", c.Trading.APIWeightLimit)
+	for _, symbol := range c.Pairs() {
+		filter := c.Trading.SymbolFilters[symbol]
+		filter.StepSize = getEnvFloat("SYMBOL_FILTER_"+symbol+"_STEP_SIZE", filter.StepSize)
+		filter.TickSize = getEnvFloat("SYMBOL_FILTER_"+symbol+"_TICK_SIZE", filter.TickSize)
+		if filter == (SymbolFilter{}) {
+			continue
+		}
+		if c.Trading.SymbolFilters == nil {
+			c.Trading.SymbolFilters = make(map[string]SymbolFilter)
+		}
+		c.Trading.SymbolFilters[symbol] = filter
 	}
 
 	// Load Logging Configuration
-	config.Logging = LoggingConfig{
-		LogLevel:       getEnvString("LOG_LEVEL", "INFO"),
-		LogFilePath:    getEnvString("LOG_FILE_PATH", "./logs/bot.log"),
-		ConsoleLogging: getEnvBool("LOG_CONSOLE_ENABLED", true),
-		FileLogging:    getEnvBool("LOG_FILE_ENABLED", true),
-		MaxLogFileSize: getEnvInt("LOG_MAX_FILE_SIZE_MB", 10),
-		MaxBackupFiles: getEnvInt("LOG_MAX_BACKUP_FILES", 5),
+	c.Logging.LogLevel = getEnvString("LOG_LEVEL", c.Logging.LogLevel)
+	c.Logging.LogFilePath = getEnvString("LOG_FILE_PATH", c.Logging.LogFilePath)
+	c.Logging.ConsoleLogging = getEnvBool("LOG_CONSOLE_ENABLED", c.Logging.ConsoleLogging)
+	c.Logging.FileLogging = getEnvBool("LOG_FILE_ENABLED", c.Logging.FileLogging)
+	c.Logging.MaxLogFileSize = getEnvInt("LOG_MAX_FILE_SIZE_MB", c.Logging.MaxLogFileSize)
+	c.Logging.MaxBackupFiles = getEnvInt("LOG_MAX_BACKUP_FILES", c.Logging.MaxBackupFiles)
+	c.Logging.LogConfigOnStart = getEnvBool("LOG_CONFIG_ON_START", c.Logging.LogConfigOnStart)
+
+	// Load Kelly Configuration
+	c.Kelly.Fraction = getEnvFloat("KELLY_FRACTION", c.Kelly.Fraction)
+	c.SizingStrategy = SizingStrategy(strings.ToUpper(getEnvString("SIZING_STRATEGY", string(c.SizingStrategy))))
+
+	// Load Copy Trading Configuration
+	c.CopyTrading.Enabled = getEnvBool("COPY_TRADING_ENABLED", c.CopyTrading.Enabled)
+	c.CopyTrading.LeaderAddresses = getEnvList("COPY_LEADER_ADDRESSES", c.CopyTrading.LeaderAddresses)
+	c.CopyTrading.CopyRatio = getEnvFloat("COPY_RATIO", c.CopyTrading.CopyRatio)
+	c.CopyTrading.MaxLeaders = getEnvInt("COPY_MAX_LEADERS", c.CopyTrading.MaxLeaders)
+	c.CopyTrading.MinLeaderPositionSize = getEnvFloat("COPY_MIN_LEADER_POSITION_SIZE", c.CopyTrading.MinLeaderPositionSize)
+	c.CopyTrading.LeaderScoreDecay = getEnvFloat("COPY_LEADER_SCORE_DECAY", c.CopyTrading.LeaderScoreDecay)
+	c.CopyTrading.CopyDelay = getEnvInt("COPY_DELAY_MS", c.CopyTrading.CopyDelay)
+	c.CopyTrading.CopyDelayJitter = getEnvInt("COPY_DELAY_JITTER_MS", c.CopyTrading.CopyDelayJitter)
+	c.CopyTrading.MaxCopiesPerMinute = getEnvInt("COPY_MAX_ORDERS_PER_MINUTE", c.CopyTrading.MaxCopiesPerMinute)
+	c.CopyTrading.MaxCopyPriceDeviation = getEnvFloat("COPY_MAX_PRICE_DEVIATION", c.CopyTrading.MaxCopyPriceDeviation)
+	c.CopyTrading.MaxCopyNotional = getEnvFloat("COPY_MAX_NOTIONAL", c.CopyTrading.MaxCopyNotional)
+	c.CopyTrading.SymbolAllowlist = getEnvList("COPY_SYMBOL_ALLOWLIST", c.CopyTrading.SymbolAllowlist)
+	c.CopyTrading.SymbolBlocklist = getEnvList("COPY_SYMBOL_BLOCKLIST", c.CopyTrading.SymbolBlocklist)
+
+	// Load Entry Filter Configuration
+	c.EntryFilters.TrendFilterEnabled = getEnvBool("TREND_FILTER_ENABLED", c.EntryFilters.TrendFilterEnabled)
+	c.EntryFilters.TrendFastPeriod = getEnvInt("TREND_FAST_PERIOD", c.EntryFilters.TrendFastPeriod)
+	c.EntryFilters.TrendSlowPeriod = getEnvInt("TREND_SLOW_PERIOD", c.EntryFilters.TrendSlowPeriod)
+	c.EntryFilters.RSIGuardEnabled = getEnvBool("RSI_GUARD_ENABLED", c.EntryFilters.RSIGuardEnabled)
+	c.EntryFilters.RSIPeriod = getEnvInt("RSI_PERIOD", c.EntryFilters.RSIPeriod)
+	c.EntryFilters.RSIOverbought = getEnvFloat("RSI_OVERBOUGHT", c.EntryFilters.RSIOverbought)
+	c.EntryFilters.RSIOversold = getEnvFloat("RSI_OVERSOLD", c.EntryFilters.RSIOversold)
+
+	// Load Scale-In Configuration
+	c.ScaleIn.Entries = getEnvInt("SCALE_IN_ENTRIES", c.ScaleIn.Entries)
+	c.ScaleIn.SpacingPercentage = getEnvFloat("SCALE_IN_SPACING_PERCENT", c.ScaleIn.SpacingPercentage)
+
+	// Load Entry Improvement Configuration
+	c.EntryImprovement.Enabled = getEnvBool("ENTRY_IMPROVEMENT_ENABLED", c.EntryImprovement.Enabled)
+	c.EntryImprovement.OffsetPercentage = getEnvFloat("ENTRY_IMPROVEMENT_OFFSET_PERCENT", c.EntryImprovement.OffsetPercentage)
+	c.EntryImprovement.Timeout = getEnvInt("ENTRY_IMPROVEMENT_TIMEOUT_SECONDS", c.EntryImprovement.Timeout)
+
+	// Load Paper Trading Configuration
+	c.PaperTrading.Enabled = getEnvBool("PAPER_TRADING_ENABLED", c.PaperTrading.Enabled)
+	c.PaperTrading.StartingBalance = getEnvFloat("PAPER_STARTING_BALANCE", c.PaperTrading.StartingBalance)
+
+	// Load Futures Configuration
+	c.Futures.Leverage = getEnvInt("FUTURES_LEVERAGE", c.Futures.Leverage)
+	c.Futures.MaintenanceMarginRate = getEnvFloat("FUTURES_MAINTENANCE_MARGIN_RATE", c.Futures.MaintenanceMarginRate)
+	for _, symbol := range c.Pairs() {
+		maxLeverage := getEnvInt("FUTURES_MAX_LEVERAGE_"+symbol, 0)
+		if maxLeverage == 0 {
+			continue
+		}
+		if c.Futures.SymbolMaxLeverage == nil {
+			c.Futures.SymbolMaxLeverage = make(map[string]int)
+		}
+		c.Futures.SymbolMaxLeverage[symbol] = maxLeverage
 	}
 
+	// Load Backtest Configuration
+	c.Backtest.ZeroVolumePolicy = ZeroVolumePolicy(strings.ToUpper(getEnvString("BACKTEST_ZERO_VOLUME_POLICY", string(c.Backtest.ZeroVolumePolicy))))
+
+	// Load BSC Configuration
+	c.BSC.NodeURL = getEnvString("BSC_NODE_URL", c.BSC.NodeURL)
+	c.BSC.TestnetURL = getEnvString("BSC_TESTNET_URL", c.BSC.TestnetURL)
+	c.BSC.Testnet = getEnvBool("TESTNET", c.BSC.Testnet)
+	c.BSC.MasterWalletAddress = getEnvString("MASTER_WALLET_ADDRESS", c.BSC.MasterWalletAddress)
+	c.BSC.FollowerPrivateKey = getEnvString("FOLLOWER_PRIVATE_KEY", c.BSC.FollowerPrivateKey)
+	c.BSC.CopyPercentage = getEnvFloat("COPY_PERCENTAGE", c.BSC.CopyPercentage)
+	c.BSC.TokenAddresses = getEnvList("TOKEN_ADDRESSES", c.BSC.TokenAddresses)
+	c.BSC.RouterAddress = getEnvString("ROUTER_ADDRESS", c.BSC.RouterAddress)
+	c.BSC.WBNBAddress = getEnvString("WBNB_ADDRESS", c.BSC.WBNBAddress)
+	c.BSC.GasPriceGwei = getEnvFloat("GAS_PRICE_GWEI", c.BSC.GasPriceGwei)
+	c.BSC.GasLimit = getEnvInt("GAS_LIMIT", c.BSC.GasLimit)
+
 	// Load General Configuration
-	config.RefreshInterval = getEnvInt("REFRESH_INTERVAL_SECONDS", 5)
-	config.DryRun = getEnvBool("DRY_RUN_MODE", false)
-	config.WebhookURL = os.Getenv("WEBHOOK_URL")
-	config.NotificationsEnabled = getEnvBool("NOTIFICATIONS_ENABLED", true)
+	c.RefreshInterval = getEnvInt("REFRESH_INTERVAL_SECONDS", c.RefreshInterval)
+	c.AdaptiveRefresh = getEnvBool("ADAPTIVE_REFRESH_ENABLED", c.AdaptiveRefresh)
+	c.MinRefreshInterval = getEnvInt("MIN_REFRESH_INTERVAL_SECONDS", c.MinRefreshInterval)
+	c.MaxRefreshInterval = getEnvInt("MAX_REFRESH_INTERVAL_SECONDS", c.MaxRefreshInterval)
+	c.AdaptiveRefreshVolatility = getEnvFloat("ADAPTIVE_REFRESH_VOLATILITY", c.AdaptiveRefreshVolatility)
+	c.FuturesMode = getEnvBool("FUTURES_MODE", c.FuturesMode)
+	c.MarketDataMode = MarketDataMode(strings.ToLower(getEnvString("MARKET_DATA_MODE", string(c.MarketDataMode))))
+	c.DryRun = getEnvBool("DRY_RUN_MODE", c.DryRun)
+	c.ExecutionMode = ExecutionMode(strings.ToUpper(getEnvString("EXECUTION_MODE", string(c.ExecutionMode))))
+	c.LivenessTimeout = getEnvInt("LIVENESS_TIMEOUT_MINUTES", c.LivenessTimeout)
+	c.ReconcileInterval = getEnvInt("RECONCILE_INTERVAL_SECONDS", c.ReconcileInterval)
+	c.ReconcileTolerance = getEnvFloat("RECONCILE_TOLERANCE", c.ReconcileTolerance)
+	c.ReconcileAutoCorrect = getEnvBool("RECONCILE_AUTO_CORRECT", c.ReconcileAutoCorrect)
+	c.DeadMansSwitchEnabled = getEnvBool("DEAD_MANS_SWITCH_ENABLED", c.DeadMansSwitchEnabled)
+	c.StateBackend = strings.ToLower(getEnvString("STATE_BACKEND", c.StateBackend))
+	c.StateDir = getEnvString("STATE_DIR", c.StateDir)
+	c.TradeHistoryPath = getEnvString("TRADE_HISTORY_PATH", c.TradeHistoryPath)
+	c.MetricsEnabled = getEnvBool("METRICS_ENABLED", c.MetricsEnabled)
+	c.MetricsPort = getEnvInt("METRICS_PORT", c.MetricsPort)
+	c.HealthEnabled = getEnvBool("HEALTH_ENABLED", c.HealthEnabled)
+	c.HealthPort = getEnvInt("HEALTH_PORT", c.HealthPort)
+	c.ControlToken = getEnvString("CONTROL_TOKEN", c.ControlToken)
+	c.WebhookURL = getEnvString("WEBHOOK_URL", c.WebhookURL)
+	c.WebhookFormat = WebhookFormat(strings.ToLower(getEnvString("WEBHOOK_FORMAT", string(c.WebhookFormat))))
+	c.WebhookTimeout = getEnvInt("WEBHOOK_TIMEOUT_SECONDS", c.WebhookTimeout)
+	c.NotificationsEnabled = getEnvBool("NOTIFICATIONS_ENABLED", c.NotificationsEnabled)
 
-	// Validate configuration
-	if err := config.Validate(); err != nil {
-		return nil, err
-	}
+	// Risk overrides are keyed by the trading pairs loaded above
+	return c.loadRiskOverrides()
+}
 
-	return config, nil
+// Warnings returns the settings that are valid but likely not what was intended, such as a
+// MaxCapitalPerTrade that MaxPositionSize always overrides at TotalCapital
+func (c *Config) Warnings() []string {
+	var warnings []string
+	if maxPositionValue := c.FixedCapital.TotalCapital * c.RiskManagement.MaxPositionSize * c.leverage(); c.FixedCapital.MaxCapitalPerTrade > maxPositionValue {
+		warnings = append(warnings, fmt.Sprintf("max capital per trade %f exceeds the max position size of %f at total capital; the position size limit applies",
+			c.FixedCapital.MaxCapitalPerTrade, maxPositionValue))
+	}
+	if c.CopyTrading.Enabled && (!c.HealthEnabled || c.ControlToken == "") {
+		warnings = append(warnings, "copy trading is enabled but leader signals are only accepted on POST /signal, which needs HEALTH_ENABLED and CONTROL_TOKEN")
+	}
+	return warnings
 }
 
 // Validate validates the configuration values
@@ -272,19 +844,47 @@ func (c *Config) Validate() error {
 	if c.FixedCapital.MaxWinRateThreshold <= 0 || c.FixedCapital.MaxWinRateThreshold > 1 {
 		return fmt.Errorf("max win rate must be between 0 and 1, got %f", c.FixedCapital.MaxWinRateThreshold)
 	}
+	if c.FixedCapital.DynamicAllocation {
+		if c.FixedCapital.MaxWinRateThreshold < c.FixedCapital.MinWinRateForIncrease {
+			return fmt.Errorf("max win rate cannot be less than min win rate")
+		}
+	}
+	if _, err := ParseCompoundingMode(string(c.FixedCapital.CompoundingMode)); err != nil {
+		return err
+	}
 
 	// Validate Multi-Tier Configuration
 	if c.MultiTier.Enabled {
 		if len(c.MultiTier.Tiers) == 0 {
 			return fmt.Errorf("at least one profit tier must be configured")
 		}
+		if c.MultiTier.MinSpacing < 0 {
+			return fmt.Errorf("tier min spacing must be non-negative, got %f", c.MultiTier.MinSpacing)
+		}
+		previousEnabled := -1
 		for i, tier := range c.MultiTier.Tiers {
-			if tier.ProfitPercentage <= 0 {
+			if tier.TargetPrice < 0 {
+				return fmt.Errorf("tier %d target price must be non-negative, got %f", i, tier.TargetPrice)
+			}
+			if tier.TargetPrice == 0 && tier.ProfitPercentage <= 0 {
 				return fmt.Errorf("tier %d profit percentage must be positive, got %f", i, tier.ProfitPercentage)
 			}
 			if tier.ClosePercentage <= 0 || tier.ClosePercentage > 1 {
 				return fmt.Errorf("tier %d close percentage must be between 0 and 1, got %f", i, tier.ClosePercentage)
 			}
+			// Absolute tiers are ordered against an entry price by ValidateTierTargets
+			if !tier.Enabled || tier.TargetPrice > 0 {
+				continue
+			}
+			if previousEnabled >= 0 && tier.ProfitPercentage <= c.MultiTier.Tiers[previousEnabled].ProfitPercentage {
+				return fmt.Errorf("tier %d profit percentage %f must be greater than tier %d profit percentage %f",
+					i, tier.ProfitPercentage, previousEnabled, c.MultiTier.Tiers[previousEnabled].ProfitPercentage)
+			}
+			if previousEnabled >= 0 && tier.ProfitPercentage-c.MultiTier.Tiers[previousEnabled].ProfitPercentage < c.MultiTier.MinSpacing-tierSpacingTolerance {
+				return fmt.Errorf("tiers %d and %d profit percentages %f and %f are closer than the minimum spacing %f",
+					previousEnabled, i, c.MultiTier.Tiers[previousEnabled].ProfitPercentage, tier.ProfitPercentage, c.MultiTier.MinSpacing)
+			}
+			previousEnabled = i
 		}
 		if c.MultiTier.MaxHoldTime <= 0 {
 			return fmt.Errorf("max hold time must be positive, got %d", c.MultiTier.MaxHoldTime)
@@ -295,8 +895,16 @@ func (c *Config) Validate() error {
 	}
 
 	// Validate Risk Management Configuration
-	if c.RiskManagement.MaxRiskPercentage <= 0 || c.RiskManagement.MaxRiskPercentage > 1 {
-		return fmt.Errorf("max risk percentage must be between 0 and 1, got %f", c.RiskManagement.MaxRiskPercentage)
+	if err := validateRiskLimits(c.RiskManagement); err != nil {
+		return err
+	}
+	// Fixed capital sizing must not risk more per trade than the stated maximum
+	if c.FixedCapital.RiskPercentage > c.RiskManagement.MaxRiskPercentage {
+		return fmt.Errorf("fixed capital risk percentage %f exceeds max risk percentage %f; lower FIXED_CAPITAL_RISK_PERCENT or raise RISK_MAX_RISK_PERCENT",
+			c.FixedCapital.RiskPercentage, c.RiskManagement.MaxRiskPercentage)
+	}
+	if err := c.validateRiskOverrides(); err != nil {
+		return err
 	}
 	if c.RiskManagement.MaxConsecutiveLosses <= 0 {
 		return fmt.Errorf("max consecutive losses must be positive, got %d", c.RiskManagement.MaxConsecutiveLosses)
@@ -307,19 +915,30 @@ func (c *Config) Validate() error {
 	if c.RiskManagement.MaxDailyLossPercentage <= 0 || c.RiskManagement.MaxDailyLossPercentage > 1 {
 		return fmt.Errorf("max daily loss percentage must be between 0 and 1, got %f", c.RiskManagement.MaxDailyLossPercentage)
 	}
-	if c.RiskManagement.StopLossPercentage < 0 || c.RiskManagement.StopLossPercentage > 1 {
-		return fmt.Errorf("stop loss percentage must be between 0 and 1, got %f", c.RiskManagement.StopLossPercentage)
+	if c.RiskManagement.DailyResetHourUTC < 0 || c.RiskManagement.DailyResetHourUTC > 23 {
+		return fmt.Errorf("daily reset hour must be between 0 and 23, got %d", c.RiskManagement.DailyResetHourUTC)
+	}
+	if c.RiskManagement.BreakEvenTrailingEnabled {
+		if !c.RiskManagement.BreakEvenStopEnabled {
+			return fmt.Errorf("break-even trailing requires the break-even stop to be enabled")
+		}
+		if c.MultiTier.TrailingStopPercentage <= 0 {
+			return fmt.Errorf("break-even trailing requires a positive trailing stop percentage, got %f", c.MultiTier.TrailingStopPercentage)
+		}
 	}
-	if c.RiskManagement.BreakEvenThreshold < 0 {
-		return fmt.Errorf("break-even threshold must be non-negative, got %f", c.RiskManagement.BreakEvenThreshold)
+	if c.RiskManagement.MaxBaseAssetExposure <= 0 || c.RiskManagement.MaxBaseAssetExposure > 1 {
+		return fmt.Errorf("max base asset exposure must be between 0 and 1, got %f", c.RiskManagement.MaxBaseAssetExposure)
 	}
-	if c.RiskManagement.MaxPositionSize <= 0 || c.RiskManagement.MaxPositionSize > 1 {
-		return fmt.Errorf("max position size must be between 0 and 1, got %f", c.RiskManagement.MaxPositionSize)
+	if c.RiskManagement.MaxTotalExposurePercentage <= 0 || c.RiskManagement.MaxTotalExposurePercentage > 1 {
+		return fmt.Errorf("max total exposure percentage must be greater than 0 and at most 1, got %f", c.RiskManagement.MaxTotalExposurePercentage)
 	}
 	if c.RiskManagement.CorrelationCheckEnabled {
 		if c.RiskManagement.MaxCorrelationThreshold < 0 || c.RiskManagement.MaxCorrelationThreshold > 1 {
 			return fmt.Errorf("max correlation threshold must be between 0 and 1, got %f", c.RiskManagement.MaxCorrelationThreshold)
 		}
+		if c.RiskManagement.CorrelationWindow < 2 {
+			return fmt.Errorf("correlation window must be at least 2, got %d", c.RiskManagement.CorrelationWindow)
+		}
 	}
 	if c.RiskManagement.DrawdownMonitoringEnabled {
 		if c.RiskManagement.MaxDrawdownPercentage <= 0 || c.RiskManagement.MaxDrawdownPercentage > 1 {
@@ -330,12 +949,50 @@ func (c *Config) Validate() error {
 		if c.RiskManagement.MinimumEquityLevel < 0 {
 			return fmt.Errorf("minimum equity level must be non-negative, got %f", c.RiskManagement.MinimumEquityLevel)
 		}
+		if c.RiskManagement.MinimumEquityRecoveryMargin < 0 {
+			return fmt.Errorf("minimum equity recovery margin must be non-negative, got %f", c.RiskManagement.MinimumEquityRecoveryMargin)
+		}
+	}
+	if c.RiskManagement.MaxReEntries < 0 {
+		return fmt.Errorf("max re-entries must be non-negative, got %d", c.RiskManagement.MaxReEntries)
+	}
+	if c.RiskManagement.ReEntryCooldown < 0 {
+		return fmt.Errorf("re-entry cooldown must be non-negative, got %d", c.RiskManagement.ReEntryCooldown)
+	}
+	if c.RiskManagement.MaxConcurrentPositions < 1 {
+		return fmt.Errorf("max concurrent positions must be at least 1, got %d", c.RiskManagement.MaxConcurrentPositions)
+	}
+	if _, err := ParseMaxPositionsPolicy(string(c.RiskManagement.MaxPositionsPolicy)); err != nil {
+		return err
+	}
+	if c.RiskManagement.MinRewardRiskRatio <= 0 {
+		return fmt.Errorf("min reward/risk ratio must be positive, got %f", c.RiskManagement.MinRewardRiskRatio)
+	}
+	if c.RiskManagement.ExpectedWinRate <= 0 || c.RiskManagement.ExpectedWinRate >= 1 {
+		return fmt.Errorf("expected win rate must be between 0 and 1, got %f", c.RiskManagement.ExpectedWinRate)
+	}
+	if _, err := ParseEquityBasis(string(c.RiskManagement.RiskEquityBasis)); err != nil {
+		return err
 	}
 
 	// Validate Trading Configuration
-	if c.Trading.TradingPair == "" {
+	pairs := c.Pairs()
+	if len(pairs) == 0 {
 		return fmt.Errorf("trading pair must be specified")
 	}
+	seenPairs := make(map[string]bool, len(pairs))
+	for i, pair := range pairs {
+		if pair == "" {
+			return fmt.Errorf("trading pair %d must not be empty", i)
+		}
+		if pair != strings.ToUpper(pair) {
+			return fmt.Errorf("trading pair %s must be uppercase", pair)
+		}
+		if seenPairs[pair] {
+			return fmt.Errorf("duplicate trading pair %s", pair)
+		}
+		seenPairs[pair] = true
+	}
 	if !c.Trading.TestnetEnabled && (c.Trading.APIKey == "" || c.Trading.APISecret == "") {
 		return fmt.Errorf("API key and secret must be provided for live trading")
 	}
@@ -354,14 +1011,66 @@ func (c *Config) Validate() error {
 	if c.Trading.OrderTimeout <= 0 {
 		return fmt.Errorf("order timeout must be positive, got %d", c.Trading.OrderTimeout)
 	}
+	orderType, err := ParseOrderType(string(c.Trading.OrderType))
+	if err != nil {
+		return err
+	}
+	if orderType != OrderTypeMarket && (c.Trading.LimitOffsetPercentage <= 0 || c.Trading.LimitOffsetPercentage >= 1) {
+		return fmt.Errorf("limit offset percentage must be between 0 and 1 for %s orders, got %f", orderType, c.Trading.LimitOffsetPercentage)
+	}
+	timeInForce, err := ParseTimeInForce(string(c.Trading.TimeInForce))
+	if err != nil {
+		return err
+	}
+	if orderType == OrderTypeMarket && timeInForce != TimeInForceGTC {
+		return fmt.Errorf("time in force %s only applies to limit-type orders, not %s orders", timeInForce, orderType)
+	}
+	if _, err := ParseRoundingMode(string(c.Trading.RoundingMode)); err != nil {
+		return err
+	}
+	if c.Trading.UseOCOExits && c.RiskManagement.StopLossPercentage <= 0 {
+		return fmt.Errorf("OCO exits require a stop loss percentage")
+	}
 	if c.Trading.MakerFee < 0 || c.Trading.MakerFee > 1 {
 		return fmt.Errorf("maker fee must be between 0 and 1, got %f", c.Trading.MakerFee)
 	}
 	if c.Trading.TakerFee < 0 || c.Trading.TakerFee > 1 {
 		return fmt.Errorf("taker fee must be between 0 and 1, got %f", c.Trading.TakerFee)
 	}
+	if err := validateFeeSchedule(c.Trading.FeeSchedule); err != nil {
+		return err
+	}
+	if c.Trading.MakerRebateRate < 0 || c.Trading.MakerRebateRate > 1 {
+		return fmt.Errorf("maker rebate rate must be between 0 and 1, got %f", c.Trading.MakerRebateRate)
+	}
+	if c.Trading.ChaseEnabled {
+		if c.Trading.MaxChaseDistance <= 0 || c.Trading.MaxChaseDistance > 1 {
+			return fmt.Errorf("max chase distance must be between 0 and 1, got %f", c.Trading.MaxChaseDistance)
+		}
+	}
+	if c.Trading.MaxClockSkew <= 0 {
+		return fmt.Errorf("max clock skew must be positive, got %d", c.Trading.MaxClockSkew)
+	}
+	if c.Trading.ClockSyncInterval <= 0 {
+		return fmt.Errorf("clock sync interval must be positive, got %d", c.Trading.ClockSyncInterval)
+	}
+	if c.Trading.Workers < 1 {
+		return fmt.Errorf("trading workers must be at least 1, got %d", c.Trading.Workers)
+	}
+	if c.Trading.APIWeightLimit <= 0 {
+		return fmt.Errorf("API weight limit must be positive, got %d", c.Trading.APIWeightLimit)
+	}
+	for symbol, filter := range c.Trading.SymbolFilters {
+		if filter.StepSize < 0 || filter.TickSize < 0 {
+			return fmt.Errorf("symbol filter for %s must have non-negative step and tick sizes, got %f and %f",
+				symbol, filter.StepSize, filter.TickSize)
+		}
+	}
 
 	// Validate Logging Configuration
+	if _, err := ParseLogLevel(c.Logging.LogLevel); err != nil {
+		return err
+	}
 	if c.Logging.LogFilePath == "" && c.Logging.FileLogging {
 		return fmt.Errorf("log file path must be specified when file logging is enabled")
 	}
@@ -372,37 +1081,282 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("max backup files must be non-negative, got %d", c.Logging.MaxBackupFiles)
 	}
 
+	// Validate Kelly Configuration
+	if c.Kelly.Fraction <= 0 || c.Kelly.Fraction > 1 {
+		return fmt.Errorf("kelly fraction must be greater than 0 and at most 1, got %f", c.Kelly.Fraction)
+	}
+	if _, err := ParseSizingStrategy(string(c.SizingStrategy)); err != nil {
+		return err
+	}
+
+	// Validate Copy Trading Configuration
+	if c.CopyTrading.Enabled && len(c.CopyTrading.LeaderAddresses) == 0 {
+		return fmt.Errorf("at least one leader address must be specified when copy trading is enabled")
+	}
+	seenLeaders := make(map[string]bool, len(c.CopyTrading.LeaderAddresses))
+	for _, address := range c.CopyTrading.LeaderAddresses {
+		if address == "" {
+			return fmt.Errorf("leader addresses cannot be empty")
+		}
+		if seenLeaders[strings.ToLower(address)] {
+			return fmt.Errorf("duplicate leader address %s", address)
+		}
+		seenLeaders[strings.ToLower(address)] = true
+	}
+	if c.CopyTrading.CopyRatio <= 0 || c.CopyTrading.CopyRatio > 10 {
+		return fmt.Errorf("copy ratio must be greater than 0 and at most 10, got %f", c.CopyTrading.CopyRatio)
+	}
+	if c.CopyTrading.MaxLeaders < 1 {
+		return fmt.Errorf("max leaders must be at least 1, got %d", c.CopyTrading.MaxLeaders)
+	}
+	if len(c.CopyTrading.LeaderAddresses) > c.CopyTrading.MaxLeaders {
+		return fmt.Errorf("%d leader addresses exceed max leaders %d", len(c.CopyTrading.LeaderAddresses), c.CopyTrading.MaxLeaders)
+	}
+	if c.CopyTrading.MinLeaderPositionSize < 0 {
+		return fmt.Errorf("min leader position size must be non-negative, got %f", c.CopyTrading.MinLeaderPositionSize)
+	}
+	if c.CopyTrading.LeaderScoreDecay <= 0 || c.CopyTrading.LeaderScoreDecay > 1 {
+		return fmt.Errorf("leader score decay must be greater than 0 and at most 1, got %f", c.CopyTrading.LeaderScoreDecay)
+	}
+	if c.CopyTrading.CopyDelay < 0 {
+		return fmt.Errorf("copy delay must be non-negative, got %d", c.CopyTrading.CopyDelay)
+	}
+	if c.CopyTrading.CopyDelayJitter < 0 {
+		return fmt.Errorf("copy delay jitter must be non-negative, got %d", c.CopyTrading.CopyDelayJitter)
+	}
+	if c.CopyTrading.MaxCopiesPerMinute < 0 {
+		return fmt.Errorf("max copies per minute must be non-negative, got %d", c.CopyTrading.MaxCopiesPerMinute)
+	}
+	if c.CopyTrading.MaxCopyPriceDeviation <= 0 || c.CopyTrading.MaxCopyPriceDeviation > 1 {
+		return fmt.Errorf("max copy price deviation must be greater than 0 and at most 1, got %f", c.CopyTrading.MaxCopyPriceDeviation)
+	}
+	if c.CopyTrading.MaxCopyNotional <= 0 {
+		return fmt.Errorf("max copy notional must be positive, got %f", c.CopyTrading.MaxCopyNotional)
+	}
+	for _, list := range []struct {
+		name    string
+		symbols []string
+	}{
+		{"allowlist", c.CopyTrading.SymbolAllowlist},
+		{"blocklist", c.CopyTrading.SymbolBlocklist},
+	} {
+		for _, symbol := range list.symbols {
+			if symbol == "" {
+				return fmt.Errorf("copy symbol %s entries cannot be empty", list.name)
+			}
+			if symbol != strings.ToUpper(symbol) {
+				return fmt.Errorf("copy symbol %s entry %s must be uppercase", list.name, symbol)
+			}
+		}
+	}
+
+	// Validate Entry Filter Configuration
+	if c.EntryFilters.TrendFilterEnabled {
+		if c.EntryFilters.TrendFastPeriod < 1 {
+			return fmt.Errorf("trend fast period must be at least 1, got %d", c.EntryFilters.TrendFastPeriod)
+		}
+		if c.EntryFilters.TrendSlowPeriod <= c.EntryFilters.TrendFastPeriod {
+			return fmt.Errorf("trend slow period %d must be greater than fast period %d", c.EntryFilters.TrendSlowPeriod, c.EntryFilters.TrendFastPeriod)
+		}
+	}
+	if c.EntryFilters.RSIGuardEnabled {
+		if c.EntryFilters.RSIPeriod < 2 {
+			return fmt.Errorf("RSI period must be at least 2, got %d", c.EntryFilters.RSIPeriod)
+		}
+		if c.EntryFilters.RSIOversold <= 0 || c.EntryFilters.RSIOverbought >= 100 {
+			return fmt.Errorf("RSI levels must be between 0 and 100, got oversold %f and overbought %f", c.EntryFilters.RSIOversold, c.EntryFilters.RSIOverbought)
+		}
+		if c.EntryFilters.RSIOversold >= c.EntryFilters.RSIOverbought {
+			return fmt.Errorf("RSI oversold %f must be below overbought %f", c.EntryFilters.RSIOversold, c.EntryFilters.RSIOverbought)
+		}
+	}
+
+	// Validate Scale-In Configuration
+	if c.ScaleIn.Entries < 1 {
+		return fmt.Errorf("scale-in entries must be at least 1, got %d", c.ScaleIn.Entries)
+	}
+	if c.ScaleIn.SpacingPercentage < 0 || c.ScaleIn.SpacingPercentage*float64(c.ScaleIn.Entries-1) >= 1 {
+		return fmt.Errorf("scale-in spacing %f must be non-negative and keep all %d entries above zero", c.ScaleIn.SpacingPercentage, c.ScaleIn.Entries)
+	}
+
+	// Validate Entry Improvement Configuration
+	if c.EntryImprovement.OffsetPercentage < 0 || c.EntryImprovement.OffsetPercentage >= 1 {
+		return fmt.Errorf("entry improvement offset must be at least 0 and below 1, got %f", c.EntryImprovement.OffsetPercentage)
+	}
+	if c.EntryImprovement.Enabled && c.EntryImprovement.Timeout <= 0 {
+		return fmt.Errorf("entry improvement timeout must be positive, got %d", c.EntryImprovement.Timeout)
+	}
+
+	// Validate Paper Trading Configuration
+	if c.PaperTrading.Enabled {
+		if c.PaperTrading.StartingBalance <= 0 {
+			return fmt.Errorf("paper starting balance must be positive, got %f", c.PaperTrading.StartingBalance)
+		}
+		if c.DryRun {
+			return fmt.Errorf("paper trading and dry run mode cannot both be enabled")
+		}
+	}
+
+	// Validate Futures Configuration
+	if c.Futures.Leverage < 1 {
+		return fmt.Errorf("futures leverage must be at least 1, got %d", c.Futures.Leverage)
+	}
+	for symbol, maxLeverage := range c.Futures.SymbolMaxLeverage {
+		if maxLeverage < 1 {
+			return fmt.Errorf("max leverage for %s must be at least 1, got %d", symbol, maxLeverage)
+		}
+	}
+	if c.Futures.MaintenanceMarginRate < 0 || c.Futures.MaintenanceMarginRate >= 1 {
+		return fmt.Errorf("maintenance margin rate must be at least 0 and below 1, got %f", c.Futures.MaintenanceMarginRate)
+	}
+	if c.FuturesMode {
+		// Shorts liquidate slightly closer to entry than longs, so check both sides
+		for _, side := range []string{SideLong, SideShort} {
+			if c.StopBeyondLiquidation(1, c.StopLossPrice(1, side), side) {
+				return fmt.Errorf("stop loss %f is beyond the %s liquidation distance at %dx leverage", c.RiskManagement.StopLossPercentage, side, c.Futures.Leverage)
+			}
+		}
+		for _, symbol := range c.Pairs() {
+			if c.Futures.Leverage > c.MaxLeverage(symbol) {
+				return fmt.Errorf("futures leverage %d exceeds the maximum of %d allowed for %s", c.Futures.Leverage, c.MaxLeverage(symbol), symbol)
+			}
+		}
+	}
+
+	// Validate Backtest Configuration
+	if _, err := ParseZeroVolumePolicy(string(c.Backtest.ZeroVolumePolicy)); err != nil {
+		return err
+	}
+
+	// Validate BSC Configuration
+	if c.BSC.Enabled() {
+		if err := c.BSC.validate(); err != nil {
+			return err
+		}
+	}
+
 	// Validate General Configuration
 	if c.RefreshInterval <= 0 {
 		return fmt.Errorf("refresh interval must be positive, got %d", c.RefreshInterval)
 	}
+	if c.AdaptiveRefresh {
+		if c.MinRefreshInterval <= 0 {
+			return fmt.Errorf("min refresh interval must be positive, got %d", c.MinRefreshInterval)
+		}
+		if c.MaxRefreshInterval < c.MinRefreshInterval {
+			return fmt.Errorf("max refresh interval cannot be less than min refresh interval")
+		}
+		if c.AdaptiveRefreshVolatility <= 0 {
+			return fmt.Errorf("adaptive refresh volatility must be positive, got %f", c.AdaptiveRefreshVolatility)
+		}
+	}
+	if _, err := ParseMarketDataMode(string(c.MarketDataMode)); err != nil {
+		return err
+	}
+	if _, err := ParseExecutionMode(string(c.ExecutionMode)); err != nil {
+		return err
+	}
+	if c.LivenessTimeout < 0 {
+		return fmt.Errorf("liveness timeout must be non-negative, got %d", c.LivenessTimeout)
+	}
+	if c.ReconcileInterval < 0 {
+		return fmt.Errorf("reconcile interval must be non-negative, got %d", c.ReconcileInterval)
+	}
+	if c.ReconcileTolerance < 0 || c.ReconcileTolerance >= 1 {
+		return fmt.Errorf("reconcile tolerance must be at least 0 and below 1, got %f", c.ReconcileTolerance)
+	}
+	if c.DeadMansSwitchEnabled && c.RiskManagement.StopLossPercentage <= 0 {
+		return fmt.Errorf("dead man's switch requires a stop loss percentage")
+	}
+	// The stop leg of an OCO exit already rests on the exchange; a second stop could overfill
+	if c.DeadMansSwitchEnabled && c.Trading.UseOCOExits {
+		return fmt.Errorf("dead man's switch and OCO exits both place exchange stops; enable only one")
+	}
+	if c.FuturesMode && c.Trading.UseOCOExits {
+		return fmt.Errorf("OCO exits are only available on spot")
+	}
+	if err := validateStateBackend(c.StateBackend); err != nil {
+		return err
+	}
+	if c.StateBackend == StateBackendFile && c.StateDir == "" {
+		return fmt.Errorf("state directory must be specified for the file state backend")
+	}
+	if c.MetricsEnabled && (c.MetricsPort < 1 || c.MetricsPort > 65535) {
+		return fmt.Errorf("metrics port must be between 1 and 65535, got %d", c.MetricsPort)
+	}
+	if c.HealthEnabled {
+		if c.HealthPort < 1 || c.HealthPort > 65535 {
+			return fmt.Errorf("health port must be between 1 and 65535, got %d", c.HealthPort)
+		}
+		if c.MetricsEnabled && c.HealthPort == c.MetricsPort {
+			return fmt.Errorf("health port %d conflicts with the metrics port", c.HealthPort)
+		}
+	}
+	if c.ControlToken != "" && !c.HealthEnabled {
+		return fmt.Errorf("control token requires the health server to be enabled")
+	}
+	if _, err := ParseWebhookFormat(string(c.WebhookFormat)); err != nil {
+		return err
+	}
+	if c.WebhookTimeout <= 0 {
+		return fmt.Errorf("webhook timeout must be positive, got %d", c.WebhookTimeout)
+	}
 
 	return nil
 }
 
+// Pairs returns the trading pairs to monitor
+func (c *Config) Pairs() []string {
+	if len(c.Trading.TradingPairs) > 0 {
+		return c.Trading.TradingPairs
+	}
+	if c.Trading.TradingPair == "" {
+		return nil
+	}
+	return []string{c.Trading.TradingPair}
+}
+
 // CalculateRiskCapital calculates the capital to risk based on fixed capital configuration
 func (c *Config) CalculateRiskCapital(currentEquity float64) float64 {
 	return currentEquity * c.FixedCapital.RiskPercentage
 }
 
-// CalculatePositionSize calculates the position size based on risk parameters
-func (c *Config) CalculatePositionSize(currentEquity float64, entryPrice float64, stopLossPrice float64) float64 {
+// CalculatePositionSize calculates the position size based on risk parameters for a long or
+// short position. It returns 0 for an unknown side and when the stop is not on the losing side
+// of the entry.
+func (c *Config) CalculatePositionSize(currentEquity float64, entryPrice float64, stopLossPrice float64, side string) float64 {
+	if !isKnownSide(side) || entryPrice <= 0 {
+		return 0
+	}
 	riskCapital := c.CalculateRiskCapital(currentEquity)
 	priceDifference := entryPrice - stopLossPrice
+	if !isBuy(side) {
+		priceDifference = stopLossPrice - entryPrice
+	}
 	if priceDifference <= 0 {
 		return 0
 	}
 	positionSize := riskCapital / priceDifference
-	maxPositionValue := currentEquity * c.RiskManagement.MaxPositionSize
-	maxPositionQuantity := maxPositionValue / entryPrice
+	maxPositionQuantity := c.EffectiveMaxTradeValue(currentEquity) / entryPrice
 	if positionSize > maxPositionQuantity {
 		return maxPositionQuantity
 	}
 	return positionSize
 }
 
-// IsWithinDailyLossLimit checks if trading can continue based on daily loss limit
+// EffectiveMaxTradeValue returns the largest notional a trade may take at currentEquity, the
+// tighter of MaxPositionSize of equity and MaxCapitalPerTrade. MaxPositionSize caps the margin,
+// so leverage raises its notional cap.
+func (c *Config) EffectiveMaxTradeValue(currentEquity float64) float64 {
+	return math.Min(currentEquity*c.RiskManagement.MaxPositionSize*c.leverage(), c.FixedCapital.MaxCapitalPerTrade)
+}
+
+// IsWithinDailyLossLimit checks if trading can continue based on daily loss limit. Without a
+// positive starting equity the loss cannot be measured and trading is refused.
 func (c *Config) IsWithinDailyLossLimit(startingEquity float64, currentEquity float64) bool {
+	if startingEquity <= 0 {
+		return false
+	}
 	loss := startingEquity - currentEquity
 	lossPercentage := loss / startingEquity
 	return lossPercentage <= c.RiskManagement.MaxDailyLossPercentage
@@ -453,6 +1407,18 @@ func getEnvInt(key string, defaultValue int) int {
 	return intValue
 }
 
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		result = append(result, strings.TrimSpace(item))
+	}
+	return result
+}
+
 func getEnvBool(key string, defaultValue bool) bool {
 	value := strings.ToLower(os.Getenv(key))
 	if value == "" {
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// secretConfigFields lists the dotted paths of configuration fields holding secrets
+var secretConfigFields = map[string]bool{
+	"Trading.APIKey":         true,
+	"Trading.APISecret":      true,
+	"WebhookURL":             true,
+	"ControlToken":           true,
+	"BSC.FollowerPrivateKey": true,
+}
+
+// ConfigChange is a configuration field that differs between two configurations
+type ConfigChange struct {
+	// Dotted field path such as RiskManagement.StopLossPercentage
+	Field string
+	Old   string
+	New   string
+}
+
+func (c ConfigChange) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Field, c.Old, c.New)
+}
+
+// Diff returns the fields of other that differ from c in declaration order. Slices and maps
+// are compared as a whole, and secret values are redacted.
+func (c *Config) Diff(other *Config) []ConfigChange {
+	var changes []ConfigChange
+	diffValues("", reflect.ValueOf(c).Elem(), reflect.ValueOf(other).Elem(), &changes)
+	return changes
+}
+
+// diffValues appends the differences between two values of the same type at path
+func diffValues(path string, old, updated reflect.Value, changes *[]ConfigChange) {
+	if old.Kind() == reflect.Struct {
+		for i := 0; i < old.NumField(); i++ {
+			field := old.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldPath := field.Name
+			if path != "" {
+				fieldPath = path + "." + field.Name
+			}
+			diffValues(fieldPath, old.Field(i), updated.Field(i), changes)
+		}
+		return
+	}
+
+	if reflect.DeepEqual(old.Interface(), updated.Interface()) {
+		return
+	}
+	change := ConfigChange{
+		Field: path,
+		Old:   fmt.Sprintf("%v", old.Interface()),
+		New:   fmt.Sprintf("%v", updated.Interface()),
+	}
+	if secretConfigFields[path] {
+		change.Old = redactConfigValue(change.Old)
+		change.New = redactConfigValue(change.New)
+	}
+	*changes = append(*changes, change)
+}
+
+// redactConfigValue redacts a non-empty secret value
+func redactConfigValue(value string) string {
+	if value == "" {
+		return value
+	}
+	return redactedValue
+}
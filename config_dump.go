@@ -0,0 +1,230 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// redactedValue replaces secret values in dumped configuration
+const redactedValue = "REDACTED"
+
+// secretEnvKeys lists the environment variables holding secrets
+var secretEnvKeys = map[string]bool{
+	"API_KEY":              true,
+	"API_SECRET":           true,
+	"WEBHOOK_URL":          true,
+	"CONTROL_TOKEN":        true,
+	"FOLLOWER_PRIVATE_KEY": true,
+}
+
+// DumpEnv returns every environment-configurable setting keyed by its environment variable,
+// so loading them into a fresh process reproduces this configuration. Secrets are redacted
+// unless includeSecrets is set.
+func (c *Config) DumpEnv(includeSecrets bool) map[string]string {
+	env := map[string]string{
+		// Fixed Capital Configuration
+		"FIXED_CAPITAL_TOTAL":              formatEnvFloat(c.FixedCapital.TotalCapital),
+		"FIXED_CAPITAL_RISK_PERCENT":       formatEnvFloat(c.FixedCapital.RiskPercentage),
+		"FIXED_CAPITAL_MINIMUM":            formatEnvFloat(c.FixedCapital.MinimumCapital),
+		"FIXED_CAPITAL_MAX_PER_TRADE":      formatEnvFloat(c.FixedCapital.MaxCapitalPerTrade),
+		"FIXED_CAPITAL_DYNAMIC_ALLOCATION": strconv.FormatBool(c.FixedCapital.DynamicAllocation),
+		"FIXED_CAPITAL_MIN_WIN_RATE":       formatEnvFloat(c.FixedCapital.MinWinRateForIncrease),
+		"FIXED_CAPITAL_MAX_WIN_RATE":       formatEnvFloat(c.FixedCapital.MaxWinRateThreshold),
+		"FIXED_CAPITAL_COMPOUNDING_MODE":   string(c.FixedCapital.CompoundingMode),
+
+		// Multi-Tier Configuration
+		"MULTI_TIER_ENABLED":          strconv.FormatBool(c.MultiTier.Enabled),
+		"MULTI_TIER_CLOSE_ON_TIMEOUT": strconv.FormatBool(c.MultiTier.CloseOnTimeout),
+		"MULTI_TIER_MAX_HOLD_TIME":    strconv.Itoa(c.MultiTier.MaxHoldTime),
+		"MULTI_TIER_TRAILING_STOP":    formatEnvFloat(c.MultiTier.TrailingStopPercentage),
+		"MULTI_TIER_MIN_SPACING":      formatEnvFloat(c.MultiTier.MinSpacing),
+		"MULTI_TIER_AUTO_SORT":        strconv.FormatBool(c.MultiTier.AutoSort),
+
+		// Risk Management Configuration
+		"RISK_MAX_RISK_PERCENT":            formatEnvFloat(c.RiskManagement.MaxRiskPercentage),
+		"RISK_MAX_CONSECUTIVE_LOSSES":      strconv.Itoa(c.RiskManagement.MaxConsecutiveLosses),
+		"RISK_PAUSE_DURATION_MINUTES":      strconv.Itoa(c.RiskManagement.PauseDuration),
+		"RISK_MAX_DAILY_LOSS_PERCENT":      formatEnvFloat(c.RiskManagement.MaxDailyLossPercentage),
+		"RESET_HOUR_UTC":                   strconv.Itoa(c.RiskManagement.DailyResetHourUTC),
+		"ATR_STOP_MULTIPLIER":              formatEnvFloat(c.RiskManagement.ATRStopMultiplier),
+		"RISK_STOP_LOSS_PERCENT":           formatEnvFloat(c.RiskManagement.StopLossPercentage),
+		"RISK_SOFT_LOSS_PERCENT":           formatEnvFloat(c.RiskManagement.SoftLossPercentage),
+		"RISK_BREAK_EVEN_STOP_ENABLED":     strconv.FormatBool(c.RiskManagement.BreakEvenStopEnabled),
+		"RISK_BREAK_EVEN_THRESHOLD":        formatEnvFloat(c.RiskManagement.BreakEvenThreshold),
+		"RISK_BREAK_EVEN_TRAILING_ENABLED": strconv.FormatBool(c.RiskManagement.BreakEvenTrailingEnabled),
+		"RISK_MAX_POSITION_SIZE":           formatEnvFloat(c.RiskManagement.MaxPositionSize),
+		"RISK_MAX_BASE_ASSET_EXPOSURE":     formatEnvFloat(c.RiskManagement.MaxBaseAssetExposure),
+		"RISK_MAX_TOTAL_EXPOSURE_PERCENT":  formatEnvFloat(c.RiskManagement.MaxTotalExposurePercentage),
+		"RISK_CORRELATION_CHECK_ENABLED":   strconv.FormatBool(c.RiskManagement.CorrelationCheckEnabled),
+		"RISK_MAX_CORRELATION_THRESHOLD":   formatEnvFloat(c.RiskManagement.MaxCorrelationThreshold),
+		"RISK_CORRELATION_WINDOW":          strconv.Itoa(c.RiskManagement.CorrelationWindow),
+		"RISK_DRAWDOWN_MONITORING_ENABLED": strconv.FormatBool(c.RiskManagement.DrawdownMonitoringEnabled),
+		"RISK_MAX_DRAWDOWN_PERCENT":        formatEnvFloat(c.RiskManagement.MaxDrawdownPercentage),
+		"RISK_EQUITY_PROTECTION_ENABLED":   strconv.FormatBool(c.RiskManagement.EquityProtectionEnabled),
+		"RISK_MINIMUM_EQUITY_LEVEL":        formatEnvFloat(c.RiskManagement.MinimumEquityLevel),
+		"MINIMUM_EQUITY_RECOVERY_MARGIN":   formatEnvFloat(c.RiskManagement.MinimumEquityRecoveryMargin),
+		"RISK_MAX_RE_ENTRIES":              strconv.Itoa(c.RiskManagement.MaxReEntries),
+		"RE_ENTRY_COOLDOWN_SECONDS":        strconv.Itoa(c.RiskManagement.ReEntryCooldown),
+		"MAX_CONCURRENT_POSITIONS":         strconv.Itoa(c.RiskManagement.MaxConcurrentPositions),
+		"MAX_POSITIONS_POLICY":             string(c.RiskManagement.MaxPositionsPolicy),
+		"RISK_MIN_REWARD_RISK_RATIO":       formatEnvFloat(c.RiskManagement.MinRewardRiskRatio),
+		"RISK_EXPECTED_WIN_RATE":           formatEnvFloat(c.RiskManagement.ExpectedWinRate),
+		"RISK_EQUITY_BASIS":                string(c.RiskManagement.RiskEquityBasis),
+
+		// Trading Configuration
+		"TRADING_PAIR":                        c.Trading.TradingPair,
+		"TRADING_PAIRS":                       strings.Join(c.Trading.TradingPairs, ","),
+		"API_KEY":                             c.Trading.APIKey,
+		"API_SECRET":                          c.Trading.APISecret,
+		"TRADING_TESTNET_ENABLED":             strconv.FormatBool(c.Trading.TestnetEnabled),
+		"TRADING_MIN_ORDER_QUANTITY":          formatEnvFloat(c.Trading.MinOrderQuantity),
+		"TRADING_MAX_ORDER_QUANTITY":          formatEnvFloat(c.Trading.MaxOrderQuantity),
+		"TRADING_SLIPPAGE_TOLERANCE":          formatEnvFloat(c.Trading.SlippageTolerance),
+		"TRADING_ORDER_TIMEOUT_SECONDS":       strconv.Itoa(c.Trading.OrderTimeout),
+		"ORDER_TYPE":                          string(c.Trading.OrderType),
+		"LIMIT_OFFSET_PERCENTAGE":             formatEnvFloat(c.Trading.LimitOffsetPercentage),
+		"ORDER_TIME_IN_FORCE":                 string(c.Trading.TimeInForce),
+		"QUANTITY_ROUNDING_MODE":              string(c.Trading.RoundingMode),
+		"TRADING_ORDER_VALIDATION_ENABLED":    strconv.FormatBool(c.Trading.OrderValidationEnabled),
+		"REDUCE_ONLY_EXITS":                   strconv.FormatBool(c.Trading.ReduceOnlyExits),
+		"USE_OCO_EXITS":                       strconv.FormatBool(c.Trading.UseOCOExits),
+		"TRADING_MAKER_FEE":                   formatEnvFloat(c.Trading.MakerFee),
+		"TRADING_TAKER_FEE":                   formatEnvFloat(c.Trading.TakerFee),
+		"TRADING_FEE_TIERS":                   formatFeeTiers(c.Trading.FeeSchedule.Tiers),
+		"TRADING_BNB_FEE_DISCOUNT":            strconv.FormatBool(c.Trading.FeeSchedule.UseBNBDiscount),
+		"TRADING_VOLUME_30D":                  formatEnvFloat(c.Trading.FeeSchedule.Volume30d),
+		"TRADING_MAKER_REBATE_RATE":           formatEnvFloat(c.Trading.MakerRebateRate),
+		"TRADING_CHASE_ENABLED":               strconv.FormatBool(c.Trading.ChaseEnabled),
+		"TRADING_MAX_CHASE_DISTANCE":          formatEnvFloat(c.Trading.MaxChaseDistance),
+		"TRADING_MAX_CLOCK_SKEW_MS":           strconv.Itoa(c.Trading.MaxClockSkew),
+		"TRADING_CLOCK_SYNC_INTERVAL_SECONDS": strconv.Itoa(c.Trading.ClockSyncInterval),
+		"TRADING_WORKERS":                     strconv.Itoa(c.Trading.Workers),
+		"API_WEIGHT_LIMIT":                    strconv.Itoa(c.Trading.APIWeightLimit),
+
+		// Logging Configuration
+		"LOG_LEVEL":            c.Logging.LogLevel,
+		"LOG_FILE_PATH":        c.Logging.LogFilePath,
+		"LOG_CONSOLE_ENABLED":  strconv.FormatBool(c.Logging.ConsoleLogging),
+		"LOG_FILE_ENABLED":     strconv.FormatBool(c.Logging.FileLogging),
+		"LOG_MAX_FILE_SIZE_MB": strconv.Itoa(c.Logging.MaxLogFileSize),
+		"LOG_MAX_BACKUP_FILES": strconv.Itoa(c.Logging.MaxBackupFiles),
+		"LOG_CONFIG_ON_START":  strconv.FormatBool(c.Logging.LogConfigOnStart),
+
+		// Kelly Configuration
+		"KELLY_FRACTION":  formatEnvFloat(c.Kelly.Fraction),
+		"SIZING_STRATEGY": string(c.SizingStrategy),
+
+		// Copy Trading Configuration
+		"COPY_TRADING_ENABLED":          strconv.FormatBool(c.CopyTrading.Enabled),
+		"COPY_LEADER_ADDRESSES":         strings.Join(c.CopyTrading.LeaderAddresses, ","),
+		"COPY_RATIO":                    formatEnvFloat(c.CopyTrading.CopyRatio),
+		"COPY_MAX_LEADERS":              strconv.Itoa(c.CopyTrading.MaxLeaders),
+		"COPY_MIN_LEADER_POSITION_SIZE": formatEnvFloat(c.CopyTrading.MinLeaderPositionSize),
+		"COPY_LEADER_SCORE_DECAY":       formatEnvFloat(c.CopyTrading.LeaderScoreDecay),
+		"COPY_DELAY_MS":                 strconv.Itoa(c.CopyTrading.CopyDelay),
+		"COPY_DELAY_JITTER_MS":          strconv.Itoa(c.CopyTrading.CopyDelayJitter),
+		"COPY_MAX_ORDERS_PER_MINUTE":    strconv.Itoa(c.CopyTrading.MaxCopiesPerMinute),
+		"COPY_MAX_PRICE_DEVIATION":      formatEnvFloat(c.CopyTrading.MaxCopyPriceDeviation),
+		"COPY_MAX_NOTIONAL":             formatEnvFloat(c.CopyTrading.MaxCopyNotional),
+		"COPY_SYMBOL_ALLOWLIST":         strings.Join(c.CopyTrading.SymbolAllowlist, ","),
+		"COPY_SYMBOL_BLOCKLIST":         strings.Join(c.CopyTrading.SymbolBlocklist, ","),
+
+		// Entry Filter Configuration
+		"TREND_FILTER_ENABLED": strconv.FormatBool(c.EntryFilters.TrendFilterEnabled),
+		"TREND_FAST_PERIOD":    strconv.Itoa(c.EntryFilters.TrendFastPeriod),
+		"TREND_SLOW_PERIOD":    strconv.Itoa(c.EntryFilters.TrendSlowPeriod),
+		"RSI_GUARD_ENABLED":    strconv.FormatBool(c.EntryFilters.RSIGuardEnabled),
+		"RSI_PERIOD":           strconv.Itoa(c.EntryFilters.RSIPeriod),
+		"RSI_OVERBOUGHT":       formatEnvFloat(c.EntryFilters.RSIOverbought),
+		"RSI_OVERSOLD":         formatEnvFloat(c.EntryFilters.RSIOversold),
+
+		// Scale-In Configuration
+		"SCALE_IN_ENTRIES":         strconv.Itoa(c.ScaleIn.Entries),
+		"SCALE_IN_SPACING_PERCENT": formatEnvFloat(c.ScaleIn.SpacingPercentage),
+
+		// Entry Improvement Configuration
+		"ENTRY_IMPROVEMENT_ENABLED":         strconv.FormatBool(c.EntryImprovement.Enabled),
+		"ENTRY_IMPROVEMENT_OFFSET_PERCENT":  formatEnvFloat(c.EntryImprovement.OffsetPercentage),
+		"ENTRY_IMPROVEMENT_TIMEOUT_SECONDS": strconv.Itoa(c.EntryImprovement.Timeout),
+
+		// Paper Trading Configuration
+		"PAPER_TRADING_ENABLED":  strconv.FormatBool(c.PaperTrading.Enabled),
+		"PAPER_STARTING_BALANCE": formatEnvFloat(c.PaperTrading.StartingBalance),
+
+		// Futures Configuration
+		"FUTURES_LEVERAGE":                strconv.Itoa(c.Futures.Leverage),
+		"FUTURES_MAINTENANCE_MARGIN_RATE": formatEnvFloat(c.Futures.MaintenanceMarginRate),
+
+		// Backtest Configuration
+		"BACKTEST_ZERO_VOLUME_POLICY": string(c.Backtest.ZeroVolumePolicy),
+
+		// BSC Configuration
+		"BSC_NODE_URL":          c.BSC.NodeURL,
+		"BSC_TESTNET_URL":       c.BSC.TestnetURL,
+		"TESTNET":               strconv.FormatBool(c.BSC.Testnet),
+		"MASTER_WALLET_ADDRESS": c.BSC.MasterWalletAddress,
+		"FOLLOWER_PRIVATE_KEY":  c.BSC.FollowerPrivateKey,
+		"COPY_PERCENTAGE":       formatEnvFloat(c.BSC.CopyPercentage),
+		"TOKEN_ADDRESSES":       strings.Join(c.BSC.TokenAddresses, ","),
+		"ROUTER_ADDRESS":        c.BSC.RouterAddress,
+		"WBNB_ADDRESS":          c.BSC.WBNBAddress,
+		"GAS_PRICE_GWEI":        formatEnvFloat(c.BSC.GasPriceGwei),
+		"GAS_LIMIT":             strconv.Itoa(c.BSC.GasLimit),
+
+		// General Configuration
+		"REFRESH_INTERVAL_SECONDS":     strconv.Itoa(c.RefreshInterval),
+		"ADAPTIVE_REFRESH_ENABLED":     strconv.FormatBool(c.AdaptiveRefresh),
+		"MIN_REFRESH_INTERVAL_SECONDS": strconv.Itoa(c.MinRefreshInterval),
+		"MAX_REFRESH_INTERVAL_SECONDS": strconv.Itoa(c.MaxRefreshInterval),
+		"ADAPTIVE_REFRESH_VOLATILITY":  formatEnvFloat(c.AdaptiveRefreshVolatility),
+		"DRY_RUN_MODE":                 strconv.FormatBool(c.DryRun),
+		"FUTURES_MODE":                 strconv.FormatBool(c.FuturesMode),
+		"MARKET_DATA_MODE":             string(c.MarketDataMode),
+		"EXECUTION_MODE":               string(c.ExecutionMode),
+		"LIVENESS_TIMEOUT_MINUTES":     strconv.Itoa(c.LivenessTimeout),
+		"RECONCILE_INTERVAL_SECONDS":   strconv.Itoa(c.ReconcileInterval),
+		"RECONCILE_TOLERANCE":          formatEnvFloat(c.ReconcileTolerance),
+		"RECONCILE_AUTO_CORRECT":       strconv.FormatBool(c.ReconcileAutoCorrect),
+		"DEAD_MANS_SWITCH_ENABLED":     strconv.FormatBool(c.DeadMansSwitchEnabled),
+		"STATE_BACKEND":                c.StateBackend,
+		"TRADE_HISTORY_PATH":           c.TradeHistoryPath,
+		"STATE_DIR":                    c.StateDir,
+		"METRICS_ENABLED":              strconv.FormatBool(c.MetricsEnabled),
+		"METRICS_PORT":                 strconv.Itoa(c.MetricsPort),
+		"HEALTH_ENABLED":               strconv.FormatBool(c.HealthEnabled),
+		"HEALTH_PORT":                  strconv.Itoa(c.HealthPort),
+		"CONTROL_TOKEN":                c.ControlToken,
+		"WEBHOOK_URL":                  c.WebhookURL,
+		"WEBHOOK_FORMAT":               string(c.WebhookFormat),
+		"WEBHOOK_TIMEOUT_SECONDS":      strconv.Itoa(c.WebhookTimeout),
+		"NOTIFICATIONS_ENABLED":        strconv.FormatBool(c.NotificationsEnabled),
+	}
+
+	for symbol, filter := range c.Trading.SymbolFilters {
+		env["SYMBOL_FILTER_"+symbol+"_STEP_SIZE"] = formatEnvFloat(filter.StepSize)
+		env["SYMBOL_FILTER_"+symbol+"_TICK_SIZE"] = formatEnvFloat(filter.TickSize)
+	}
+
+	for symbol, maxLeverage := range c.Futures.SymbolMaxLeverage {
+		env["FUTURES_MAX_LEVERAGE_"+symbol] = strconv.Itoa(maxLeverage)
+	}
+
+	for key, value := range c.riskOverrideEnv() {
+		env[key] = value
+	}
+
+	if !includeSecrets {
+		for key := range secretEnvKeys {
+			if env[key] != "" {
+				env[key] = redactedValue
+			}
+		}
+	}
+
+	return env
+}
+
+// formatEnvFloat formats a float so it parses back to the identical value
+func formatEnvFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
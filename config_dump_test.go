@@ -0,0 +1,51 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDumpEnvRoundTrips(t *testing.T) {
+	want := DefaultConfig()
+	// Move every dumped scalar away from its default so a dropped or misparsed key shows up
+	dumped := want.envKeysByField()
+	root := reflect.ValueOf(want).Elem()
+	forEachConfigField(root, "", nil, func(path string, index []int) {
+		if _, ok := dumped[path]; !ok {
+			return
+		}
+		value := root.FieldByIndex(index)
+		switch value.Kind() {
+		case reflect.Bool, reflect.Int, reflect.Float64:
+			perturbConfigValue(value)
+		}
+	})
+
+	for key, value := range want.DumpEnv(true) {
+		t.Setenv(key, value)
+	}
+	got := DefaultConfig()
+	if err := got.applyEnv(); err != nil {
+		t.Fatalf("applyEnv: %v", err)
+	}
+	for _, change := range want.Diff(got) {
+		t.Errorf("not reproduced from the dump: %s", change)
+	}
+}
+
+func TestDumpEnvRedactsSecrets(t *testing.T) {
+	c := DefaultConfig()
+	c.Trading.APIKey = "key"
+	c.Trading.APISecret = "secret"
+	c.WebhookURL = "https://hooks.example.com/token"
+
+	redacted := c.DumpEnv(false)
+	for key := range secretEnvKeys {
+		if value, ok := redacted[key]; ok && value != "" && value != redactedValue {
+			t.Errorf("%s = %q, want it redacted", key, value)
+		}
+	}
+	if full := c.DumpEnv(true); full["API_SECRET"] != "secret" {
+		t.Errorf("API_SECRET with secrets = %q, want secret", full["API_SECRET"])
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConfigFromFile loads configuration from a YAML or JSON file, detected by extension.
+// Keys match the Config field names (lowercased for YAML). Values not present in the file
+// keep their defaults, and environment variables override values from the file.
+func LoadConfigFromFile(path string) (*Config, error) {
+	// Load .env file if it exists
+	_ = godotenv.Load()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %v", path, err)
+	}
+
+	config := DefaultConfig()
+	// Trading pair has no file default so a missing value is reported
+	config.Trading.TradingPair = ""
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, config)
+	case ".json":
+		err = json.Unmarshal(data, config)
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q, expected .yaml, .yml or .json", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("malformed config file %s: %v", path, err)
+	}
+
+	if err := config.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	if len(config.Pairs()) == 0 {
+		return nil, fmt.Errorf("config file %s is missing required field Trading.TradingPair", path)
+	}
+
+	// Sort tiers before validation so the ascending check passes
+	if config.MultiTier.AutoSort {
+		config.MultiTier.SortTiers()
+	}
+
+	// Validate configuration
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfigFile writes contents to a file named name in a temporary directory
+func writeConfigFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfigFromFileEnvOverridesFile(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{
+		"Trading": {"TradingPair": "ETHUSDT", "TestnetEnabled": true},
+		"RiskManagement": {"StopLossPercentage": 0.03, "MaxRiskPercentage": 0.03}
+	}`)
+	t.Setenv("TRADING_PAIR", "SOLUSDT")
+	t.Setenv("RISK_STOP_LOSS_PERCENT", "0.01")
+
+	c, err := LoadConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile: %v", err)
+	}
+	if c.Trading.TradingPair != "SOLUSDT" {
+		t.Errorf("TradingPair = %q, want the environment's SOLUSDT", c.Trading.TradingPair)
+	}
+	if c.RiskManagement.StopLossPercentage != 0.01 {
+		t.Errorf("StopLossPercentage = %f, want the environment's 0.01", c.RiskManagement.StopLossPercentage)
+	}
+	if c.RiskManagement.MaxRiskPercentage != 0.03 {
+		t.Errorf("MaxRiskPercentage = %f, want the file's 0.03", c.RiskManagement.MaxRiskPercentage)
+	}
+	if !c.Trading.TestnetEnabled {
+		t.Error("TestnetEnabled from the file was lost")
+	}
+	if want := DefaultConfig().RiskManagement.MaxDailyLossPercentage; c.RiskManagement.MaxDailyLossPercentage != want {
+		t.Errorf("MaxDailyLossPercentage = %f, want the default %f", c.RiskManagement.MaxDailyLossPercentage, want)
+	}
+}
+
+func TestLoadConfigFromFileYAML(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "trading:\n  tradingpair: ETHUSDT\n  testnetenabled: true\n")
+	c, err := LoadConfigFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile: %v", err)
+	}
+	if c.Trading.TradingPair != "ETHUSDT" {
+		t.Errorf("TradingPair = %q, want ETHUSDT", c.Trading.TradingPair)
+	}
+}
+
+func TestLoadConfigFromFileRejectsBadFiles(t *testing.T) {
+	tests := []struct {
+		name, file, contents, want string
+	}{
+		{"malformed json", "config.json", `{"Trading": {"TradingPair": "ETHUSDT",}`, "malformed config file"},
+		{"malformed yaml", "config.yaml", "trading: [unclosed\n", "malformed config file"},
+		{"unsupported extension", "config.toml", "", "unsupported config file extension"},
+		{"missing trading pair", "config.json", `{"Trading": {"TestnetEnabled": true}}`, "TradingPair"},
+	}
+	for _, tt := range tests {
+		path := writeConfigFile(t, tt.file, tt.contents)
+		if _, err := LoadConfigFromFile(path); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: LoadConfigFromFile error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+	if _, err := LoadConfigFromFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("LoadConfigFromFile accepted a missing file")
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// generalSection groups the top-level fields that belong to no section in Summary
+const generalSection = "General"
+
+// Value sources marked in Summary
+const (
+	sourceEnv     = "env"
+	sourceFile    = "file"
+	sourceDefault = "default"
+)
+
+// Summary returns a human-readable dump of every configuration value grouped by section,
+// with secrets redacted. Each value is marked (env) when an environment variable setting it
+// is present, (file) when it differs from DefaultConfig without one, such as a value read
+// from a config file, and (default) otherwise.
+func (c *Config) Summary() string {
+	sources := c.valueSources()
+
+	var general, sections strings.Builder
+	value := reflect.ValueOf(c).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct {
+			fmt.Fprintf(&sections, "[%s]\n", field.Name)
+			writeSummaryValues(&sections, field.Name, "", value.Field(i), sources)
+			continue
+		}
+		writeSummaryValues(&general, "", field.Name, value.Field(i), sources)
+	}
+	if general.Len() > 0 {
+		fmt.Fprintf(&sections, "[%s]\n%s", generalSection, general.String())
+	}
+	return sections.String()
+}
+
+// valueSources returns the source of every field that is not a default, keyed by field path
+func (c *Config) valueSources() map[string]string {
+	sources := make(map[string]string)
+	for _, change := range DefaultConfig().Diff(c) {
+		sources[change.Field] = sourceFile
+	}
+	for path, keys := range c.envKeysByField() {
+		for _, key := range keys {
+			// The getEnv helpers treat an empty variable as unset
+			if value, ok := os.LookupEnv(key); ok && value != "" {
+				sources[path] = sourceEnv
+				break
+			}
+		}
+	}
+	return sources
+}
+
+// envKeysByField maps each field path to the environment variables DumpEnv writes it to,
+// found by changing each field of a copy of c in turn and seeing which variables change
+func (c *Config) envKeysByField() map[string][]string {
+	base := c.DumpEnv(true)
+	keys := make(map[string][]string)
+	forEachConfigField(reflect.ValueOf(c).Elem(), "", nil, func(path string, index []int) {
+		changed := *c
+		if !perturbConfigValue(reflect.ValueOf(&changed).Elem().FieldByIndex(index)) {
+			return
+		}
+		dump := changed.DumpEnv(true)
+		for key, value := range base {
+			if dumped, ok := dump[key]; !ok || dumped != value {
+				keys[path] = append(keys[path], key)
+			}
+		}
+		for key := range dump {
+			if _, ok := base[key]; !ok {
+				keys[path] = append(keys[path], key)
+			}
+		}
+	})
+	return keys
+}
+
+// forEachConfigField calls fn with the path and index of every exported leaf field below value
+func forEachConfigField(value reflect.Value, path string, index []int, fn func(path string, index []int)) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+		if field.Type.Kind() == reflect.Struct {
+			forEachConfigField(value.Field(i), fieldPath, fieldIndex, fn)
+			continue
+		}
+		fn(fieldPath, fieldIndex)
+	}
+}
+
+// perturbConfigValue sets value to a different value without touching data shared with the
+// original config, reporting false for kinds it cannot change
+func perturbConfigValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Bool:
+		value.SetBool(!value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value.SetInt(value.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value.SetUint(value.Uint() + 1)
+	case reflect.Float32, reflect.Float64:
+		value.SetFloat(value.Float() + 1)
+	case reflect.String:
+		value.SetString(value.String() + "x")
+	case reflect.Slice:
+		if value.Len() > 0 {
+			value.Set(reflect.Zero(value.Type()))
+		} else {
+			value.Set(reflect.MakeSlice(value.Type(), 1, 1))
+		}
+	case reflect.Map:
+		// Map entries are written to per-key variables, which only exist for present entries
+		if value.Len() == 0 {
+			return false
+		}
+		value.Set(reflect.Zero(value.Type()))
+	default:
+		return false
+	}
+	return true
+}
+
+// writeSummaryValues writes one line per leaf value below path, named relative to its section
+func writeSummaryValues(b *strings.Builder, section, name string, value reflect.Value, sources map[string]string) {
+	path := name
+	if section != "" {
+		path = section + "." + name
+	}
+
+	if value.Kind() == reflect.Struct {
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldName := field.Name
+			if name != "" {
+				fieldName = name + "." + field.Name
+			}
+			writeSummaryValues(b, section, fieldName, value.Field(i), sources)
+		}
+		return
+	}
+
+	formatted := fmt.Sprintf("%v", value.Interface())
+	if secretConfigFields[path] {
+		formatted = redactConfigValue(formatted)
+	}
+	source, ok := sources[path]
+	if !ok {
+		source = sourceDefault
+	}
+	fmt.Fprintf(b, "  %s = %s (%s)\n", name, formatted, source)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// summaryLine returns the Summary line of a field in section, or "" when missing
+func summaryLine(summary, section, name string) string {
+	inSection := false
+	for _, line := range strings.Split(summary, "\n") {
+		if strings.HasPrefix(line, "[") {
+			inSection = line == "["+section+"]"
+			continue
+		}
+		if inSection && strings.HasPrefix(line, "  "+name+" = ") {
+			return line
+		}
+	}
+	return ""
+}
+
+func TestSummaryMarksValueSources(t *testing.T) {
+	c := DefaultConfig()
+	// Set in the environment to its default value
+	t.Setenv("RISK_MAX_CONSECUTIVE_LOSSES", "5")
+	// Changed without an environment variable, as a config file would
+	c.RiskManagement.StopLossPercentage = 0.05
+	// Set to an empty value, which the loaders treat as unset
+	t.Setenv("RISK_PAUSE_DURATION_MINUTES", "")
+
+	summary := c.Summary()
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"MaxConsecutiveLosses", "  MaxConsecutiveLosses = 5 (env)"},
+		{"StopLossPercentage", "  StopLossPercentage = 0.05 (file)"},
+		{"MaxDailyLossPercentage", "(default)"},
+		{"PauseDuration", "(default)"},
+	}
+	for _, tt := range tests {
+		line := summaryLine(summary, "RiskManagement", tt.name)
+		if !strings.HasSuffix(line, tt.want) {
+			t.Errorf("%s line = %q, want suffix %q", tt.name, line, tt.want)
+		}
+	}
+}
+
+func TestSummaryMarksOverridesFromEnv(t *testing.T) {
+	c := DefaultConfig()
+	tight := 0.01
+	withOverride(c, "ETHUSDT", RiskOverride{StopLossPercentage: &tight})
+	t.Setenv("RISK_OVERRIDE_ETHUSDT_STOP_LOSS_PERCENT", "0.01")
+
+	if line := summaryLine(c.Summary(), "RiskManagement", "SymbolOverrides"); !strings.HasSuffix(line, "(env)") {
+		t.Errorf("SymbolOverrides line = %q, want (env)", line)
+	}
+}
+
+func TestSummaryRedactsSecrets(t *testing.T) {
+	c := DefaultConfig()
+	c.Trading.APISecret = "supersecretvalue"
+	t.Setenv("API_SECRET", "supersecretvalue")
+
+	summary := c.Summary()
+	if strings.Contains(summary, "supersecretvalue") {
+		t.Error("Summary contains the API secret")
+	}
+	if line := summaryLine(summary, "Trading", "APISecret"); !strings.HasSuffix(line, "(env)") {
+		t.Errorf("APISecret line = %q, want (env)", line)
+	}
+}
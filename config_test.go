@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// testnetConfig returns the default configuration on testnet, which validates without API keys
+func testnetConfig() *Config {
+	c := DefaultConfig()
+	c.Trading.TestnetEnabled = true
+	return c
+}
+
+func TestDefaultConfigIsValid(t *testing.T) {
+	if err := testnetConfig().Validate(); err != nil {
+		t.Fatalf("DefaultConfig fails validation: %v", err)
+	}
+}
+
+func TestValidateRequiresAPIKeysOffTestnet(t *testing.T) {
+	c := DefaultConfig()
+	c.CopyTrading.Enabled = false
+	c.DryRun = true
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "API key") {
+		t.Fatalf("Validate without API keys = %v, want the missing keys reported", err)
+	}
+	c.Trading.APIKey, c.Trading.APISecret = "key", "secret"
+	if err := c.Validate(); err != nil {
+		t.Errorf("Validate with API keys = %v", err)
+	}
+}
+
+func TestValidateTierMinSpacing(t *testing.T) {
+	tests := []struct {
+		name       string
+		minSpacing float64
+		tiers      []TierProfit
+		wantErr    bool
+	}{
+		{"exactly the spacing", 0.5, []TierProfit{{ProfitPercentage: 1, Enabled: true}, {ProfitPercentage: 1.5, Enabled: true}}, false},
+		{"wider than the spacing", 0.5, []TierProfit{{ProfitPercentage: 1, Enabled: true}, {ProfitPercentage: 2, Enabled: true}}, false},
+		{"closer than the spacing", 0.5, []TierProfit{{ProfitPercentage: 1, Enabled: true}, {ProfitPercentage: 1.4, Enabled: true}}, true},
+		// 0.3-0.2 rounds just below 0.1
+		{"rounding within tolerance", 0.1, []TierProfit{{ProfitPercentage: 0.2, Enabled: true}, {ProfitPercentage: 0.3, Enabled: true}}, false},
+		{"inside tolerance", 0.5, []TierProfit{{ProfitPercentage: 1, Enabled: true}, {ProfitPercentage: 1.5 - tierSpacingTolerance/2, Enabled: true}}, false},
+		{"past tolerance", 0.5, []TierProfit{{ProfitPercentage: 1, Enabled: true}, {ProfitPercentage: 1.5 - tierSpacingTolerance*2, Enabled: true}}, true},
+		{"disabled tiers skipped", 0.5, []TierProfit{{ProfitPercentage: 1, Enabled: true}, {ProfitPercentage: 1.2}, {ProfitPercentage: 1.5, Enabled: true}}, false},
+		{"absolute tiers skipped", 0.5, []TierProfit{{ProfitPercentage: 1, Enabled: true}, {TargetPrice: 101, Enabled: true}, {ProfitPercentage: 1.5, Enabled: true}}, false},
+		{"no spacing", 0, []TierProfit{{ProfitPercentage: 1, Enabled: true}, {ProfitPercentage: 1.01, Enabled: true}}, false},
+	}
+	for _, tt := range tests {
+		c := testnetConfig()
+		c.MultiTier.Enabled = true
+		c.MultiTier.MinSpacing = tt.minSpacing
+		c.MultiTier.Tiers = tt.tiers
+		for i := range c.MultiTier.Tiers {
+			c.MultiTier.Tiers[i].ClosePercentage = 0.3
+		}
+		err := c.Validate()
+		if tt.wantErr && (err == nil || !strings.Contains(err.Error(), "minimum spacing")) {
+			t.Errorf("%s: Validate = %v, want a minimum spacing error", tt.name, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("%s: Validate = %v", tt.name, err)
+		}
+	}
+}
+
+func TestEffectiveMaxTradeValueTighterCapWins(t *testing.T) {
+	c := DefaultConfig()
+	c.FixedCapital.MaxCapitalPerTrade = 500
+	c.RiskManagement.MaxPositionSize = 0.1
+	if got := c.EffectiveMaxTradeValue(1000); got != 100 {
+		t.Errorf("EffectiveMaxTradeValue = %f, want the position size cap 100", got)
+	}
+	c.FixedCapital.MaxCapitalPerTrade = 50
+	if got := c.EffectiveMaxTradeValue(1000); got != 50 {
+		t.Errorf("EffectiveMaxTradeValue = %f, want the capital cap 50", got)
+	}
+}
+
+func TestWarningsReportUnreachableMaxCapitalPerTrade(t *testing.T) {
+	c := DefaultConfig()
+	c.FixedCapital.MaxCapitalPerTrade = 500
+	c.RiskManagement.MaxPositionSize = 0.1
+	warnings := c.Warnings()
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "max capital per trade") {
+		t.Errorf("Warnings = %q, want the max capital per trade warning", warnings)
+	}
+	c.FixedCapital.MaxCapitalPerTrade = 100
+	if warnings := c.Warnings(); len(warnings) != 0 {
+		t.Errorf("Warnings = %q, want none", warnings)
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Pausable can stop and restart opening new positions while managing open ones
+type Pausable interface {
+	Pause()
+	Resume()
+	Paused() bool
+}
+
+// SignalHandler copies leader signals, returning the opened position or nil when skipped
+type SignalHandler interface {
+	HandleSignal(ctx context.Context, signal CopySignal) (*Position, error)
+}
+
+// signalRequest is the JSON body of a POST /signal request
+type signalRequest struct {
+	LeaderAddress string  `json:"leader_address"`
+	Symbol        string  `json:"symbol"`
+	Side          string  `json:"side"`
+	LeaderSize    float64 `json:"leader_size"`
+	LeaderEquity  float64 `json:"leader_equity"`
+	Price         float64 `json:"price"`
+}
+
+// registerControlRoutes adds POST /pause and /resume to mux, authorized by a bearer token.
+// When target also handles signals, POST /signal copies the leader signal in the body.
+func registerControlRoutes(mux *http.ServeMux, target Pausable, token string) {
+	authorized := func(r *http.Request) bool {
+		provided := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		return subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
+	}
+	handle := func(action http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				w.Header().Set("Allow", http.MethodPost)
+				writeHealthResponse(w, http.StatusMethodNotAllowed, map[string]interface{}{"error": "method not allowed"})
+				return
+			}
+			if !authorized(r) {
+				writeHealthResponse(w, http.StatusUnauthorized, map[string]interface{}{"error": "unauthorized"})
+				return
+			}
+			action(w, r)
+		}
+	}
+	toggle := func(action func()) http.HandlerFunc {
+		return handle(func(w http.ResponseWriter, r *http.Request) {
+			action()
+			writeHealthResponse(w, http.StatusOK, map[string]interface{}{"paused": target.Paused()})
+		})
+	}
+	mux.HandleFunc("/pause", toggle(target.Pause))
+	mux.HandleFunc("/resume", toggle(target.Resume))
+
+	handler, ok := target.(SignalHandler)
+	if !ok {
+		return
+	}
+	mux.HandleFunc("/signal", handle(func(w http.ResponseWriter, r *http.Request) {
+		var req signalRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeHealthResponse(w, http.StatusBadRequest, map[string]interface{}{"error": "invalid signal: " + err.Error()})
+			return
+		}
+		p, err := handler.HandleSignal(r.Context(), CopySignal{
+			LeaderAddress: req.LeaderAddress,
+			Symbol:        req.Symbol,
+			Side:          req.Side,
+			LeaderSize:    req.LeaderSize,
+			LeaderEquity:  req.LeaderEquity,
+			Price:         req.Price,
+			ReceivedAt:    time.Now(),
+		})
+		if err != nil {
+			writeHealthResponse(w, http.StatusUnprocessableEntity, map[string]interface{}{"error": err.Error()})
+			return
+		}
+		if p == nil {
+			writeHealthResponse(w, http.StatusOK, map[string]interface{}{"copied": false})
+			return
+		}
+		writeHealthResponse(w, http.StatusOK, map[string]interface{}{"copied": true, "position": p.ID, "quantity": p.FilledQuantity, "price": p.EntryPrice})
+	}))
+}
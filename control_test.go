@@ -0,0 +1,42 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSignalRouteCopiesAuthorizedSignals(t *testing.T) {
+	executor := &fakeExecutor{fillRatio: 1}
+	bot := newTestBot(t, executor)
+	health := NewHealthServer()
+	health.EnableControl(bot, "secret")
+	server := httptest.NewServer(health.Handler())
+	defer server.Close()
+
+	post := func(token string) *http.Response {
+		body := `{"leader_address":"0xleader","symbol":"BNBUSDT","side":"buy","leader_size":10,"leader_equity":10000,"price":300}`
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/signal", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST /signal: %v", err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	if resp := post("wrong"); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("status = %d with a wrong token, want 401", resp.StatusCode)
+	}
+	if len(bot.OpenPositions()) != 0 {
+		t.Fatal("unauthorized signal opened a position")
+	}
+	if resp := post("secret"); resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if open := bot.OpenPositions(); len(open) != 1 || open[0].Symbol != "BNBUSDT" {
+		t.Errorf("open positions = %+v, want the copied signal", open)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// CooldownTracker blocks re-entering a symbol until a cooldown has elapsed since its last close
+type CooldownTracker struct {
+	mu       sync.Mutex
+	cooldown time.Duration
+	closedAt map[string]time.Time
+}
+
+// NewCooldownTracker creates a cooldown tracker using the configured re-entry cooldown; a zero
+// cooldown never blocks
+func (c *Config) NewCooldownTracker() *CooldownTracker {
+	return &CooldownTracker{
+		cooldown: time.Duration(c.RiskManagement.ReEntryCooldown) * time.Second,
+		closedAt: make(map[string]time.Time),
+	}
+}
+
+// reconfigure switches the tracker to the re-entry cooldown of c, which also applies to
+// cooldowns already running
+func (t *CooldownTracker) reconfigure(c *Config) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cooldown = time.Duration(c.RiskManagement.ReEntryCooldown) * time.Second
+}
+
+// RecordClose starts the cooldown of a symbol whose position closed at now
+func (t *CooldownTracker) RecordClose(symbol string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closedAt[symbol] = now
+}
+
+// CanEnter reports whether symbol may be entered at now
+func (t *CooldownTracker) CanEnter(symbol string, now time.Time) bool {
+	return t.Remaining(symbol, now) == 0
+}
+
+// Remaining returns how long re-entering symbol stays blocked at now
+func (t *CooldownTracker) Remaining(symbol string, now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	closedAt, ok := t.closedAt[symbol]
+	if !ok {
+		return 0
+	}
+	remaining := closedAt.Add(t.cooldown).Sub(now)
+	if remaining <= 0 {
+		delete(t.closedAt, symbol)
+		return 0
+	}
+	return remaining
+}
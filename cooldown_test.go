@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCooldownTrackerBlocksThenAllowsReEntry(t *testing.T) {
+	c := DefaultConfig()
+	c.RiskManagement.ReEntryCooldown = 60
+	tracker := c.NewCooldownTracker()
+	closedAt := time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)
+	tracker.RecordClose("BNBUSDT", closedAt)
+
+	if tracker.CanEnter("BNBUSDT", closedAt.Add(59*time.Second)) {
+		t.Error("re-entry allowed during the cooldown")
+	}
+	if got := tracker.Remaining("BNBUSDT", closedAt.Add(45*time.Second)); got != 15*time.Second {
+		t.Errorf("Remaining = %s, want 15s", got)
+	}
+	if !tracker.CanEnter("ETHUSDT", closedAt) {
+		t.Error("another symbol is blocked")
+	}
+	if !tracker.CanEnter("BNBUSDT", closedAt.Add(time.Minute)) {
+		t.Error("re-entry blocked after the cooldown")
+	}
+}
+
+func TestCooldownTrackerDisabled(t *testing.T) {
+	tracker := DefaultConfig().NewCooldownTracker()
+	now := time.Now()
+	tracker.RecordClose("BNBUSDT", now)
+	if !tracker.CanEnter("BNBUSDT", now) {
+		t.Error("zero cooldown blocked re-entry")
+	}
+}
+
+func TestAllowEntryRefusesSymbolCoolingDown(t *testing.T) {
+	c := DefaultConfig()
+	c.Logging.LogConfigOnStart = false
+	c.RiskManagement.ReEntryCooldown = 3600
+	bot, err := NewBot(c, &fakeExecutor{fillRatio: 1}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewBot: %v", err)
+	}
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 1, FilledQuantity: 1})
+	if _, err := bot.ClosePosition(context.Background(), "p", 1); err != nil {
+		t.Fatalf("ClosePosition: %v", err)
+	}
+
+	if bot.AllowEntry(CopySignal{Symbol: "BNBUSDT", Side: SideBuy}) {
+		t.Error("entry allowed right after closing the symbol")
+	}
+	if !bot.AllowEntry(CopySignal{Symbol: "ETHUSDT", Side: SideBuy}) {
+		t.Error("entry in another symbol refused")
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"log"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxCopyRatio is the largest effective copy ratio, matching the CopyRatio validation bound
+const maxCopyRatio = 10
+
+// ScaleLeaderTrade translates a leader's position quantity into mine by the equity ratio times
+// CopyRatio multiplied by the leader's weight, such as LeaderScorer.WeightFor, clamped to the
+// order quantity limits and to MaxCapitalPerTrade at price. MaxCopyNotional is applied last,
+// so no scaling can exceed it. It returns 0 when the scaled size is below
+// MinLeaderPositionSize, or when the capital and notional caps leave less than
+// MinOrderQuantity, and the trade should be skipped.
+func (c *Config) ScaleLeaderTrade(leaderPositionSize, leaderEquity, myEquity, price, weight float64) float64 {
+	if leaderPositionSize <= 0 || leaderEquity <= 0 || myEquity <= 0 || price <= 0 || weight <= 0 {
+		return 0
+	}
+
+	copyRatio := math.Min(c.CopyTrading.CopyRatio*weight, maxCopyRatio)
+	size := leaderPositionSize * (myEquity / leaderEquity) * copyRatio
+	if size < c.CopyTrading.MinLeaderPositionSize {
+		return 0
+	}
+
+	size = math.Max(size, c.Trading.MinOrderQuantity)
+	size = math.Min(size, c.Trading.MaxOrderQuantity)
+	if maxByCapital := c.FixedCapital.MaxCapitalPerTrade / price; size > maxByCapital {
+		size = maxByCapital
+	}
+	if size*price > c.CopyTrading.MaxCopyNotional {
+		clamped := c.CopyTrading.MaxCopyNotional / price
+		log.Printf("⚠️  Clamping copied order from %f to %f: notional %.2f exceeds max copy notional %.2f",
+			size, clamped, size*price, c.CopyTrading.MaxCopyNotional)
+		size = clamped
+	}
+	if size < c.Trading.MinOrderQuantity {
+		return 0
+	}
+	return size
+}
+
+// IsSymbolAllowed reports whether leader trades in symbol may be copied. Symbols compare
+// case-insensitively, the blocklist takes precedence over the allowlist, and an empty
+// allowlist allows every symbol.
+func (c *Config) IsSymbolAllowed(symbol string) bool {
+	matches := func(listed string) bool {
+		return strings.EqualFold(listed, symbol)
+	}
+	if slices.ContainsFunc(c.CopyTrading.SymbolBlocklist, matches) {
+		return false
+	}
+	return len(c.CopyTrading.SymbolAllowlist) == 0 || slices.ContainsFunc(c.CopyTrading.SymbolAllowlist, matches)
+}
+
+// IsLeaderFollowed reports whether signals from address may be copied. Addresses compare
+// case-insensitively, and an empty LeaderAddresses follows every leader.
+func (c *Config) IsLeaderFollowed(address string) bool {
+	if len(c.CopyTrading.LeaderAddresses) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(c.CopyTrading.LeaderAddresses, func(leader string) bool {
+		return strings.EqualFold(leader, address)
+	})
+}
+
+// CopyPriceDeviation returns how far the market price has moved from the leader's fill price
+// as a fraction of the fill price
+func CopyPriceDeviation(leaderFillPrice, marketPrice float64) float64 {
+	if leaderFillPrice <= 0 {
+		return math.Inf(1)
+	}
+	return math.Abs(marketPrice-leaderFillPrice) / leaderFillPrice
+}
+
+// WithinCopyPriceDeviation reports whether signal may still be copied at marketPrice, logging
+// the skipped signal when the price moved more than MaxCopyPriceDeviation from the leader's fill
+func (c *Config) WithinCopyPriceDeviation(signal CopySignal, marketPrice float64) bool {
+	deviation := CopyPriceDeviation(signal.Price, marketPrice)
+	if deviation <= c.CopyTrading.MaxCopyPriceDeviation {
+		return true
+	}
+	log.Printf("⏭️  Skipping copy of %s %s from %s: market %f is %.2f%% from leader fill %f (max %.2f%%)",
+		signal.Side, signal.Symbol, signal.LeaderAddress, marketPrice, deviation*100, signal.Price,
+		c.CopyTrading.MaxCopyPriceDeviation*100)
+	return false
+}
+
+// NextCopyDelay returns CopyDelay plus a random jitter of up to CopyDelayJitter
+func (c *Config) NextCopyDelay() time.Duration {
+	delay := time.Duration(c.CopyTrading.CopyDelay) * time.Millisecond
+	if c.CopyTrading.CopyDelayJitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(c.CopyTrading.CopyDelayJitter)+1)) * time.Millisecond
+	}
+	return delay
+}
+
+// WaitCopyDelay waits NextCopyDelay before a leader order is mirrored, returning early when ctx is done
+func (c *Config) WaitCopyDelay(ctx context.Context) error {
+	timer := time.NewTimer(c.NextCopyDelay())
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// CopyThrottle is a token bucket capping how many copied orders are placed per minute
+type CopyThrottle struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	// Tokens refilled per second
+	rate float64
+	last time.Time
+	now  func() time.Time
+}
+
+// NewCopyThrottle creates a throttle allowing MaxCopiesPerMinute copied orders per minute; 0
+// disables throttling
+func (c *Config) NewCopyThrottle() *CopyThrottle {
+	perMinute := float64(c.CopyTrading.MaxCopiesPerMinute)
+	return &CopyThrottle{
+		capacity: perMinute,
+		tokens:   perMinute,
+		rate:     perMinute / 60,
+		last:     time.Now(),
+		now:      time.Now,
+	}
+}
+
+// reconfigure switches the throttle to the MaxCopiesPerMinute of c. Tokens accrued at the old
+// rate are kept up to the new capacity; a throttle that was disabled starts full.
+func (t *CopyThrottle) reconfigure(c *Config) {
+	perMinute := float64(c.CopyTrading.MaxCopiesPerMinute)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.refill()
+	if t.capacity == 0 {
+		t.tokens = perMinute
+	}
+	t.capacity, t.rate = perMinute, perMinute/60
+	t.tokens = math.Min(t.tokens, t.capacity)
+}
+
+// refill adds the tokens accrued since the last refill; must be called with the lock held
+func (t *CopyThrottle) refill() {
+	now := t.now()
+	t.tokens = math.Min(t.capacity, t.tokens+now.Sub(t.last).Seconds()*t.rate)
+	t.last = now
+}
+
+// Allow consumes a token and reports whether a copied order may be placed now
+func (t *CopyThrottle) Allow() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.capacity == 0 {
+		return true
+	}
+
+	t.refill()
+	if t.tokens < 1 {
+		return false
+	}
+	t.tokens--
+	return true
+}
+
+// Wait blocks until a token is available and consumes it, returning early when ctx is done
+func (t *CopyThrottle) Wait(ctx context.Context) error {
+	for {
+		t.mu.Lock()
+		if t.capacity == 0 {
+			t.mu.Unlock()
+			return nil
+		}
+		t.refill()
+		if t.tokens >= 1 {
+			t.tokens--
+			t.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - t.tokens) / t.rate * float64(time.Second))
+		t.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
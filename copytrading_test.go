@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScaleLeaderTradeLeaderMuchLarger(t *testing.T) {
+	c := DefaultConfig()
+	// A 100x larger leader buying 50 scales to 0.5, within every cap at price 10
+	if got := c.ScaleLeaderTrade(50, 100000, 1000, 10, 1); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("ScaleLeaderTrade = %f, want 0.5", got)
+	}
+	// Dust below MinLeaderPositionSize after scaling is skipped
+	if got := c.ScaleLeaderTrade(0.05, 100000, 1000, 10, 1); got != 0 {
+		t.Errorf("ScaleLeaderTrade of dust = %f, want 0", got)
+	}
+}
+
+func TestScaleLeaderTradeLeaderMuchSmaller(t *testing.T) {
+	c := DefaultConfig()
+	// A 100x smaller leader buying 5 scales to 500, capped by MaxCapitalPerTrade at price 1
+	want := c.FixedCapital.MaxCapitalPerTrade / 1
+	if got := c.ScaleLeaderTrade(5, 10, 1000, 1, 1); math.Abs(got-want) > 1e-9 {
+		t.Errorf("ScaleLeaderTrade = %f, want the capital cap %f", got, want)
+	}
+}
+
+func TestScaleLeaderTradeRaisesToMinimumOrder(t *testing.T) {
+	c := DefaultConfig()
+	// 0.005 is above MinLeaderPositionSize but below MinOrderQuantity
+	if got := c.ScaleLeaderTrade(0.005, 1000, 1000, 10, 1); got != c.Trading.MinOrderQuantity {
+		t.Errorf("ScaleLeaderTrade = %f, want MinOrderQuantity %f", got, c.Trading.MinOrderQuantity)
+	}
+}
+
+func TestScaleLeaderTradeSkipsWhenCapsFallBelowMinimum(t *testing.T) {
+	c := DefaultConfig()
+	c.FixedCapital.MaxCapitalPerTrade = 100
+	// At a price of 20000 the capital cap allows only 0.005, below MinOrderQuantity 0.01
+	if got := c.ScaleLeaderTrade(1, 1000, 1000, 20000, 1); got != 0 {
+		t.Errorf("ScaleLeaderTrade = %f, want 0 when the caps leave less than the minimum order", got)
+	}
+}
+
+func TestScaleLeaderTradeNotionalCap(t *testing.T) {
+	c := DefaultConfig()
+	c.FixedCapital.MaxCapitalPerTrade = 1e9
+	if got := c.ScaleLeaderTrade(100, 1000, 1000, 100, 1); got*100 > c.CopyTrading.MaxCopyNotional+1e-9 {
+		t.Errorf("ScaleLeaderTrade notional %f exceeds MaxCopyNotional %f", got*100, c.CopyTrading.MaxCopyNotional)
+	}
+}
+
+// timedExecutor fills every order and records when it was submitted
+type timedExecutor struct {
+	mu    sync.Mutex
+	times []time.Time
+}
+
+func (e *timedExecutor) Submit(ctx context.Context, order Order) (Fill, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.times = append(e.times, time.Now())
+	return Fill{Order: order, Price: order.Price, Quantity: order.Quantity}, nil
+}
+
+func TestCopiedOrdersSpacedByCopyDelay(t *testing.T) {
+	executor := &timedExecutor{}
+	bot := newTestBot(t, executor)
+	bot.config.Load().CopyTrading.CopyDelay = 50
+	delay := 50 * time.Millisecond
+
+	// Signals arriving together are still mirrored one copy delay apart
+	start := time.Now()
+	var wg sync.WaitGroup
+	for _, symbol := range []string{"BNBUSDT", "ETHUSDT", "SOLUSDT"} {
+		wg.Add(1)
+		go func(symbol string) {
+			defer wg.Done()
+			signal := testSignal()
+			signal.Symbol = symbol
+			if _, err := bot.HandleSignal(context.Background(), signal); err != nil {
+				t.Errorf("HandleSignal(%s): %v", symbol, err)
+			}
+		}(symbol)
+	}
+	wg.Wait()
+
+	if len(executor.times) != 3 {
+		t.Fatalf("submitted %d orders, want 3", len(executor.times))
+	}
+	sort.Slice(executor.times, func(i, j int) bool { return executor.times[i].Before(executor.times[j]) })
+	if first := executor.times[0].Sub(start); first < delay {
+		t.Errorf("first order sent %s after its signal, want at least %s", first, delay)
+	}
+	for i := 1; i < len(executor.times); i++ {
+		if gap := executor.times[i].Sub(executor.times[i-1]); gap < delay {
+			t.Errorf("orders %d and %d sent %s apart, want at least %s", i-1, i, gap, delay)
+		}
+	}
+}
+
+func TestCopyThrottleHoldsCopiesOverTheLimit(t *testing.T) {
+	c := DefaultConfig()
+	c.CopyTrading.MaxCopiesPerMinute = 2
+	throttle := c.NewCopyThrottle()
+	now := time.Now()
+	throttle.now = func() time.Time { return now }
+	throttle.last = now
+
+	if !throttle.Allow() || !throttle.Allow() {
+		t.Fatal("throttle refused copies within the per-minute limit")
+	}
+	if throttle.Allow() {
+		t.Error("throttle allowed a third copy within the minute")
+	}
+	now = now.Add(30 * time.Second)
+	if !throttle.Allow() {
+		t.Error("throttle refused a copy after a token refilled")
+	}
+}
+
+func TestHandleSignalSkipsUnfollowedLeaders(t *testing.T) {
+	executor := &fakeExecutor{fillRatio: 1}
+	bot := newTestBot(t, executor)
+	bot.config.Load().CopyTrading.LeaderAddresses = []string{"0xLEADER"}
+
+	stranger := testSignal()
+	stranger.LeaderAddress = "0xstranger"
+	if p, err := bot.HandleSignal(context.Background(), stranger); err != nil || p != nil {
+		t.Fatalf("HandleSignal from an unfollowed leader = %v, %v, want it skipped", p, err)
+	}
+	if len(executor.orders) != 0 {
+		t.Fatalf("submitted %d orders for an unfollowed leader, want none", len(executor.orders))
+	}
+
+	// Leader addresses compare case-insensitively
+	if p, err := bot.HandleSignal(context.Background(), testSignal()); err != nil || p == nil {
+		t.Fatalf("HandleSignal from a followed leader = %v, %v, want a position", p, err)
+	}
+}
+
+func TestWithinCopyPriceDeviation(t *testing.T) {
+	c := DefaultConfig()
+	c.CopyTrading.MaxCopyPriceDeviation = 0.01
+	tests := []struct {
+		name        string
+		leaderPrice float64
+		market      float64
+		want        bool
+	}{
+		{"unchanged", 100, 100, true},
+		{"up to the limit", 100, 101, true},
+		{"down to the limit", 100, 99, true},
+		{"ran away up", 100, 101.5, false},
+		{"ran away down", 100, 98.9, false},
+		{"no leader fill price", 0, 100, false},
+	}
+	for _, tt := range tests {
+		signal := CopySignal{LeaderAddress: "0xleader", Symbol: "BNBUSDT", Side: SideBuy, Price: tt.leaderPrice}
+		if got := c.WithinCopyPriceDeviation(signal, tt.market); got != tt.want {
+			t.Errorf("%s: WithinCopyPriceDeviation(%f at %f) = %v, want %v", tt.name, tt.leaderPrice, tt.market, got, tt.want)
+		}
+	}
+}
+
+func TestIsSymbolAllowed(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowlist []string
+		blocklist []string
+		symbol    string
+		want      bool
+	}{
+		{"no lists", nil, nil, "BNBUSDT", true},
+		{"allowed", []string{"BNBUSDT", "ETHUSDT"}, nil, "ETHUSDT", true},
+		{"not on the allowlist", []string{"BNBUSDT"}, nil, "ETHUSDT", false},
+		{"blocked", nil, []string{"ETHUSDT"}, "ETHUSDT", false},
+		{"blocklist beats allowlist", []string{"ETHUSDT"}, []string{"ETHUSDT"}, "ETHUSDT", false},
+		{"lowercase symbol", []string{"BNBUSDT"}, nil, "bnbusdt", true},
+		{"lowercase allowlist", []string{"bnbusdt"}, nil, "BNBUSDT", true},
+		{"mixed case blocklist", nil, []string{"EthUsdt"}, "ethusdt", false},
+	}
+	for _, tt := range tests {
+		c := DefaultConfig()
+		c.CopyTrading.SymbolAllowlist = tt.allowlist
+		c.CopyTrading.SymbolBlocklist = tt.blocklist
+		if got := c.IsSymbolAllowed(tt.symbol); got != tt.want {
+			t.Errorf("%s: IsSymbolAllowed(%q) = %v, want %v", tt.name, tt.symbol, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+)
+
+// CorrelationTracker keeps a rolling window of prices per symbol and computes Pearson correlation
+type CorrelationTracker struct {
+	mu     sync.Mutex
+	window int
+	prices map[string][]float64
+}
+
+// NewCorrelationTracker creates a tracker keeping the last CorrelationWindow prices per symbol
+func (c *Config) NewCorrelationTracker() *CorrelationTracker {
+	return &CorrelationTracker{
+		window: c.RiskManagement.CorrelationWindow,
+		prices: make(map[string][]float64),
+	}
+}
+
+// AddPrice appends a price sample for symbol, dropping samples outside the window
+func (t *CorrelationTracker) AddPrice(symbol string, price float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	series := append(t.prices[symbol], price)
+	if len(series) > t.window {
+		series = series[len(series)-t.window:]
+	}
+	t.prices[symbol] = series
+}
+
+// Correlation returns the Pearson correlation of the two symbols' price windows
+func (t *CorrelationTracker) Correlation(symbolA, symbolB string) (float64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	a, b := t.prices[symbolA], t.prices[symbolB]
+	if len(a) < t.window {
+		return 0, fmt.Errorf("insufficient data for %s: %d of %d samples", symbolA, len(a), t.window)
+	}
+	if len(b) < t.window {
+		return 0, fmt.Errorf("insufficient data for %s: %d of %d samples", symbolB, len(b), t.window)
+	}
+	return pearson(a, b)
+}
+
+// Matrix returns the pairwise correlation matrix of symbols, indexed like symbols, with 1 on
+// the diagonal. It returns an error listing every symbol without a full window of samples.
+func (t *CorrelationTracker) Matrix(symbols []string) ([][]float64, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var insufficient []string
+	for _, symbol := range symbols {
+		if len(t.prices[symbol]) < t.window {
+			insufficient = append(insufficient, fmt.Sprintf("%s (%d of %d samples)", symbol, len(t.prices[symbol]), t.window))
+		}
+	}
+	if len(insufficient) > 0 {
+		return nil, fmt.Errorf("insufficient data for %s", strings.Join(insufficient, ", "))
+	}
+
+	matrix := make([][]float64, len(symbols))
+	for i := range symbols {
+		matrix[i] = make([]float64, len(symbols))
+		matrix[i][i] = 1
+	}
+	for i := range symbols {
+		for j := i + 1; j < len(symbols); j++ {
+			correlation, err := pearson(t.prices[symbols[i]], t.prices[symbols[j]])
+			if err != nil {
+				return nil, fmt.Errorf("error correlating %s and %s: %v", symbols[i], symbols[j], err)
+			}
+			matrix[i][j] = correlation
+			matrix[j][i] = correlation
+		}
+	}
+	return matrix, nil
+}
+
+// pearson computes the Pearson correlation coefficient of two equal-length series
+func pearson(a, b []float64) (float64, error) {
+	n := float64(len(a))
+	var meanA, meanB float64
+	for i := range a {
+		meanA += a[i]
+		meanB += b[i]
+	}
+	meanA /= n
+	meanB /= n
+
+	var covariance, varianceA, varianceB float64
+	for i := range a {
+		da, db := a[i]-meanA, b[i]-meanB
+		covariance += da * db
+		varianceA += da * da
+		varianceB += db * db
+	}
+	if varianceA == 0 || varianceB == 0 {
+		return 0, fmt.Errorf("correlation undefined for a constant price series")
+	}
+	return covariance / math.Sqrt(varianceA*varianceB), nil
+}
+
+// CanOpenPosition reports whether newSymbol may be opened alongside existingSymbols without
+// exceeding MaxCorrelationThreshold. It returns an error when there is insufficient data.
+func (c *Config) CanOpenPosition(tracker *CorrelationTracker, newSymbol string, existingSymbols []string) (bool, error) {
+	if !c.RiskManagement.CorrelationCheckEnabled {
+		return true, nil
+	}
+	for _, symbol := range existingSymbols {
+		if symbol == newSymbol {
+			continue
+		}
+		correlation, err := tracker.Correlation(newSymbol, symbol)
+		if err != nil {
+			return false, err
+		}
+		if correlation > c.RiskManagement.MaxCorrelationThreshold {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func correlationConfig() *Config {
+	c := DefaultConfig()
+	c.RiskManagement.CorrelationCheckEnabled = true
+	c.RiskManagement.CorrelationWindow = 4
+	c.RiskManagement.MaxCorrelationThreshold = 0.8
+	return c
+}
+
+func TestCorrelationTrackerKeepsWindow(t *testing.T) {
+	tracker := correlationConfig().NewCorrelationTracker()
+	for _, price := range []float64{50, 1, 2, 3, 4} {
+		tracker.AddPrice("BNBUSDT", price)
+		tracker.AddPrice("ETHUSDT", price*2)
+	}
+	tracker.AddPrice("BTCUSDT", 1)
+
+	// The 50 outlier has left the window, leaving two perfectly correlated series
+	correlation, err := tracker.Correlation("BNBUSDT", "ETHUSDT")
+	if err != nil || math.Abs(correlation-1) > 1e-9 {
+		t.Errorf("Correlation = %f, %v, want 1", correlation, err)
+	}
+	if _, err := tracker.Correlation("BNBUSDT", "BTCUSDT"); err == nil {
+		t.Error("Correlation with a partial window succeeded")
+	}
+}
+
+func TestCorrelationMatrix(t *testing.T) {
+	tracker := correlationConfig().NewCorrelationTracker()
+	for _, price := range []float64{1, 2, 3, 4} {
+		tracker.AddPrice("BNBUSDT", price)
+		tracker.AddPrice("ETHUSDT", 10-price)
+	}
+	matrix, err := tracker.Matrix([]string{"BNBUSDT", "ETHUSDT"})
+	if err != nil {
+		t.Fatalf("Matrix: %v", err)
+	}
+	if matrix[0][0] != 1 || math.Abs(matrix[0][1]+1) > 1e-9 || matrix[0][1] != matrix[1][0] {
+		t.Errorf("Matrix = %v, want a symmetric matrix with -1 off the diagonal", matrix)
+	}
+	if _, err := tracker.Matrix([]string{"BNBUSDT", "SOLUSDT"}); err == nil {
+		t.Error("Matrix with a missing symbol succeeded")
+	}
+}
+
+func TestCanOpenPosition(t *testing.T) {
+	c := correlationConfig()
+	tracker := c.NewCorrelationTracker()
+	for _, price := range []float64{1, 2, 3, 4} {
+		tracker.AddPrice("BNBUSDT", price)
+		tracker.AddPrice("ETHUSDT", price+1)
+		tracker.AddPrice("SOLUSDT", []float64{4, 1, 3, 2}[int(price)-1])
+	}
+
+	if ok, err := c.CanOpenPosition(tracker, "ETHUSDT", []string{"BNBUSDT"}); err != nil || ok {
+		t.Errorf("correlated CanOpenPosition = %v, %v, want refused", ok, err)
+	}
+	if ok, err := c.CanOpenPosition(tracker, "SOLUSDT", []string{"BNBUSDT", "SOLUSDT"}); err != nil || !ok {
+		t.Errorf("uncorrelated CanOpenPosition = %v, %v, want allowed", ok, err)
+	}
+	if _, err := c.CanOpenPosition(tracker, "ADAUSDT", []string{"BNBUSDT"}); err == nil {
+		t.Error("CanOpenPosition without data succeeded")
+	}
+
+	c.RiskManagement.CorrelationCheckEnabled = false
+	if ok, err := c.CanOpenPosition(tracker, "ETHUSDT", []string{"BNBUSDT"}); err != nil || !ok {
+		t.Errorf("disabled CanOpenPosition = %v, %v, want allowed", ok, err)
+	}
+}
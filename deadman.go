@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+)
+
+// StopOrderPlacer places exchange-native stop-market orders that trigger at stopPrice, so they
+// execute even when the bot is not running
+type StopOrderPlacer interface {
+	PlaceStopOrder(ctx context.Context, order Order, stopPrice float64) (string, error)
+}
+
+// protectiveStop is the resting exchange stop guarding one position
+type protectiveStop struct {
+	orderID   string
+	symbol    string
+	stopPrice float64
+	quantity  float64
+}
+
+// DeadMansSwitch keeps a reduce-only stop order resting on the exchange for every open
+// position, so positions stay protected if the bot process hangs or dies and its in-memory
+// stops stop being checked
+type DeadMansSwitch struct {
+	mu     sync.Mutex
+	config *Config
+	placer StopOrderPlacer
+	orders OrderManager
+	stops  map[string]protectiveStop
+}
+
+// NewDeadMansSwitch creates a dead man's switch placing stops with placer, and replacing them
+// and checking whether they executed through orders
+func (c *Config) NewDeadMansSwitch(placer StopOrderPlacer, orders OrderManager) *DeadMansSwitch {
+	return &DeadMansSwitch{
+		config: c,
+		placer: placer,
+		orders: orders,
+		stops:  make(map[string]protectiveStop),
+	}
+}
+
+// reconfigure switches later stops to the stop levels of c
+func (s *DeadMansSwitch) reconfigure(c *Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config = c
+}
+
+// Protect ensures p has a resting stop at stopPrice, or at its EffectiveStopPrice when stopPrice
+// is 0, covering its filled quantity. An existing stop is replaced only when it differs.
+func (s *DeadMansSwitch) Protect(ctx context.Context, p *Position, stopPrice float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stopPrice <= 0 {
+		stopPrice = s.config.EffectiveStopPrice(p)
+	}
+	if stopPrice <= 0 {
+		return fmt.Errorf("position %s has no stop price to protect", p.ID)
+	}
+
+	current, ok := s.stops[p.ID]
+	if ok && current.stopPrice == stopPrice && math.Abs(current.quantity-p.FilledQuantity) <= p.FilledQuantity*tierPercentageEpsilon {
+		return nil
+	}
+	if ok {
+		if err := s.orders.CancelOrder(ctx, current.symbol, current.orderID); err != nil {
+			return fmt.Errorf("error cancelling stop %s of position %s: %v", current.orderID, p.ID, err)
+		}
+		delete(s.stops, p.ID)
+	}
+	if p.FilledQuantity <= 0 {
+		return nil
+	}
+
+	side := SideSell
+	if !isBuy(p.Side) {
+		side = SideBuy
+	}
+	order := Order{Symbol: p.Symbol, Side: side, Quantity: p.FilledQuantity, Price: stopPrice, Type: OrderTypeMarket, ReduceOnly: true}
+	orderID, err := s.placer.PlaceStopOrder(ctx, order, stopPrice)
+	if err != nil {
+		return fmt.Errorf("error placing stop for position %s: %v", p.ID, err)
+	}
+	s.stops[p.ID] = protectiveStop{orderID: orderID, symbol: p.Symbol, stopPrice: stopPrice, quantity: p.FilledQuantity}
+	log.Printf("🛡️  Protective stop %s for %s %s %f at %f", orderID, p.Symbol, p.Side, p.FilledQuantity, stopPrice)
+	return nil
+}
+
+// Release cancels the resting stop of a position that was closed by the bot
+func (s *DeadMansSwitch) Release(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.stops[id]
+	if !ok {
+		return nil
+	}
+	if err := s.orders.CancelOrder(ctx, current.symbol, current.orderID); err != nil {
+		return fmt.Errorf("error cancelling stop %s of position %s: %v", current.orderID, id, err)
+	}
+	delete(s.stops, id)
+	return nil
+}
+
+// Triggered returns the fills of the stops that executed on the exchange by position ID and
+// forgets them
+func (s *DeadMansSwitch) Triggered(ctx context.Context) (map[string]Fill, error) {
+	s.mu.Lock()
+	stops := make(map[string]protectiveStop, len(s.stops))
+	for id, stop := range s.stops {
+		stops[id] = stop
+	}
+	s.mu.Unlock()
+
+	var errs []error
+	triggered := make(map[string]Fill)
+	for id, stop := range stops {
+		fill, err := s.orders.QueryOrder(ctx, stop.symbol, stop.orderID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error checking stop %s of position %s: %v", stop.orderID, id, err))
+			continue
+		}
+		if fill.Quantity > 0 {
+			triggered[id] = fill
+		}
+	}
+
+	s.mu.Lock()
+	for id := range triggered {
+		if s.stops[id].orderID == stops[id].orderID {
+			delete(s.stops, id)
+		}
+	}
+	s.mu.Unlock()
+	return triggered, errors.Join(errs...)
+}
+
+// Sync protects every open position at its effective stop and releases the stops of positions
+// no longer open
+func (s *DeadMansSwitch) Sync(ctx context.Context, positions []*Position) error {
+	var errs []error
+	open := make(map[string]bool, len(positions))
+	for _, p := range positions {
+		open[p.ID] = true
+		if err := s.Protect(ctx, p, 0); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	s.mu.Lock()
+	var closed []string
+	for id := range s.stops {
+		if !open[id] {
+			closed = append(closed, id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, id := range closed {
+		if err := s.Release(ctx, id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// EnableDeadMansSwitch has the bot keep a protective stop resting for each open position at its
+// current stop, releasing it before every close and re-placing it for what remains. Open
+// positions are protected immediately.
+func (b *Bot) EnableDeadMansSwitch(ctx context.Context, s *DeadMansSwitch) error {
+	b.mu.Lock()
+	b.deadman = s
+	b.mu.Unlock()
+	return s.Sync(ctx, b.OpenPositions())
+}
+
+// SyncProtectiveStops books the protective stops that executed on the exchange as closes of
+// their positions
+func (b *Bot) SyncProtectiveStops(ctx context.Context) error {
+	b.mu.Lock()
+	deadman := b.deadman
+	b.mu.Unlock()
+	if deadman == nil {
+		return nil
+	}
+
+	b.closeMu.Lock()
+	defer b.closeMu.Unlock()
+	triggered, err := deadman.Triggered(ctx)
+	return errors.Join(err, b.bookExitFills(ctx, triggered, "protective stop"))
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// fakeStopExchange records stop placements, cancels and market orders in the order they arrive
+type fakeStopExchange struct {
+	mu     sync.Mutex
+	events []string
+	stops  int
+	// Fills reported for queried stop orders by order ID
+	fills map[string]Fill
+}
+
+func (e *fakeStopExchange) record(format string, args ...interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, fmt.Sprintf(format, args...))
+}
+
+func (e *fakeStopExchange) PlaceStopOrder(ctx context.Context, order Order, stopPrice float64) (string, error) {
+	e.mu.Lock()
+	e.stops++
+	id := fmt.Sprintf("stop-%d", e.stops)
+	e.mu.Unlock()
+	e.record("place %s %g at %g", id, order.Quantity, stopPrice)
+	return id, nil
+}
+
+func (e *fakeStopExchange) CancelOrder(ctx context.Context, symbol, orderID string) error {
+	e.record("cancel %s", orderID)
+	return nil
+}
+
+func (e *fakeStopExchange) QueryOrder(ctx context.Context, symbol, orderID string) (Fill, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.fills[orderID], nil
+}
+
+func (e *fakeStopExchange) Submit(ctx context.Context, order Order) (Fill, error) {
+	e.record("%s %g", order.Side, order.Quantity)
+	return Fill{Order: order, Price: order.Price, Quantity: order.Quantity}, nil
+}
+
+func TestDeadMansSwitchReleasedBeforeClose(t *testing.T) {
+	exchange := &fakeStopExchange{}
+	bot := newTestBot(t, exchange)
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 4, FilledQuantity: 4})
+	if err := bot.EnableDeadMansSwitch(context.Background(), bot.config.Load().NewDeadMansSwitch(exchange, exchange)); err != nil {
+		t.Fatalf("EnableDeadMansSwitch: %v", err)
+	}
+
+	if _, err := bot.ClosePosition(context.Background(), "p", 1); err != nil {
+		t.Fatalf("ClosePosition: %v", err)
+	}
+	if _, err := bot.ClosePosition(context.Background(), "p", 3); err != nil {
+		t.Fatalf("ClosePosition: %v", err)
+	}
+
+	// The 3% stop is cancelled before each close and re-placed only for what remains
+	want := []string{"place stop-1 4 at 97", "cancel stop-1", "sell 1", "place stop-2 3 at 97", "cancel stop-2", "sell 3"}
+	if fmt.Sprint(exchange.events) != fmt.Sprint(want) {
+		t.Errorf("events = %v, want %v", exchange.events, want)
+	}
+}
+
+func TestTriggeredProtectiveStopClosesPosition(t *testing.T) {
+	exchange := &fakeStopExchange{}
+	bot := newTestBot(t, exchange)
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 4, FilledQuantity: 4})
+	if err := bot.EnableDeadMansSwitch(context.Background(), bot.config.Load().NewDeadMansSwitch(exchange, exchange)); err != nil {
+		t.Fatalf("EnableDeadMansSwitch: %v", err)
+	}
+
+	if err := bot.SyncProtectiveStops(context.Background()); err != nil {
+		t.Fatalf("SyncProtectiveStops: %v", err)
+	}
+	if len(bot.OpenPositions()) != 1 {
+		t.Fatal("position closed before its stop executed")
+	}
+
+	exchange.fills = map[string]Fill{"stop-1": {OrderID: "stop-1", Price: 97, Quantity: 4}}
+	if err := bot.SyncProtectiveStops(context.Background()); err != nil {
+		t.Fatalf("SyncProtectiveStops: %v", err)
+	}
+	if open := bot.OpenPositions(); len(open) != 0 {
+		t.Errorf("open positions = %+v, want the stopped-out position dropped", open)
+	}
+	if pnl := bot.CapitalBase().RealizedPnL(); pnl >= 0 {
+		t.Errorf("realized PnL = %f, want the stop's loss booked", pnl)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+)
+
+// DryRunExecutor simulates fills at the market price plus slippage against a simulated balance
+type DryRunExecutor struct {
+	mu       sync.Mutex
+	cfg      *Config
+	balance  float64
+	holdings map[string]float64
+	trades   []Fill
+	now      func() time.Time
+}
+
+// NewDryRunExecutor creates a dry-run executor with a balance of FixedCapital.TotalCapital
+func (c *Config) NewDryRunExecutor() *DryRunExecutor {
+	return &DryRunExecutor{
+		cfg:      c,
+		balance:  c.FixedCapital.TotalCapital,
+		holdings: make(map[string]float64),
+		now:      time.Now,
+	}
+}
+
+// Submit records the order and fills it at its market price adjusted by SlippageTolerance,
+// charging the taker fee against the simulated balance
+func (e *DryRunExecutor) Submit(ctx context.Context, order Order) (Fill, error) {
+	if order.Quantity <= 0 || order.Price <= 0 {
+		return Fill{}, fmt.Errorf("invalid dry run order: quantity %f at price %f", order.Quantity, order.Price)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	price := e.cfg.EstimateFillPrice(order.Price, order.Side)
+	notional := price * order.Quantity
+	fee := notional * e.cfg.feeRate(false)
+
+	if isBuy(order.Side) {
+		if notional+fee > e.balance {
+			return Fill{}, fmt.Errorf("insufficient dry run balance: need %f, have %f", notional+fee, e.balance)
+		}
+		e.balance -= notional + fee
+		e.holdings[order.Symbol] += order.Quantity
+	} else {
+		e.balance += notional - fee
+		e.holdings[order.Symbol] -= order.Quantity
+	}
+
+	fill := Fill{
+		Order:    order,
+		Price:    price,
+		Quantity: order.Quantity,
+		Fee:      fee,
+		Time:     e.now(),
+	}
+	e.trades = append(e.trades, fill)
+	log.Printf("🧪 Dry run: %s %f %s at %f (fee %f, balance %f)", order.Side, order.Quantity, order.Symbol, price, fee, e.balance)
+	return fill, nil
+}
+
+// Balance returns the simulated quote balance
+func (e *DryRunExecutor) Balance() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.balance
+}
+
+// Holding returns the simulated base quantity held for symbol; negative when net short
+func (e *DryRunExecutor) Holding(symbol string) float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.holdings[symbol]
+}
+
+// TradeLog returns the simulated fills in execution order
+func (e *DryRunExecutor) TradeLog() []Fill {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Fill(nil), e.trades...)
+}
+
+// DumpTradeLog writes the simulated fills to w, one per line
+func (e *DryRunExecutor) DumpTradeLog(w io.Writer) error {
+	for _, fill := range e.TradeLog() {
+		if _, err := fmt.Fprintf(w, "%s %s %s %f @ %f fee %f\n",
+			fill.Time.UTC().Format(time.RFC3339), fill.Order.Symbol, fill.Order.Side, fill.Quantity, fill.Price, fill.Fee); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// unreachableExecutor fails the test when an order reaches it
+type unreachableExecutor struct {
+	t *testing.T
+}
+
+func (e unreachableExecutor) Submit(ctx context.Context, order Order) (Fill, error) {
+	e.t.Errorf("live executor received %+v", order)
+	return Fill{}, fmt.Errorf("live executor reached")
+}
+
+func TestDryRunNeverReachesLiveExecutor(t *testing.T) {
+	c := DefaultConfig()
+	c.DryRun = true
+	c.Trading.SlippageTolerance = 0.01
+	executor, err := c.NewExecutor(unreachableExecutor{t})
+	if err != nil {
+		t.Fatalf("NewExecutor: %v", err)
+	}
+
+	fill, err := executor.Submit(context.Background(), Order{Symbol: "BNBUSDT", Side: SideBuy, Quantity: 1, Price: 300})
+	if err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if fill.Quantity != 1 || math.Abs(fill.Price-303) > 1e-9 || fill.Fee <= 0 {
+		t.Errorf("fill = %+v, want 1 simulated at 303 with a fee", fill)
+	}
+}
+
+func TestDryRunExecutorTracksSimulatedBalance(t *testing.T) {
+	c := DefaultConfig()
+	c.FixedCapital.TotalCapital = 1000
+	executor := c.NewDryRunExecutor()
+	ctx := context.Background()
+
+	buy, err := executor.Submit(ctx, Order{Symbol: "BNBUSDT", Side: SideBuy, Quantity: 2, Price: 300})
+	if err != nil {
+		t.Fatalf("Submit buy: %v", err)
+	}
+	if want := 1000 - buy.Price*2 - buy.Fee; math.Abs(executor.Balance()-want) > 1e-9 {
+		t.Errorf("Balance after buy = %f, want %f", executor.Balance(), want)
+	}
+	if executor.Holding("BNBUSDT") != 2 {
+		t.Errorf("Holding = %f, want 2", executor.Holding("BNBUSDT"))
+	}
+
+	if _, err := executor.Submit(ctx, Order{Symbol: "BNBUSDT", Side: SideBuy, Quantity: 10, Price: 300}); err == nil {
+		t.Error("Submit accepted a buy past the simulated balance")
+	}
+	if _, err := executor.Submit(ctx, Order{Symbol: "BNBUSDT", Side: SideSell, Quantity: 0, Price: 300}); err == nil {
+		t.Error("Submit accepted an empty order")
+	}
+	if len(executor.TradeLog()) != 1 {
+		t.Errorf("TradeLog = %d fills, want 1", len(executor.TradeLog()))
+	}
+}
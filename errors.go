@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrorCategory classifies exchange errors for retry and breaker logic
+type ErrorCategory string
+
+const (
+	// ErrorUnknown is an error no mapping matched
+	ErrorUnknown ErrorCategory = "UNKNOWN"
+	// ErrorRetryable is a transient error worth retrying
+	ErrorRetryable ErrorCategory = "RETRYABLE"
+	// ErrorRateLimit means request weight or order limits were exceeded
+	ErrorRateLimit ErrorCategory = "RATE_LIMIT"
+	// ErrorFilter means the order violated a symbol filter or account constraint
+	ErrorFilter ErrorCategory = "FILTER"
+	// ErrorFatal is an error that retrying cannot fix, such as bad credentials
+	ErrorFatal ErrorCategory = "FATAL"
+)
+
+// binanceErrorCodes maps Binance API error codes to categories
+var binanceErrorCodes = map[int]ErrorCategory{
+	-1000: ErrorRetryable, // UNKNOWN
+	-1001: ErrorRetryable, // DISCONNECTED
+	-1006: ErrorRetryable, // UNEXPECTED_RESP
+	-1007: ErrorRetryable, // TIMEOUT
+	-1021: ErrorRetryable, // INVALID_TIMESTAMP
+	-1003: ErrorRateLimit, // TOO_MANY_REQUESTS
+	-1015: ErrorRateLimit, // TOO_MANY_ORDERS
+	-1013: ErrorFilter,    // filter failure
+	-1111: ErrorFilter,    // BAD_PRECISION
+	-2010: ErrorFilter,    // NEW_ORDER_REJECTED
+	-1002: ErrorFatal,     // UNAUTHORIZED
+	-1022: ErrorFatal,     // INVALID_SIGNATURE
+	-1100: ErrorFatal,     // ILLEGAL_CHARS
+	-1102: ErrorFatal,     // MANDATORY_PARAM_EMPTY_OR_MALFORMED
+	-1121: ErrorFatal,     // BAD_SYMBOL
+	-2014: ErrorFatal,     // BAD_API_KEY_FMT
+	-2015: ErrorFatal,     // REJECTED_MBX_KEY
+}
+
+// messageRule maps an error message substring to a category
+type messageRule struct {
+	substring string
+	category  ErrorCategory
+}
+
+// binanceErrorMessages maps error message substrings to categories, checked when no code matches
+var binanceErrorMessages = []messageRule{
+	{"filter failure", ErrorFilter},
+	{"insufficient balance", ErrorFilter},
+	{"too many requests", ErrorRateLimit},
+	{"timeout", ErrorRetryable},
+	{"connection reset", ErrorRetryable},
+	{"invalid api-key", ErrorFatal},
+}
+
+// ErrorClassifier maps exchange error codes and messages to categories
+type ErrorClassifier struct {
+	mu       sync.RWMutex
+	codes    map[int]ErrorCategory
+	messages []messageRule
+}
+
+// NewErrorClassifier creates a classifier with the Binance error mappings
+func NewErrorClassifier() *ErrorClassifier {
+	codes := make(map[int]ErrorCategory, len(binanceErrorCodes))
+	for code, category := range binanceErrorCodes {
+		codes[code] = category
+	}
+	return &ErrorClassifier{
+		codes:    codes,
+		messages: append([]messageRule(nil), binanceErrorMessages...),
+	}
+}
+
+// RegisterCode maps an exchange error code to a category, replacing any existing mapping
+func (c *ErrorClassifier) RegisterCode(code int, category ErrorCategory) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.codes[code] = category
+}
+
+// RegisterMessage maps a case-insensitive message substring to a category; later
+// registrations take precedence
+func (c *ErrorClassifier) RegisterMessage(substring string, category ErrorCategory) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rule := messageRule{strings.ToLower(substring), category}
+	c.messages = append([]messageRule{rule}, c.messages...)
+}
+
+// Classify returns the category of an exchange error, matching the code before the message
+func (c *ErrorClassifier) Classify(code int, message string) ErrorCategory {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if category, ok := c.codes[code]; ok {
+		return category
+	}
+	message = strings.ToLower(message)
+	for _, rule := range c.messages {
+		if strings.Contains(message, rule.substring) {
+			return rule.category
+		}
+	}
+	return ErrorUnknown
+}
+
+// Retryable reports whether errors of the category may be retried
+func (category ErrorCategory) Retryable() bool {
+	return category == ErrorRetryable || category == ErrorRateLimit
+}
+
+// ExchangeError is an error response from the exchange
+type ExchangeError struct {
+	// HTTP status code of the response
+	StatusCode int
+	// Exchange error code, 0 when the response carried none
+	Code     int
+	Message  string
+	Category ErrorCategory
+	// Delay the exchange asked for before retrying, 0 when not given
+	RetryAfter time.Duration
+}
+
+// NewExchangeError classifies an exchange error response. Server errors are retryable,
+// HTTP 429 and 418 are rate limits and HTTP 401 and 403 are authentication failures;
+// other responses are classified by code and message.
+func NewExchangeError(classifier *ErrorClassifier, statusCode, code int, message string) *ExchangeError {
+	e := &ExchangeError{StatusCode: statusCode, Code: code, Message: message}
+	switch {
+	case statusCode == http.StatusTooManyRequests || statusCode == http.StatusTeapot:
+		e.Category = ErrorRateLimit
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		e.Category = ErrorFatal
+	case statusCode >= 500:
+		e.Category = ErrorRetryable
+	default:
+		e.Category = classifier.Classify(code, message)
+	}
+	return e
+}
+
+func (e *ExchangeError) Error() string {
+	return fmt.Sprintf("exchange error %d (HTTP %d, %s): %s", e.Code, e.StatusCode, e.Category, e.Message)
+}
+
+// Retryable reports whether the request may succeed if retried: network, server and rate limit errors
+func (e *ExchangeError) Retryable() bool {
+	return e.Category.Retryable()
+}
+
+// Fatal reports whether the error needs operator attention, such as failed authentication or an invalid symbol
+func (e *ExchangeError) Fatal() bool {
+	return e.Category == ErrorFatal
+}
+
+// Rejected reports whether the exchange rejected the order itself, such as for insufficient
+// balance or a filter failure
+func (e *ExchangeError) Rejected() bool {
+	return e.Category == ErrorFilter
+}
+
+// ClassifyError returns the category of any error returned by an exchange call. Network
+// errors and timeouts are retryable; a cancelled context is never retried.
+func ClassifyError(err error) ErrorCategory {
+	var exchangeErr *ExchangeError
+	if errors.As(err, &exchangeErr) {
+		return exchangeErr.Category
+	}
+	if errors.Is(err, context.Canceled) {
+		return ErrorUnknown
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorRetryable
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ErrorRetryable
+	}
+	return ErrorUnknown
+}
+
+// RetryWithBackoff calls fn up to maxAttempts times, retrying only retryable errors with a
+// delay starting at initialBackoff and doubling each retry, or the exchange's RetryAfter when
+// longer. Fatal and rejected errors are returned immediately.
+func RetryWithBackoff(ctx context.Context, maxAttempts int, initialBackoff time.Duration, fn func(ctx context.Context) error) error {
+	backoff := initialBackoff
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+		if !ClassifyError(err).Retryable() || attempt == maxAttempts {
+			return err
+		}
+
+		delay := backoff
+		var exchangeErr *ExchangeError
+		if errors.As(err, &exchangeErr) && exchangeErr.RetryAfter > delay {
+			delay = exchangeErr.RetryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%v (retry abandoned: %v)", err, ctx.Err())
+		case <-time.After(delay):
+		}
+		backoff *= 2
+	}
+	return err
+}
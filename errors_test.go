@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestClassifyBinanceErrorCodes(t *testing.T) {
+	classifier := NewErrorClassifier()
+	tests := []struct {
+		code    int
+		message string
+		want    ErrorCategory
+	}{
+		{-1001, "Internal error; unable to process your request. Please try again.", ErrorRetryable},
+		{-1021, "Timestamp for this request is outside of the recvWindow.", ErrorRetryable},
+		{-1003, "Too much request weight used; current limit is 1200 request weight per 1 MINUTE.", ErrorRateLimit},
+		{-1015, "Too many new orders; current limit is 10 orders per SECOND.", ErrorRateLimit},
+		{-1013, "Filter failure: LOT_SIZE", ErrorFilter},
+		{-2010, "Account has insufficient balance for requested action.", ErrorFilter},
+		{-1022, "Signature for this request is not valid.", ErrorFatal},
+		{-2015, "Invalid API-key, IP, or permissions for action.", ErrorFatal},
+		{-1121, "Invalid symbol.", ErrorFatal},
+		// Unmapped codes fall back to the message
+		{-9999, "Filter failure: MIN_NOTIONAL", ErrorFilter},
+		{-9999, "something new", ErrorUnknown},
+	}
+	for _, tt := range tests {
+		if got := classifier.Classify(tt.code, tt.message); got != tt.want {
+			t.Errorf("Classify(%d, %q) = %s, want %s", tt.code, tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestErrorClassifierRegistrations(t *testing.T) {
+	classifier := NewErrorClassifier()
+	classifier.RegisterCode(-4164, ErrorFilter)
+	classifier.RegisterMessage("maintenance", ErrorRetryable)
+	if got := classifier.Classify(-4164, "Order's notional must be no smaller than 100"); got != ErrorFilter {
+		t.Errorf("registered code = %s, want %s", got, ErrorFilter)
+	}
+	if got := classifier.Classify(0, "System MAINTENANCE"); got != ErrorRetryable {
+		t.Errorf("registered message = %s, want %s", got, ErrorRetryable)
+	}
+	// Registrations never leak into other classifiers
+	if got := NewErrorClassifier().Classify(-4164, ""); got != ErrorUnknown {
+		t.Errorf("fresh classifier = %s, want %s", got, ErrorUnknown)
+	}
+}
+
+func TestNewExchangeErrorHTTPStatus(t *testing.T) {
+	classifier := NewErrorClassifier()
+	tests := []struct {
+		status int
+		code   int
+		want   ErrorCategory
+	}{
+		{429, -1003, ErrorRateLimit},
+		{418, 0, ErrorRateLimit},
+		{401, 0, ErrorFatal},
+		{503, 0, ErrorRetryable},
+		{400, -1013, ErrorFilter},
+	}
+	for _, tt := range tests {
+		if got := NewExchangeError(classifier, tt.status, tt.code, "").Category; got != tt.want {
+			t.Errorf("HTTP %d code %d = %s, want %s", tt.status, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestRetryWithBackoffStopsOnFatal(t *testing.T) {
+	classifier := NewErrorClassifier()
+	attempts := 0
+	err := RetryWithBackoff(context.Background(), 5, time.Millisecond, func(context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return NewExchangeError(classifier, 503, 0, "unavailable")
+		}
+		return fmt.Errorf("submit: %w", NewExchangeError(classifier, 400, -2015, "Invalid API-key"))
+	})
+	var exchangeErr *ExchangeError
+	if !errors.As(err, &exchangeErr) || !exchangeErr.Fatal() {
+		t.Errorf("RetryWithBackoff = %v, want the fatal error", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Order represents an order to submit to the exchange
+type Order struct {
+	Symbol string
+	// Order side: buy or sell
+	Side     string
+	Quantity float64
+	// Reference price; the market price for market orders
+	Price float64
+	// Order type; empty means market
+	Type OrderType
+	// Trigger price of stop-limit orders, which rest at Price once it trades; required for them
+	StopPrice float64
+	// Time in force of limit-type orders, empty for market orders
+	TimeInForce TimeInForce
+	// Only reduce an open position, never open or reverse one
+	ReduceOnly bool
+	// Closes part or all of an open position, so entry limits do not apply to it
+	Exit bool
+}
+
+// Notional returns the quote value of the order
+func (o Order) Notional() float64 {
+	return o.Quantity * o.Price
+}
+
+// IsMaker reports whether the order is expected to rest on the book and pay the maker fee
+func (o Order) IsMaker() bool {
+	return restsOnBook(o.Type, o.TimeInForce)
+}
+
+// Fill represents the execution result of an order
+type Fill struct {
+	// Exchange order ID, empty for simulated fills
+	OrderID  string
+	Order    Order
+	Price    float64
+	Quantity float64
+	Fee      float64
+	Time     time.Time
+}
+
+// OrderExecutor submits orders and reports their fills
+type OrderExecutor interface {
+	Submit(ctx context.Context, order Order) (Fill, error)
+}
+
+// OrderExecutorFunc adapts a function to an OrderExecutor
+type OrderExecutorFunc func(ctx context.Context, order Order) (Fill, error)
+
+// Submit calls f
+func (f OrderExecutorFunc) Submit(ctx context.Context, order Order) (Fill, error) {
+	return f(ctx, order)
+}
+
+// OrderCanceler cancels the unfilled remainder of an open order
+type OrderCanceler interface {
+	CancelOrder(ctx context.Context, symbol, orderID string) error
+}
+
+// ExecutionMode selects which executor handles orders
+type ExecutionMode string
+
+const (
+	// ExecutionLive submits orders to the exchange
+	ExecutionLive ExecutionMode = "LIVE"
+	// ExecutionObserve logs would-be orders without keeping any wallet or position state
+	ExecutionObserve ExecutionMode = "OBSERVE"
+)
+
+// ParseExecutionMode parses an execution mode case-insensitively
+func ParseExecutionMode(s string) (ExecutionMode, error) {
+	switch mode := ExecutionMode(strings.ToUpper(strings.TrimSpace(s))); mode {
+	case ExecutionLive, ExecutionObserve:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown execution mode %q", s)
+	}
+}
+
+// TradesLive reports whether orders go to the exchange rather than a simulation
+func (c *Config) TradesLive() bool {
+	return c.ExecutionMode == ExecutionLive && !c.DryRun && !c.PaperTrading.Enabled
+}
+
+// NewExecutor selects the order executor for the configured execution mode, simulating
+// live orders when DryRun is set and filling them against the persisted paper account when
+// paper trading is enabled. Orders are rounded to their symbol filters before submission.
+func (c *Config) NewExecutor(live OrderExecutor) (OrderExecutor, error) {
+	var executor OrderExecutor
+	switch {
+	case c.ExecutionMode == ExecutionObserve:
+		executor = &ObserveExecutor{}
+	case c.DryRun:
+		executor = c.NewDryRunExecutor()
+	case c.PaperTrading.Enabled:
+		store, err := c.NewStateStore()
+		if err != nil {
+			return nil, err
+		}
+		broker, err := c.NewPaperBroker(store)
+		if err != nil {
+			return nil, err
+		}
+		executor = broker
+	default:
+		if live == nil {
+			return nil, fmt.Errorf("live executor required for execution mode %s", c.ExecutionMode)
+		}
+		executor = live
+	}
+	return &filteredExecutor{next: executor, config: c}, nil
+}
+
+// ObserveExecutor logs every decision and would-be order but never fills anything
+type ObserveExecutor struct{}
+
+// Submit logs the would-be order and returns an empty fill
+func (e *ObserveExecutor) Submit(ctx context.Context, order Order) (Fill, error) {
+	log.Printf("👀 Observe: would %s %f %s at %f", order.Side, order.Quantity, order.Symbol, order.Price)
+	return Fill{Order: order, Time: time.Now()}, nil
+}
+
+// LogDecision logs a strategy decision that did not result in an order
+func (e *ObserveExecutor) LogDecision(symbol, decision string) {
+	log.Printf("👀 Observe: %s %s", symbol, decision)
+}
+
+// observer returns the ObserveExecutor behind executor, or nil outside observe mode
+func observer(executor OrderExecutor) *ObserveExecutor {
+	if filtered, ok := executor.(*filteredExecutor); ok {
+		executor = filtered.next
+	}
+	observe, _ := executor.(*ObserveExecutor)
+	return observe
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+)
+
+// captureLog redirects the standard logger to a buffer for the rest of the test
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	output, flags := log.Writer(), log.Flags()
+	log.SetOutput(&buf)
+	log.SetFlags(0)
+	t.Cleanup(func() {
+		log.SetOutput(output)
+		log.SetFlags(flags)
+	})
+	return &buf
+}
+
+func TestObserveModeLogsDecisionsWithoutState(t *testing.T) {
+	c := DefaultConfig()
+	c.Logging.LogConfigOnStart = false
+	c.CopyTrading.CopyDelay = 0
+	c.CopyTrading.CopyDelayJitter = 0
+	c.RiskManagement.MaxBaseAssetExposure = 1
+	c.ExecutionMode = ExecutionObserve
+	executor, err := c.NewExecutor(nil)
+	if err != nil {
+		t.Fatalf("NewExecutor: %v", err)
+	}
+	bot, err := NewBot(c, executor, nil, nil)
+	if err != nil {
+		t.Fatalf("NewBot: %v", err)
+	}
+	equity := bot.Equity()
+	logged := captureLog(t)
+
+	if p, err := bot.HandleSignal(context.Background(), testSignal()); err != nil || p != nil {
+		t.Fatalf("HandleSignal = %+v, %v, want nothing opened", p, err)
+	}
+	if !strings.Contains(logged.String(), "👀 Observe: would buy 1.000000 BNBUSDT at 300.000000") {
+		t.Errorf("log = %q, want the would-be order", logged)
+	}
+
+	// An order the validation rejects is logged as the decision it stopped
+	logged.Reset()
+	c.Trading.MaxOrderQuantity = 0.5
+	c.Trading.MinOrderQuantity = 0.01
+	if _, err := bot.submit(context.Background(), c.EntryOrder("BNBUSDT", SideBuy, 0.6, 300)); err == nil {
+		t.Fatal("submit accepted an order above the maximum quantity")
+	}
+	if !strings.Contains(logged.String(), "👀 Observe: BNBUSDT would not buy 0.600000 at 300.000000: order rejected (QUANTITY_TOO_LARGE)") {
+		t.Errorf("log = %q, want the rejected decision", logged)
+	}
+
+	if len(bot.OpenPositions()) != 0 || bot.Equity() != equity {
+		t.Errorf("observe mode changed state: %d positions, equity %f from %f", len(bot.OpenPositions()), bot.Equity(), equity)
+	}
+}
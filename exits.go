@@ -0,0 +1,101 @@
+package main
+
+// ExitPhase is the stage of a position's stop management
+type ExitPhase int
+
+const (
+	// ExitPhaseInitial uses the initial stop loss
+	ExitPhaseInitial ExitPhase = iota
+	// ExitPhaseBreakEven has moved the stop to break-even
+	ExitPhaseBreakEven
+	// ExitPhaseTrailing trails the stop behind the best price beyond break-even
+	ExitPhaseTrailing
+)
+
+func (p ExitPhase) String() string {
+	switch p {
+	case ExitPhaseBreakEven:
+		return "break-even"
+	case ExitPhaseTrailing:
+		return "trailing"
+	default:
+		return "initial"
+	}
+}
+
+// ExitManager moves a position's stop from the initial stop loss to break-even once
+// BreakEvenThreshold is reached and, in break-even-plus-trailing mode, then trails it by
+// TrailingStopPercentage once the trail is tighter than break-even. The stop never loosens.
+type ExitManager struct {
+	config   *Config
+	long     bool
+	entry    float64
+	stop     float64
+	phase    ExitPhase
+	trailing *TrailingStop
+}
+
+// NewExitManager creates an exit manager starting at the position's effective stop
+func (c *Config) NewExitManager(p *Position) *ExitManager {
+	return &ExitManager{
+		config: c.ForSymbol(p.Symbol),
+		long:   isLong(p.Side),
+		entry:  p.EntryPrice,
+		stop:   c.EffectiveStopPrice(p),
+	}
+}
+
+// tighter reports whether stop a is closer to the price than stop b; a zero b is no stop
+func (m *ExitManager) tighter(a, b float64) bool {
+	if b == 0 {
+		return a > 0
+	}
+	if m.long {
+		return a > b
+	}
+	return a < b
+}
+
+// Update advances the stop with the current price and reports whether the stop was crossed
+func (m *ExitManager) Update(price float64) (float64, bool) {
+	c := m.config
+	side := SideShort
+	if m.long {
+		side = SideLong
+	}
+
+	if m.phase == ExitPhaseInitial && c.BreakEvenTriggered(m.entry, price, side) {
+		if breakEven := c.CalculateBreakEvenStop(m.entry, side); m.tighter(breakEven, m.stop) {
+			m.stop = breakEven
+		}
+		m.phase = ExitPhaseBreakEven
+		if c.RiskManagement.BreakEvenTrailingEnabled {
+			m.trailing = c.NewTrailingStop(price, side)
+		}
+	}
+
+	if m.trailing != nil {
+		if trail, _ := m.trailing.Update(price); m.tighter(trail, m.stop) {
+			m.stop = trail
+			m.phase = ExitPhaseTrailing
+		}
+	}
+
+	if m.stop == 0 {
+		return 0, false
+	}
+	if m.long {
+		return m.stop, price <= m.stop
+	}
+	return m.stop, price >= m.stop
+}
+
+// StopPrice returns the current effective stop, 0 when the position has no stop
+func (m *ExitManager) StopPrice() float64 {
+	return m.stop
+}
+
+// Phase returns the current stop management phase
+func (m *ExitManager) Phase() ExitPhase {
+	return m.phase
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func exitConfig() *Config {
+	c := DefaultConfig()
+	c.Trading.OrderType = OrderTypeMarket
+	c.Trading.TakerFee = 0.001
+	c.Trading.FeeSchedule = FeeSchedule{}
+	c.RiskManagement.StopLossPercentage = 0.03
+	c.RiskManagement.BreakEvenStopEnabled = true
+	c.RiskManagement.BreakEvenThreshold = 1
+	c.RiskManagement.BreakEvenTrailingEnabled = true
+	c.MultiTier.TrailingStopPercentage = 1.5
+	return c
+}
+
+func TestExitManagerWalksBreakEvenThenTrailing(t *testing.T) {
+	c := exitConfig()
+	manager := c.NewExitManager(&Position{Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100})
+	breakEven := c.CalculateBreakEvenStop(100, SideLong)
+
+	steps := []struct {
+		price     float64
+		wantStop  float64
+		wantPhase ExitPhase
+		wantHit   bool
+	}{
+		{100.5, 97, ExitPhaseInitial, false},
+		// 1% profit moves the stop to break-even; a 1.5% trail from 101 is still looser
+		{101, breakEven, ExitPhaseBreakEven, false},
+		{101.5, breakEven, ExitPhaseBreakEven, false},
+		// Further profit tightens the trail beyond break-even
+		{103, 103 * 0.985, ExitPhaseTrailing, false},
+		// A pullback never loosens the stop
+		{102, 103 * 0.985, ExitPhaseTrailing, false},
+		{101.4, 103 * 0.985, ExitPhaseTrailing, true},
+	}
+	for _, step := range steps {
+		stop, hit := manager.Update(step.price)
+		if math.Abs(stop-step.wantStop) > 1e-9 || hit != step.wantHit || manager.Phase() != step.wantPhase {
+			t.Errorf("Update(%f) = %f, %v in %s phase, want %f, %v in %s phase",
+				step.price, stop, hit, manager.Phase(), step.wantStop, step.wantHit, step.wantPhase)
+		}
+	}
+}
+
+func TestExitManagerBreakEvenOnlyShort(t *testing.T) {
+	c := exitConfig()
+	c.RiskManagement.BreakEvenTrailingEnabled = false
+	manager := c.NewExitManager(&Position{Symbol: "BNBUSDT", Side: SideShort, EntryPrice: 100})
+	breakEven := c.CalculateBreakEvenStop(100, SideShort)
+
+	if stop, hit := manager.Update(99); math.Abs(stop-breakEven) > 1e-9 || hit {
+		t.Errorf("Update(99) = %f, %v, want break-even %f", stop, hit, breakEven)
+	}
+	// Without trailing the stop stays at break-even however far price runs
+	if stop, _ := manager.Update(90); math.Abs(stop-breakEven) > 1e-9 || manager.Phase() != ExitPhaseBreakEven {
+		t.Errorf("Update(90) = %f in %s phase, want break-even", stop, manager.Phase())
+	}
+	if _, hit := manager.Update(breakEven); !hit {
+		t.Error("return to break-even did not hit the stop")
+	}
+}
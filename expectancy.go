@@ -0,0 +1,86 @@
+package main
+
+import "fmt"
+
+// averageTierReward returns the close-weighted average reward of the enabled percentage tiers as a fraction
+func (c *Config) averageTierReward() float64 {
+	var weightedReward, totalWeight float64
+	for _, tier := range c.MultiTier.Tiers {
+		if !tier.Enabled || tier.TargetPrice > 0 {
+			continue
+		}
+		weightedReward += tier.ProfitPercentage / 100 * tier.ClosePercentage
+		totalWeight += tier.ClosePercentage
+	}
+	if totalWeight == 0 {
+		return 0
+	}
+	return weightedReward / totalWeight
+}
+
+// roundTripFee returns the fee fraction paid to enter and exit a position
+func (c *Config) roundTripFee() float64 {
+	return c.entryFee() + c.exitFee()
+}
+
+// BreakEvenWinRate returns the win rate required to break even given the tier rewards,
+// the stop loss and round-trip fees. It returns 1 when no win rate can break even.
+func (c *Config) BreakEvenWinRate() float64 {
+	netWin := c.averageTierReward() - c.roundTripFee()
+	netLoss := c.RiskManagement.StopLossPercentage + c.roundTripFee()
+	if netWin <= 0 {
+		return 1
+	}
+	return netLoss / (netWin + netLoss)
+}
+
+// TierCoverageReport describes the risk/reward geometry of the tiers against the stop loss
+type TierCoverageReport struct {
+	// Reward of the first enabled tier as a fraction
+	FirstTierReward float64
+	// Stop loss risk as a fraction
+	StopRisk float64
+	// First tier reward divided by stop risk
+	RewardRiskRatio float64
+	// Expected return per trade at the configured expected win rate, net of fees
+	Expectancy float64
+	Warnings   []string
+}
+
+// OK reports whether the report has no warnings
+func (r TierCoverageReport) OK() bool {
+	return len(r.Warnings) == 0
+}
+
+// TierCoverage reports whether the first tier's reward covers the stop's risk by
+// MinRewardRiskRatio and whether the tiers imply a negative expectancy at ExpectedWinRate
+func (c *Config) TierCoverage() TierCoverageReport {
+	report := TierCoverageReport{StopRisk: c.RiskManagement.StopLossPercentage}
+
+	for _, tier := range c.MultiTier.Tiers {
+		if tier.Enabled && tier.TargetPrice == 0 {
+			report.FirstTierReward = tier.ProfitPercentage / 100
+			break
+		}
+	}
+	if report.StopRisk > 0 {
+		report.RewardRiskRatio = report.FirstTierReward / report.StopRisk
+		if report.RewardRiskRatio < c.RiskManagement.MinRewardRiskRatio {
+			report.Warnings = append(report.Warnings, fmt.Sprintf(
+				"first tier reward %.4f covers stop risk %.4f by %.2fx, below minimum %.2fx",
+				report.FirstTierReward, report.StopRisk, report.RewardRiskRatio, c.RiskManagement.MinRewardRiskRatio))
+		}
+	}
+
+	winRate := c.RiskManagement.ExpectedWinRate
+	netWin := c.averageTierReward() - c.roundTripFee()
+	netLoss := c.RiskManagement.StopLossPercentage + c.roundTripFee()
+	report.Expectancy = winRate*netWin - (1-winRate)*netLoss
+	if report.Expectancy < 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf(
+			"tiers imply negative expectancy %.4f per trade at a %.0f%% win rate (break-even win rate %.0f%%)",
+			report.Expectancy, winRate*100, c.BreakEvenWinRate()*100))
+	}
+
+	return report
+}
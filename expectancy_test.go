@@ -0,0 +1,81 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func expectancyConfig() *Config {
+	c := DefaultConfig()
+	c.Trading.OrderType = OrderTypeMarket
+	c.Trading.MakerFee = 0.001
+	c.Trading.TakerFee = 0.001
+	c.Trading.MakerRebateRate = 0
+	c.Trading.FeeSchedule.Tiers = nil
+	c.RiskManagement.StopLossPercentage = 0.03
+	c.MultiTier.Tiers = []TierProfit{
+		{ProfitPercentage: 4, ClosePercentage: 0.5, Enabled: true},
+		{ProfitPercentage: 8, ClosePercentage: 0.5, Enabled: true},
+		{ProfitPercentage: 20, ClosePercentage: 1, Enabled: false},
+	}
+	return c
+}
+
+func TestBreakEvenWinRate(t *testing.T) {
+	c := expectancyConfig()
+	// Average tier reward 6%, round trip fees 0.2%: net win 5.8%, net loss 3.2%
+	want := 0.032 / (0.058 + 0.032)
+	if got := c.BreakEvenWinRate(); math.Abs(got-want) > 1e-9 {
+		t.Errorf("BreakEvenWinRate() = %f, want %f", got, want)
+	}
+}
+
+func TestBreakEvenWinRateIgnoresAbsoluteTargets(t *testing.T) {
+	c := expectancyConfig()
+	want := c.BreakEvenWinRate()
+	c.MultiTier.Tiers = append(c.MultiTier.Tiers, TierProfit{TargetPrice: 1000, ClosePercentage: 1, Enabled: true})
+	if got := c.BreakEvenWinRate(); math.Abs(got-want) > 1e-9 {
+		t.Errorf("BreakEvenWinRate() with an absolute target = %f, want %f", got, want)
+	}
+}
+
+func TestBreakEvenWinRateUnreachable(t *testing.T) {
+	c := expectancyConfig()
+	// Tiers that cannot cover the round trip fees never break even
+	c.MultiTier.Tiers = []TierProfit{{ProfitPercentage: 0.1, ClosePercentage: 1, Enabled: true}}
+	if got := c.BreakEvenWinRate(); got != 1 {
+		t.Errorf("BreakEvenWinRate() = %f, want 1", got)
+	}
+}
+
+func TestTierCoverage(t *testing.T) {
+	c := expectancyConfig()
+	c.RiskManagement.MinRewardRiskRatio = 1
+	c.RiskManagement.ExpectedWinRate = 0.5
+	report := c.TierCoverage()
+	if !report.OK() {
+		t.Fatalf("warnings = %v, want none", report.Warnings)
+	}
+	if math.Abs(report.RewardRiskRatio-0.04/0.03) > 1e-9 {
+		t.Errorf("RewardRiskRatio = %f, want %f", report.RewardRiskRatio, 0.04/0.03)
+	}
+	// Half the trades net 5.8%, half lose 3.2%
+	if math.Abs(report.Expectancy-0.013) > 1e-9 {
+		t.Errorf("Expectancy = %f, want 0.013", report.Expectancy)
+	}
+}
+
+func TestTierCoverageWarnings(t *testing.T) {
+	c := expectancyConfig()
+	c.RiskManagement.MinRewardRiskRatio = 1
+	c.RiskManagement.ExpectedWinRate = 0.3
+	c.MultiTier.Tiers = []TierProfit{{ProfitPercentage: 2, ClosePercentage: 1, Enabled: true}}
+	report := c.TierCoverage()
+	// A 2% first tier against a 3% stop, and a 30% win rate below the break-even rate
+	if len(report.Warnings) != 2 {
+		t.Errorf("warnings = %v, want reward/risk and expectancy warnings", report.Warnings)
+	}
+	if report.Expectancy >= 0 {
+		t.Errorf("Expectancy = %f, want negative", report.Expectancy)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"strings"
+	"sync"
+)
+
+// quoteAssets lists known quote assets, longest first so suffix matching is unambiguous
+var quoteAssets = []string{"FDUSD", "USDT", "USDC", "BUSD", "TUSD", "BTC", "ETH", "BNB"}
+
+// BaseAsset returns the base asset of a trading pair such as BNBUSDT
+func BaseAsset(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	for _, quote := range quoteAssets {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return strings.TrimSuffix(symbol, quote)
+		}
+	}
+	return symbol
+}
+
+// QuoteAsset returns the quote asset of a trading pair such as BNBUSDT, empty when it has no
+// known quote asset
+func QuoteAsset(symbol string) string {
+	symbol = strings.ToUpper(symbol)
+	return strings.TrimPrefix(symbol, BaseAsset(symbol))
+}
+
+// ExposureTracker aggregates open notional exposure per symbol
+type ExposureTracker struct {
+	mu       sync.Mutex
+	notional map[string]float64
+}
+
+// NewExposureTracker creates an empty exposure tracker
+func NewExposureTracker() *ExposureTracker {
+	return &ExposureTracker{notional: make(map[string]float64)}
+}
+
+// Add adds notional exposure for symbol
+func (t *ExposureTracker) Add(symbol string, notional float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.notional[symbol] += notional
+}
+
+// Remove removes notional exposure for symbol
+func (t *ExposureTracker) Remove(symbol string, notional float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.notional[symbol] -= notional
+	if t.notional[symbol] <= 0 {
+		delete(t.notional, symbol)
+	}
+}
+
+// BaseAssetExposure returns the notional aggregated across all pairs sharing asset as base
+func (t *ExposureTracker) BaseAssetExposure(asset string) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var total float64
+	for symbol, notional := range t.notional {
+		if BaseAsset(symbol) == asset {
+			total += notional
+		}
+	}
+	return total
+}
+
+// TotalExposure returns the notional aggregated across all symbols
+func (t *ExposureTracker) TotalExposure() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var total float64
+	for _, notional := range t.notional {
+		total += notional
+	}
+	return total
+}
+
+// CanOpen reports whether adding notional keeps the total exposure within
+// MaxTotalExposurePercentage of equity
+func (c *Config) CanOpen(tracker *ExposureTracker, notional, equity float64) bool {
+	return tracker.TotalExposure()+notional <= equity*c.RiskManagement.MaxTotalExposurePercentage
+}
+
+// CanOpenBaseAsset reports whether adding notional on symbol keeps its base asset exposure
+// within MaxBaseAssetExposure of equity
+func (c *Config) CanOpenBaseAsset(tracker *ExposureTracker, symbol string, notional, equity float64) bool {
+	exposure := tracker.BaseAssetExposure(BaseAsset(symbol)) + notional
+	return exposure <= equity*c.RiskManagement.MaxBaseAssetExposure
+}
+
+// OpenExposure returns the notional of the open positions at their entry prices, resting
+// entries included for their full quantity
+func (b *Bot) OpenExposure() *ExposureTracker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	tracker := NewExposureTracker()
+	for _, p := range b.positions {
+		tracker.Add(p.Symbol, p.EntryPrice*p.Quantity)
+	}
+	return tracker
+}
+
+// copyNotional returns the notional a copy of signal would open, sized as HandleSignal sizes it
+// at the last price, falling back to the leader's price
+func (b *Bot) copyNotional(signal CopySignal) float64 {
+	price, ok := b.LastPrice(signal.Symbol)
+	if !ok {
+		price = signal.Price
+	}
+	return b.ScaleSignal(signal, b.capital.EffectiveCapital(), price) * price
+}
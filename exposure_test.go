@@ -0,0 +1,99 @@
+package main
+
+import "testing"
+
+func TestBaseAsset(t *testing.T) {
+	tests := map[string]string{
+		"BNBUSDT":  "BNB",
+		"bnbfdusd": "BNB",
+		"BNBBTC":   "BNB",
+		"ETHBNB":   "ETH",
+		"USDT":     "USDT",
+	}
+	for symbol, want := range tests {
+		if got := BaseAsset(symbol); got != want {
+			t.Errorf("BaseAsset(%q) = %q, want %q", symbol, got, want)
+		}
+	}
+}
+
+func TestCanOpenBaseAssetBlocksConcentration(t *testing.T) {
+	c := DefaultConfig()
+	c.RiskManagement.MaxBaseAssetExposure = 0.3
+	tracker := NewExposureTracker()
+	tracker.Add("BNBUSDT", 150)
+	tracker.Add("BNBFDUSD", 120)
+	tracker.Add("ETHUSDT", 500)
+
+	// 270 of BNB held against a 300 cap on 1000 equity
+	if got := tracker.BaseAssetExposure("BNB"); got != 270 {
+		t.Fatalf("BaseAssetExposure(BNB) = %f, want 270", got)
+	}
+	if !c.CanOpenBaseAsset(tracker, "BNBBTC", 30, 1000) {
+		t.Error("entry reaching the cap was blocked")
+	}
+	if c.CanOpenBaseAsset(tracker, "BNBBTC", 40, 1000) {
+		t.Error("entry beyond the cap was allowed")
+	}
+	if !c.CanOpenBaseAsset(tracker, "SOLUSDT", 200, 1000) {
+		t.Error("entry in another base asset was blocked")
+	}
+
+	tracker.Remove("BNBUSDT", 150)
+	if !c.CanOpenBaseAsset(tracker, "BNBBTC", 40, 1000) {
+		t.Error("entry was blocked after exposure was removed")
+	}
+}
+
+func TestCanOpenTotalExposure(t *testing.T) {
+	c := DefaultConfig()
+	c.RiskManagement.MaxTotalExposurePercentage = 0.5
+	tracker := NewExposureTracker()
+	tracker.Add("BNBUSDT", 300)
+	tracker.Add("ETHUSDT", 150)
+	if !c.CanOpen(tracker, 50, 1000) {
+		t.Error("entry reaching the total cap was blocked")
+	}
+	if c.CanOpen(tracker, 60, 1000) {
+		t.Error("entry beyond the total cap was allowed")
+	}
+}
+
+func TestAllowEntryCapsBaseAssetExposureAcrossPairs(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	bot.config.Load().RiskManagement.MaxBaseAssetExposure = 0.5
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 300, Quantity: 0.5, FilledQuantity: 0.5})
+	signal := testSignal()
+	signal.Symbol = "BNBFDUSD"
+
+	// 150 open and a 300 copy stay within 50% of the 1000 equity
+	if !bot.AllowEntry(signal) {
+		t.Fatal("AllowEntry refused a copy within the base asset cap")
+	}
+
+	bot.TrackPosition(&Position{ID: "q", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 300, Quantity: 0.5, FilledQuantity: 0.5})
+	if bot.AllowEntry(signal) {
+		t.Error("AllowEntry admitted a copy taking BNB exposure past the cap")
+	}
+	signal.Symbol = "ETHUSDT"
+	if !bot.AllowEntry(signal) {
+		t.Error("AllowEntry refused a copy in another base asset")
+	}
+}
+
+func TestAllowEntryCapsTotalExposure(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	bot.config.Load().RiskManagement.MaxTotalExposurePercentage = 0.8
+	bot.TrackPosition(&Position{ID: "p", Symbol: "ETHUSDT", Side: SideLong, EntryPrice: 100, Quantity: 4, FilledQuantity: 4})
+
+	// 400 open and a 300 copy stay within 80% of the 1000 equity
+	if !bot.AllowEntry(testSignal()) {
+		t.Fatal("AllowEntry refused a copy within the total exposure cap")
+	}
+
+	// A resting entry counts at its full quantity
+	bot.TrackPosition(&Position{ID: "q", Symbol: "SOLUSDT", Side: SideLong, EntryPrice: 100, Quantity: 2, EntryOrderID: "resting"})
+	if bot.AllowEntry(testSignal()) {
+		t.Error("AllowEntry admitted a copy taking total exposure past the cap")
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// bnbFeeDiscount is the fee reduction for paying fees in BNB
+const bnbFeeDiscount = 0.25
+
+// FeeTier holds the fee rates applying from a 30-day trading volume in quote currency
+type FeeTier struct {
+	MinVolume float64
+	MakerFee  float64
+	TakerFee  float64
+}
+
+// FeeSchedule holds the volume-based fee tiers, ascending by MinVolume
+type FeeSchedule struct {
+	Tiers []FeeTier
+	// Apply the discount for paying fees in BNB
+	UseBNBDiscount bool
+	// 30-day trading volume in quote currency selecting the fee tier
+	Volume30d float64
+}
+
+// FeeFor returns the maker or taker fee rate for a 30-day volume, falling back to the flat
+// MakerFee and TakerFee when no fee tiers are configured or the volume is below every tier
+func (c *Config) FeeFor(volume30d float64, isMaker bool) float64 {
+	fee := c.Trading.TakerFee
+	if isMaker {
+		fee = c.Trading.MakerFee
+	}
+	for _, tier := range c.Trading.FeeSchedule.Tiers {
+		if volume30d < tier.MinVolume {
+			break
+		}
+		fee = tier.TakerFee
+		if isMaker {
+			fee = tier.MakerFee
+		}
+	}
+	if c.Trading.FeeSchedule.UseBNBDiscount {
+		fee *= 1 - bnbFeeDiscount
+	}
+	return fee
+}
+
+// WithVolume returns a copy of the config whose fee tier is selected by volume30d, such as the
+// volume tracked by a VolumeTracker, when it exceeds the configured Volume30d
+func (c *Config) WithVolume(volume30d float64) *Config {
+	if volume30d <= c.Trading.FeeSchedule.Volume30d {
+		return c
+	}
+	scoped := *c
+	scoped.Trading.FeeSchedule.Volume30d = volume30d
+	return &scoped
+}
+
+// ParseFeeTiers parses fee tiers written as min_volume:maker_fee:taker_fee separated by commas,
+// such as "0:0.001:0.001,1000000:0.0009:0.001", and sorts them by volume
+func ParseFeeTiers(s string) ([]FeeTier, error) {
+	var tiers []FeeTier
+	for _, item := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("invalid fee tier %q, expected min_volume:maker_fee:taker_fee", item)
+		}
+		var values [3]float64
+		for i, part := range parts {
+			value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid fee tier %q: %v", item, err)
+			}
+			values[i] = value
+		}
+		tiers = append(tiers, FeeTier{MinVolume: values[0], MakerFee: values[1], TakerFee: values[2]})
+	}
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].MinVolume < tiers[j].MinVolume })
+	return tiers, nil
+}
+
+// formatFeeTiers formats fee tiers in the form ParseFeeTiers reads
+func formatFeeTiers(tiers []FeeTier) string {
+	items := make([]string, len(tiers))
+	for i, tier := range tiers {
+		items[i] = formatEnvFloat(tier.MinVolume) + ":" + formatEnvFloat(tier.MakerFee) + ":" + formatEnvFloat(tier.TakerFee)
+	}
+	return strings.Join(items, ",")
+}
+
+// getEnvFeeTiers reads fee tiers from an environment variable, keeping the default when unset or invalid
+func getEnvFeeTiers(key string, defaultValue []FeeTier) []FeeTier {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	tiers, err := ParseFeeTiers(value)
+	if err != nil {
+		log.Printf("Invalid fee tiers for %s: %v, using default\n", key, err)
+		return defaultValue
+	}
+	return tiers
+}
+
+// validateFeeSchedule checks the fee tiers are ascending by volume with fees between 0 and 1
+func validateFeeSchedule(s FeeSchedule) error {
+	for i, tier := range s.Tiers {
+		if tier.MinVolume < 0 {
+			return fmt.Errorf("fee tier %d minimum volume must be non-negative, got %f", i+1, tier.MinVolume)
+		}
+		if i > 0 && tier.MinVolume <= s.Tiers[i-1].MinVolume {
+			return fmt.Errorf("fee tier %d minimum volume must be greater than tier %d", i+1, i)
+		}
+		if tier.MakerFee < 0 || tier.MakerFee > 1 || tier.TakerFee < 0 || tier.TakerFee > 1 {
+			return fmt.Errorf("fee tier %d fees must be between 0 and 1, got maker %f and taker %f", i+1, tier.MakerFee, tier.TakerFee)
+		}
+	}
+	if s.Volume30d < 0 {
+		return fmt.Errorf("30-day volume must be non-negative, got %f", s.Volume30d)
+	}
+	return nil
+}
+
+// EffectiveMakerFee returns the maker fee net of any maker rebate; negative when the rebate exceeds the fee
+func (c *Config) EffectiveMakerFee() float64 {
+	return c.Trading.MakerFee - c.Trading.MakerRebateRate
+}
+
+// FeeLedger accumulates fees paid and maker rebates accrued for reporting
+type FeeLedger struct {
+	mu         sync.Mutex
+	makerFee   float64
+	takerFee   float64
+	rebateRate float64
+	fees       float64
+	rebates    float64
+}
+
+// NewFeeLedger creates a fee ledger using the configured fee and rebate rates
+func (c *Config) NewFeeLedger() *FeeLedger {
+	return &FeeLedger{
+		makerFee:   c.Trading.MakerFee,
+		takerFee:   c.Trading.TakerFee,
+		rebateRate: c.Trading.MakerRebateRate,
+	}
+}
+
+// reconfigure switches the ledger to the fee and rebate rates of c for later fills
+func (l *FeeLedger) reconfigure(c *Config) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.makerFee, l.takerFee, l.rebateRate = c.Trading.MakerFee, c.Trading.TakerFee, c.Trading.MakerRebateRate
+}
+
+// RecordFill records the fee and any maker rebate for a fill and returns its net fee
+func (l *FeeLedger) RecordFill(notional float64, isMaker bool) float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !isMaker {
+		fee := notional * l.takerFee
+		l.fees += fee
+		return fee
+	}
+
+	fee := notional * l.makerFee
+	rebate := notional * l.rebateRate
+	l.fees += fee
+	l.rebates += rebate
+	return fee - rebate
+}
+
+// Fees returns the gross fees paid
+func (l *FeeLedger) Fees() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.fees
+}
+
+// Rebates returns the maker rebates accrued
+func (l *FeeLedger) Rebates() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rebates
+}
+
+// NetFeesAfterRebates returns the fees paid minus maker rebates accrued
+func (l *FeeLedger) NetFeesAfterRebates() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.fees - l.rebates
+}
+
+// feeRate returns the maker (net of rebates) or taker fee rate of the tier for the 30-day volume
+func (c *Config) feeRate(isMaker bool) float64 {
+	volume := c.Trading.FeeSchedule.Volume30d
+	if isMaker {
+		return c.FeeFor(volume, true) - c.Trading.MakerRebateRate
+	}
+	return c.FeeFor(volume, false)
+}
+
+// entryFee returns the fee rate paid when entering a position; resting GTC limit entries pay
+// the maker fee
+func (c *Config) entryFee() float64 {
+	return c.feeRate(restsOnBook(c.Trading.OrderType, c.Trading.TimeInForce))
+}
+
+// exitFee returns the fee rate paid when a stop exits a position
+func (c *Config) exitFee() float64 {
+	return c.feeRate(false)
+}
+
+// TradeCostEstimate breaks down the expected cost of an order before it is submitted
+type TradeCostEstimate struct {
+	Notional float64
+	Fee      float64
+	// Expected cost of filling at the slippage tolerance instead of the quoted price
+	SlippageCost float64
+	// Notional plus fee and slippage
+	Total float64
+}
+
+// EstimateTradeCost estimates the cost of trading quantity at price. Maker orders rest at
+// their limit price and are assumed to fill without slippage; taker orders pay SlippageTolerance.
+func (c *Config) EstimateTradeCost(price, quantity float64, isMaker bool) TradeCostEstimate {
+	notional := price * quantity
+	estimate := TradeCostEstimate{
+		Notional: notional,
+		Fee:      notional * c.feeRate(isMaker),
+	}
+	if !isMaker {
+		estimate.SlippageCost = notional * c.Trading.SlippageTolerance
+	}
+	estimate.Total = estimate.Notional + estimate.Fee + estimate.SlippageCost
+	return estimate
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func tieredFeeConfig() *Config {
+	c := DefaultConfig()
+	c.Trading.MakerFee = 0.001
+	c.Trading.TakerFee = 0.001
+	c.Trading.MakerRebateRate = 0
+	c.Trading.FeeSchedule.Tiers = []FeeTier{
+		{MinVolume: 0, MakerFee: 0.001, TakerFee: 0.001},
+		{MinVolume: 1000000, MakerFee: 0.0009, TakerFee: 0.001},
+		{MinVolume: 5000000, MakerFee: 0.0008, TakerFee: 0.0009},
+	}
+	return c
+}
+
+func TestFeeForSelectsVolumeTier(t *testing.T) {
+	c := tieredFeeConfig()
+	tests := []struct {
+		volume  float64
+		isMaker bool
+		want    float64
+	}{
+		{0, true, 0.001},
+		{999999, true, 0.001},
+		{1000000, true, 0.0009},
+		{6000000, true, 0.0008},
+		{6000000, false, 0.0009},
+	}
+	for _, tt := range tests {
+		if got := c.FeeFor(tt.volume, tt.isMaker); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("FeeFor(%f, %t) = %f, want %f", tt.volume, tt.isMaker, got, tt.want)
+		}
+	}
+}
+
+func TestFeeRateUsesThirtyDayVolume(t *testing.T) {
+	c := tieredFeeConfig()
+	if got := c.feeRate(false); math.Abs(got-0.001) > 1e-12 {
+		t.Errorf("taker fee without volume = %f, want 0.001", got)
+	}
+	c.Trading.FeeSchedule.Volume30d = 5000000
+	if got := c.feeRate(false); math.Abs(got-0.0009) > 1e-12 {
+		t.Errorf("taker fee at the configured volume = %f, want 0.0009", got)
+	}
+}
+
+func TestWithVolumeKeepsTheHigherVolume(t *testing.T) {
+	c := tieredFeeConfig()
+	c.Trading.FeeSchedule.Volume30d = 2000000
+	if got := c.WithVolume(100); got != c {
+		t.Error("WithVolume below the configured volume returned a copy")
+	}
+	scoped := c.WithVolume(6000000)
+	if got := scoped.feeRate(true); math.Abs(got-0.0008) > 1e-12 {
+		t.Errorf("maker fee at the tracked volume = %f, want 0.0008", got)
+	}
+	if c.Trading.FeeSchedule.Volume30d != 2000000 {
+		t.Error("WithVolume modified the original config")
+	}
+}
+
+func TestBotRealizedProfitUsesTrackedVolumeTier(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	bot.config.Store(tieredFeeConfig())
+	bot.VolumeTracker().Record(6000000)
+	p := &Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 1, FilledQuantity: 1}
+
+	want := bot.config.Load().WithVolume(6000000).RealizedProfit(p, 110, 1)
+	if got := bot.feeConfig().RealizedProfit(p, 110, 1); math.Abs(got-want) > 1e-12 {
+		t.Errorf("realized profit = %f, want %f at the tracked volume tier", got, want)
+	}
+	if untiered := bot.config.Load().RealizedProfit(p, 110, 1); untiered >= want {
+		t.Errorf("realized profit at the lowest tier %f should be below %f", untiered, want)
+	}
+}
+
+func TestEstimateTradeCost(t *testing.T) {
+	c := DefaultConfig()
+	c.Trading.MakerFee = 0.001
+	c.Trading.TakerFee = 0.002
+	c.Trading.MakerRebateRate = 0
+	c.Trading.SlippageTolerance = 0.01
+	tests := []struct {
+		name    string
+		isMaker bool
+		want    TradeCostEstimate
+	}{
+		{"maker", true, TradeCostEstimate{Notional: 200, Fee: 0.2, SlippageCost: 0, Total: 200.2}},
+		{"taker", false, TradeCostEstimate{Notional: 200, Fee: 0.4, SlippageCost: 2, Total: 202.4}},
+	}
+	for _, tt := range tests {
+		got := c.EstimateTradeCost(100, 2, tt.isMaker)
+		if math.Abs(got.Notional-tt.want.Notional) > 1e-9 || math.Abs(got.Fee-tt.want.Fee) > 1e-9 ||
+			math.Abs(got.SlippageCost-tt.want.SlippageCost) > 1e-9 || math.Abs(got.Total-tt.want.Total) > 1e-9 {
+			t.Errorf("%s: EstimateTradeCost = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEntryFeeChargesTakerForIOCLimits(t *testing.T) {
+	c := DefaultConfig()
+	c.Trading.MakerFee = 0.001
+	c.Trading.TakerFee = 0.002
+	c.Trading.MakerRebateRate = 0
+	c.Trading.OrderType = OrderTypeLimit
+	c.Trading.TimeInForce = TimeInForceGTC
+	if got := c.entryFee(); got != 0.001 {
+		t.Errorf("GTC limit entry fee = %f, want the maker fee", got)
+	}
+	c.Trading.TimeInForce = TimeInForceIOC
+	if got := c.entryFee(); got != 0.002 {
+		t.Errorf("IOC limit entry fee = %f, want the taker fee", got)
+	}
+}
+
+func TestMakerRebatesReduceNetFeesOfMakerHeavySession(t *testing.T) {
+	c := DefaultConfig()
+	c.Logging.LogConfigOnStart = false
+	c.CopyTrading.CopyDelay = 0
+	c.CopyTrading.CopyDelayJitter = 0
+	c.Trading.OrderType = OrderTypeLimit
+	c.Trading.TimeInForce = TimeInForceGTC
+	c.Trading.MakerFee = 0.0002
+	c.Trading.TakerFee = 0.0005
+	c.Trading.MakerRebateRate = 0.0001
+	c.RiskManagement.MaxBaseAssetExposure = 1
+	bot, err := NewBot(c, &fakeExecutor{fillRatio: 1}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewBot: %v", err)
+	}
+
+	// Three maker entries and one taker close
+	var makerNotional float64
+	for _, symbol := range []string{"BNBUSDT", "ETHUSDT", "SOLUSDT"} {
+		signal := testSignal()
+		signal.Symbol = symbol
+		p, err := bot.HandleSignal(context.Background(), signal)
+		if err != nil || p == nil {
+			t.Fatalf("HandleSignal(%s) = %+v, %v", symbol, p, err)
+		}
+		makerNotional += p.EntryPrice * p.FilledQuantity
+	}
+	close, err := bot.ClosePosition(context.Background(), bot.OpenPositions()[0].ID, math.Inf(1))
+	if err != nil {
+		t.Fatalf("ClosePosition: %v", err)
+	}
+	takerNotional := close.Price * close.Quantity
+
+	ledger := bot.FeeLedger()
+	wantFees := makerNotional*0.0002 + takerNotional*0.0005
+	wantRebates := makerNotional * 0.0001
+	if math.Abs(ledger.Fees()-wantFees) > 1e-9 || math.Abs(ledger.Rebates()-wantRebates) > 1e-9 {
+		t.Errorf("fees = %f, rebates = %f; want %f and %f", ledger.Fees(), ledger.Rebates(), wantFees, wantRebates)
+	}
+	if net := ledger.NetFeesAfterRebates(); math.Abs(net-(wantFees-wantRebates)) > 1e-9 || net >= ledger.Fees() {
+		t.Errorf("net fees = %f, want %f, below the gross %f", net, wantFees-wantRebates, ledger.Fees())
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// CopyFilter decides from recent closes whether a copy signal may be entered
+type CopyFilter interface {
+	// Update adds a closed candle's price for symbol
+	Update(symbol string, close float64)
+	// Allow reports whether signal may be copied and why not when it may not
+	Allow(signal CopySignal) (bool, string)
+}
+
+// NewCopyFilters creates the enabled entry filters
+func (c *Config) NewCopyFilters() []CopyFilter {
+	var filters []CopyFilter
+	if c.EntryFilters.TrendFilterEnabled {
+		filters = append(filters, c.NewTrendFilter())
+	}
+	if c.EntryFilters.RSIGuardEnabled {
+		filters = append(filters, c.NewRSIGuard())
+	}
+	return filters
+}
+
+// UpdateCopyFilters adds a closed candle's price to every filter
+func UpdateCopyFilters(filters []CopyFilter, symbol string, close float64) {
+	for _, filter := range filters {
+		filter.Update(symbol, close)
+	}
+}
+
+// AllowCopy reports whether every filter allows signal and, when one does not, why
+func AllowCopy(filters []CopyFilter, signal CopySignal) (bool, string) {
+	for _, filter := range filters {
+		if ok, reason := filter.Allow(signal); !ok {
+			return false, reason
+		}
+	}
+	return true, ""
+}
+
+// trendAverages holds a symbol's fast and slow moving averages
+type trendAverages struct {
+	fast *SMA
+	slow *SMA
+}
+
+// TrendFilter only allows copies in the direction of the moving average crossover: longs
+// while the fast SMA is above the slow SMA and shorts while it is below
+type TrendFilter struct {
+	mu         sync.Mutex
+	fastPeriod int
+	slowPeriod int
+	averages   map[string]*trendAverages
+}
+
+// NewTrendFilter creates a trend filter from the configured SMA periods
+func (c *Config) NewTrendFilter() *TrendFilter {
+	return &TrendFilter{
+		fastPeriod: c.EntryFilters.TrendFastPeriod,
+		slowPeriod: c.EntryFilters.TrendSlowPeriod,
+		averages:   make(map[string]*trendAverages),
+	}
+}
+
+// Update adds a closed candle's price for symbol
+func (f *TrendFilter) Update(symbol string, close float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	averages, ok := f.averages[symbol]
+	if !ok {
+		averages = &trendAverages{fast: NewSMA(f.fastPeriod), slow: NewSMA(f.slowPeriod)}
+		f.averages[symbol] = averages
+	}
+	averages.fast.Update(close)
+	averages.slow.Update(close)
+}
+
+// Allow reports whether signal follows the trend; copies are skipped until the slow SMA is ready
+func (f *TrendFilter) Allow(signal CopySignal) (bool, string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	averages, ok := f.averages[signal.Symbol]
+	if !ok || !averages.slow.Ready() {
+		return false, fmt.Sprintf("trend filter needs %d closes", f.slowPeriod)
+	}
+	fast, slow := averages.fast.Value(), averages.slow.Value()
+	if (isBuy(signal.Side) && fast > slow) || (!isBuy(signal.Side) && fast < slow) {
+		return true, ""
+	}
+	return false, fmt.Sprintf("counter-trend, fast SMA %f, slow SMA %f", fast, slow)
+}
+
+// RSIGuard blocks longs while a symbol's RSI is above the overbought level and shorts while it
+// is below the oversold level, so exhausted moves are not chased
+type RSIGuard struct {
+	mu         sync.Mutex
+	period     int
+	overbought float64
+	oversold   float64
+	rsi        map[string]*RSI
+}
+
+// NewRSIGuard creates an RSI guard from the configured period and levels
+func (c *Config) NewRSIGuard() *RSIGuard {
+	return &RSIGuard{
+		period:     c.EntryFilters.RSIPeriod,
+		overbought: c.EntryFilters.RSIOverbought,
+		oversold:   c.EntryFilters.RSIOversold,
+		rsi:        make(map[string]*RSI),
+	}
+}
+
+// Update adds a closed candle's price for symbol
+func (g *RSIGuard) Update(symbol string, close float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	rsi, ok := g.rsi[symbol]
+	if !ok {
+		rsi = NewRSI(g.period)
+		g.rsi[symbol] = rsi
+	}
+	rsi.Update(close)
+}
+
+// Allow reports whether signal is not chasing an overbought or oversold move; signals pass
+// until the RSI is ready
+func (g *RSIGuard) Allow(signal CopySignal) (bool, string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	rsi, ok := g.rsi[signal.Symbol]
+	if !ok || !rsi.Ready() {
+		return true, ""
+	}
+	value := rsi.Value()
+	if isBuy(signal.Side) && value > g.overbought {
+		return false, fmt.Sprintf("RSI %.1f is above overbought %.1f", value, g.overbought)
+	}
+	if !isBuy(signal.Side) && value < g.oversold {
+		return false, fmt.Sprintf("RSI %.1f is below oversold %.1f", value, g.oversold)
+	}
+	return true, ""
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+// newFilteredTestBot returns a test bot with its entry filters built from configure
+func newFilteredTestBot(t *testing.T, configure func(c *EntryFiltersConfig)) *Bot {
+	t.Helper()
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	config := *bot.config.Load()
+	configure(&config.EntryFilters)
+	bot.Reconfigure(&config)
+	return bot
+}
+
+// feedPrices refreshes BNBUSDT at each of prices in turn
+func feedPrices(t *testing.T, bot *Bot, prices ...float64) {
+	t.Helper()
+	for _, price := range prices {
+		fetch := func(ctx context.Context, symbol string) (float64, error) { return price, nil }
+		if err := bot.RefreshPrices(context.Background(), []string{"BNBUSDT"}, fetch); err != nil {
+			t.Fatalf("RefreshPrices: %v", err)
+		}
+	}
+}
+
+func TestAllowEntryFollowsTrendFilter(t *testing.T) {
+	bot := newFilteredTestBot(t, func(c *EntryFiltersConfig) {
+		c.TrendFilterEnabled, c.TrendFastPeriod, c.TrendSlowPeriod = true, 2, 4
+	})
+	long := CopySignal{LeaderAddress: "0xleader", Symbol: "BNBUSDT", Side: SideBuy}
+	short := CopySignal{LeaderAddress: "0xleader", Symbol: "BNBUSDT", Side: SideSell}
+
+	feedPrices(t, bot, 100, 101, 102)
+	if bot.AllowEntry(long) {
+		t.Error("AllowEntry allowed a copy before the slow SMA was ready")
+	}
+
+	feedPrices(t, bot, 103)
+	if !bot.AllowEntry(long) {
+		t.Error("AllowEntry refused a long in an uptrend")
+	}
+	if bot.AllowEntry(short) {
+		t.Error("AllowEntry allowed a short in an uptrend")
+	}
+}
+
+func TestAllowEntryBlocksOverboughtLongs(t *testing.T) {
+	bot := newFilteredTestBot(t, func(c *EntryFiltersConfig) {
+		c.RSIGuardEnabled, c.RSIPeriod, c.RSIOverbought, c.RSIOversold = true, 3, 70, 30
+	})
+	long := CopySignal{LeaderAddress: "0xleader", Symbol: "BNBUSDT", Side: SideBuy}
+	short := CopySignal{LeaderAddress: "0xleader", Symbol: "BNBUSDT", Side: SideSell}
+
+	if !bot.AllowEntry(long) {
+		t.Error("AllowEntry refused a long before the RSI was ready")
+	}
+	feedPrices(t, bot, 100, 102, 104, 106)
+	if bot.AllowEntry(long) {
+		t.Error("AllowEntry allowed a long at RSI 100")
+	}
+	if !bot.AllowEntry(short) {
+		t.Error("AllowEntry refused a short at RSI 100")
+	}
+}
+
+func TestHandleSignalSkipsFilteredCopy(t *testing.T) {
+	executor := &fakeExecutor{fillRatio: 1}
+	bot := newTestBot(t, executor)
+	config := *bot.config.Load()
+	config.EntryFilters.TrendFilterEnabled = true
+	bot.Reconfigure(&config)
+
+	p, err := bot.HandleSignal(context.Background(), testSignal())
+	if err != nil || p != nil {
+		t.Fatalf("HandleSignal = %v, %v; want the copy skipped without closes", p, err)
+	}
+	if len(executor.orders) != 0 {
+		t.Errorf("%d orders submitted for a filtered copy, want none", len(executor.orders))
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// fundingInterval is the time between Binance perpetual futures funding payments
+const fundingInterval = 8 * time.Hour
+
+// FundingRateFunc fetches the current funding rate of a perpetual futures symbol
+type FundingRateFunc func(ctx context.Context, symbol string) (float64, error)
+
+// FundingRate is a funding rate observed at a time
+type FundingRate struct {
+	Rate float64
+	Time time.Time
+}
+
+// FundingTracker records perpetual futures funding rates per symbol
+type FundingTracker struct {
+	mu    sync.Mutex
+	fetch FundingRateFunc
+	rates map[string]FundingRate
+	now   func() time.Time
+}
+
+// NewFundingTracker creates a funding tracker; fetch may be nil when rates are only recorded
+func NewFundingTracker(fetch FundingRateFunc) *FundingTracker {
+	return &FundingTracker{
+		fetch: fetch,
+		rates: make(map[string]FundingRate),
+		now:   time.Now,
+	}
+}
+
+// Refresh fetches and records the current funding rate of symbol
+func (t *FundingTracker) Refresh(ctx context.Context, symbol string) error {
+	if t.fetch == nil {
+		return fmt.Errorf("no funding rate source configured")
+	}
+	rate, err := t.fetch(ctx, symbol)
+	if err != nil {
+		return fmt.Errorf("error fetching %s funding rate: %v", symbol, err)
+	}
+	t.Record(symbol, rate)
+	return nil
+}
+
+// Record records the current funding rate of symbol
+func (t *FundingTracker) Record(symbol string, rate float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rates[symbol] = FundingRate{Rate: rate, Time: t.now()}
+}
+
+// Rate returns the last recorded funding rate of symbol
+func (t *FundingTracker) Rate(symbol string) (FundingRate, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	rate, ok := t.rates[symbol]
+	return rate, ok
+}
+
+// AccruedFunding estimates the funding paid by an open position up to now at the last recorded
+// rate of its symbol; negative when the position received funding
+func (t *FundingTracker) AccruedFunding(p *Position, now time.Time) float64 {
+	rate, ok := t.Rate(p.Symbol)
+	if !ok {
+		return 0
+	}
+	return EstimateFundingCost(rate.Rate, p.EntryPrice*p.FilledQuantity, p.Side, p.OpenedAt, now)
+}
+
+// FundingPayments returns the number of funding timestamps, at 00:00, 08:00 and 16:00 UTC,
+// after openedAt and up to now. A position opened at 07:59 pays at 08:00 after a minute.
+func FundingPayments(openedAt, now time.Time) int {
+	if openedAt.IsZero() || !now.After(openedAt) {
+		return 0
+	}
+	// the zero time is midnight UTC, so truncating to the interval lands on a funding timestamp
+	return int(now.Truncate(fundingInterval).Sub(openedAt.Truncate(fundingInterval)) / fundingInterval)
+}
+
+// EstimateFundingCost returns the funding paid by a position of notional held from openedAt to
+// now at rate per funding payment. Longs pay a positive rate and shorts receive it; a negative
+// result is funding received.
+func EstimateFundingCost(rate, notional float64, side string, openedAt, now time.Time) float64 {
+	cost := rate * notional * float64(FundingPayments(openedAt, now))
+	if !isLong(side) {
+		return -cost
+	}
+	return cost
+}
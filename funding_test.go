@@ -0,0 +1,56 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFundingPaymentsCountsTimestampsCrossed(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 3, 1, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name     string
+		openedAt time.Time
+		now      time.Time
+		want     int
+	}{
+		{"one minute across 08:00", at(7, 59), at(8, 0), 1},
+		{"seven hours between timestamps", at(8, 30), at(15, 30), 0},
+		{"exactly eight hours from a timestamp", at(8, 0), at(16, 0), 1},
+		{"across midnight", at(15, 0), at(0, 30).Add(24 * time.Hour), 2},
+		{"now before opened", at(10, 0), at(9, 0), 0},
+		{"zero opened", time.Time{}, at(9, 0), 0},
+		{"non-UTC location", at(7, 0).In(time.FixedZone("UTC+8", 8*3600)), at(9, 0), 1},
+	}
+	for _, tt := range tests {
+		if got := FundingPayments(tt.openedAt, tt.now); got != tt.want {
+			t.Errorf("%s: FundingPayments = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEstimateFundingCostBySide(t *testing.T) {
+	opened := time.Date(2024, 3, 1, 7, 0, 0, 0, time.UTC)
+	now := opened.Add(10 * time.Hour) // crosses 08:00 and 16:00
+	if got := EstimateFundingCost(0.0001, 10000, SideLong, opened, now); math.Abs(got-2) > 1e-9 {
+		t.Errorf("long funding = %f, want 2", got)
+	}
+	if got := EstimateFundingCost(0.0001, 10000, SideShort, opened, now); math.Abs(got+2) > 1e-9 {
+		t.Errorf("short funding = %f, want -2", got)
+	}
+}
+
+func TestAccruedFundingUsesRecordedRate(t *testing.T) {
+	tracker := NewFundingTracker(nil)
+	opened := time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)
+	p := &Position{Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, FilledQuantity: 10, OpenedAt: opened}
+	if got := tracker.AccruedFunding(p, opened.Add(2*time.Hour)); got != 0 {
+		t.Errorf("AccruedFunding without a rate = %f, want 0", got)
+	}
+	tracker.Record("BNBUSDT", 0.001)
+	if got := tracker.AccruedFunding(p, opened.Add(2*time.Hour)); math.Abs(got-1) > 1e-9 {
+		t.Errorf("AccruedFunding = %f, want 1", got)
+	}
+}
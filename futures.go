@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// defaultMaxLeverage is the highest leverage Binance offers on any perpetual, used for symbols
+// without a configured maximum
+const defaultMaxLeverage = 125
+
+// MaxLeverage returns the highest leverage allowed on symbol
+func (c *Config) MaxLeverage(symbol string) int {
+	if max, ok := c.Futures.SymbolMaxLeverage[symbol]; ok {
+		return max
+	}
+	return defaultMaxLeverage
+}
+
+// leverage returns the configured leverage in futures mode and 1 for spot
+func (c *Config) leverage() float64 {
+	if !c.FuturesMode {
+		return 1
+	}
+	return float64(c.Futures.Leverage)
+}
+
+// LiquidationPrice estimates the isolated-margin liquidation price of a position entered at
+// entryPrice using Binance's formula for the first maintenance margin bracket, where the
+// initial margin of 1/leverage is exhausted down to the maintenance margin at that price.
+// It returns 0 for unleveraged positions, which cannot be liquidated.
+func (c *Config) LiquidationPrice(entryPrice, leverage float64, side string, maintenanceMarginRate float64) float64 {
+	if leverage <= 1 {
+		return 0
+	}
+	if isBuy(side) {
+		return entryPrice * (1 - 1/leverage) / (1 - maintenanceMarginRate)
+	}
+	return entryPrice * (1 + 1/leverage) / (1 + maintenanceMarginRate)
+}
+
+// StopBeyondLiquidation reports whether a stop at stopPrice would only trigger after the
+// position is liquidated at the configured leverage
+func (c *Config) StopBeyondLiquidation(entryPrice, stopPrice float64, side string) bool {
+	liquidation := c.LiquidationPrice(entryPrice, c.leverage(), side, c.Futures.MaintenanceMarginRate)
+	if liquidation == 0 || stopPrice == 0 {
+		return false
+	}
+	if isBuy(side) {
+		return stopPrice <= liquidation
+	}
+	return stopPrice >= liquidation
+}
+
+// WarnStopBeyondLiquidation notifies when the position's effective stop lies beyond its
+// liquidation price, returning whether it does
+func (c *Config) WarnStopBeyondLiquidation(ctx context.Context, notifier *Notifier, p *Position) (bool, error) {
+	stop := c.EffectiveStopPrice(p)
+	if !c.FuturesMode || !c.StopBeyondLiquidation(p.EntryPrice, stop, p.Side) {
+		return false, nil
+	}
+	liquidation := c.LiquidationPrice(p.EntryPrice, c.leverage(), p.Side, c.Futures.MaintenanceMarginRate)
+	message := fmt.Sprintf("⚠️ %s %s position %s: stop %f is beyond the estimated liquidation price %f at %dx leverage",
+		p.Symbol, p.Side, p.ID, stop, liquidation, c.Futures.Leverage)
+	return true, notifier.Send(ctx, message)
+}
+
+// MarginRequired returns the margin needed to hold notional at the configured leverage;
+// spot positions need the full notional
+func (c *Config) MarginRequired(notional float64) float64 {
+	return notional / c.leverage()
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"math"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// binanceIsolatedLiquidation is Binance's published isolated-margin liquidation formula for a
+// single position in the first bracket: (WB - side*size*entry) / (size*MMR - side*size),
+// where the wallet balance WB is the initial margin size*entry/leverage
+func binanceIsolatedLiquidation(entry, size, leverage, mmr float64, long bool) float64 {
+	side := -1.0
+	if long {
+		side = 1
+	}
+	wallet := size * entry / leverage
+	return (wallet - side*size*entry) / (size*mmr - side*size)
+}
+
+func TestLiquidationPriceMatchesBinanceExamples(t *testing.T) {
+	c := DefaultConfig()
+	tests := []struct {
+		name     string
+		entry    float64
+		leverage float64
+		side     string
+		mmr      float64
+		want     float64
+	}{
+		// The common 100x example: a long at 10,000 liquidates near 9,940
+		{"100x long", 10000, 100, SideLong, 0.004, 9939.759036},
+		{"20x long", 30000, 20, SideBuy, 0.004, 28614.457831},
+		{"20x short", 30000, 20, SideShort, 0.004, 31374.501992},
+		{"125x short", 2000, 125, SideSell, 0.005, 2005.970149},
+	}
+	for _, tt := range tests {
+		got := c.LiquidationPrice(tt.entry, tt.leverage, tt.side, tt.mmr)
+		if math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("%s: LiquidationPrice = %f, want %f", tt.name, got, tt.want)
+		}
+		if formula := binanceIsolatedLiquidation(tt.entry, 2.5, tt.leverage, tt.mmr, isBuy(tt.side)); math.Abs(got-formula) > 1e-6 {
+			t.Errorf("%s: LiquidationPrice = %f, Binance formula gives %f", tt.name, got, formula)
+		}
+	}
+	if got := c.LiquidationPrice(100, 1, SideLong, 0.004); got != 0 {
+		t.Errorf("unleveraged LiquidationPrice = %f, want 0", got)
+	}
+}
+
+func TestValidateRefusesStopBeyondLiquidation(t *testing.T) {
+	c := testnetConfig()
+	c.FuturesMode = true
+	c.Futures.MaintenanceMarginRate = 0.004
+	c.RiskManagement.StopLossPercentage = 0.03
+	c.Futures.Leverage = 20
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate at 20x = %v", err)
+	}
+	// At 30x a long liquidates 2.95% below entry, before a 3% stop
+	c.Futures.Leverage = 30
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "liquidation") {
+		t.Errorf("Validate at 30x = %v, want a liquidation error", err)
+	}
+}
+
+func TestWarnStopBeyondLiquidationNotifies(t *testing.T) {
+	webhook := &recordingWebhook{}
+	server := httptest.NewServer(webhook)
+	defer server.Close()
+
+	c := DefaultConfig()
+	c.FuturesMode = true
+	c.Futures.Leverage = 20
+	c.Futures.MaintenanceMarginRate = 0.004
+	c.WebhookURL = server.URL
+	c.NotificationsEnabled = true
+	notifier := c.NewNotifier()
+	ctx := context.Background()
+
+	safe := &Position{ID: "safe", Symbol: "BTCUSDT", Side: SideLong, EntryPrice: 30000, AbsoluteStopPrice: 29000}
+	if warned, err := c.WarnStopBeyondLiquidation(ctx, notifier, safe); warned || err != nil {
+		t.Errorf("stop above liquidation warned = %v, %v", warned, err)
+	}
+	risky := &Position{ID: "risky", Symbol: "BTCUSDT", Side: SideLong, EntryPrice: 30000, AbsoluteStopPrice: 28000}
+	if warned, err := c.WarnStopBeyondLiquidation(ctx, notifier, risky); !warned || err != nil {
+		t.Errorf("stop below liquidation warned = %v, %v", warned, err)
+	}
+	if messages := webhook.received(); len(messages) != 1 || !strings.Contains(messages[0], "risky") {
+		t.Errorf("notifications = %v, want one for the risky position", messages)
+	}
+}
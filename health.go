@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// healthCheckTimeout bounds how long the readiness checks may take together
+const healthCheckTimeout = 5 * time.Second
+
+// HealthCheck reports an error when a dependency of the bot is not ready
+type HealthCheck func(ctx context.Context) error
+
+// namedHealthCheck is a readiness check reported under name
+type namedHealthCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// Degradable reports whether a component has flagged itself as degraded
+type Degradable interface {
+	Degraded() bool
+}
+
+// HealthServer serves liveness and readiness probes for container orchestrators, and the
+// pause controls when enabled
+type HealthServer struct {
+	mu           sync.Mutex
+	checks       []namedHealthCheck
+	pausable     Pausable
+	controlToken string
+	liveness     Degradable
+}
+
+// NewHealthServer creates a health server without readiness checks
+func NewHealthServer() *HealthServer {
+	return &HealthServer{}
+}
+
+// AddCheck adds a readiness check reported under name
+func (h *HealthServer) AddCheck(name string, check HealthCheck) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks = append(h.checks, namedHealthCheck{name: name, check: check})
+}
+
+// EnableControl reports target's paused state on /healthz and, when token is set, serves
+// POST /pause and /resume to requests carrying it as a bearer token
+func (h *HealthServer) EnableControl(target Pausable, token string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pausable = target
+	h.controlToken = token
+}
+
+// EnableLiveness reports source's degraded status on /healthz
+func (h *HealthServer) EnableLiveness(source Degradable) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.liveness = source
+}
+
+// Ready runs every readiness check and returns the failures by check name
+func (h *HealthServer) Ready(ctx context.Context) map[string]string {
+	h.mu.Lock()
+	checks := append([]namedHealthCheck(nil), h.checks...)
+	h.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	failures := make(map[string]string)
+	for _, check := range checks {
+		if err := check.check(ctx); err != nil {
+			failures[check.name] = err.Error()
+		}
+	}
+	return failures
+}
+
+// Handler returns the handler serving /healthz, which succeeds while the process is alive and
+// reports a degraded status without failing, and /readyz, which fails with 503 when any
+// readiness check fails
+func (h *HealthServer) Handler() http.Handler {
+	h.mu.Lock()
+	pausable, token, liveness := h.pausable, h.controlToken, h.liveness
+	h.mu.Unlock()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]interface{}{"status": "ok"}
+		if liveness != nil && liveness.Degraded() {
+			body["status"] = "degraded"
+		}
+		if pausable != nil {
+			body["paused"] = pausable.Paused()
+		}
+		writeHealthResponse(w, http.StatusOK, body)
+	})
+	if pausable != nil && token != "" {
+		registerControlRoutes(mux, pausable, token)
+	}
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		failures := h.Ready(r.Context())
+		if len(failures) > 0 {
+			writeHealthResponse(w, http.StatusServiceUnavailable, map[string]interface{}{"status": "not ready", "failures": failures})
+			return
+		}
+		writeHealthResponse(w, http.StatusOK, map[string]interface{}{"status": "ready"})
+	})
+	return mux
+}
+
+// writeHealthResponse writes a JSON probe response
+func writeHealthResponse(w http.ResponseWriter, status int, body map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// Serve serves the health endpoints on port until ctx is done
+func (h *HealthServer) Serve(ctx context.Context, port int) error {
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           h.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("error serving health checks on port %d: %v", port, err)
+	}
+	return nil
+}
+
+// ExchangeHealthCheck checks exchange connectivity by fetching the server time
+func ExchangeHealthCheck(serverTime ServerTimeFunc) HealthCheck {
+	return func(ctx context.Context) error {
+		if _, err := serverTime(ctx); err != nil {
+			return fmt.Errorf("exchange unreachable: %v", err)
+		}
+		return nil
+	}
+}
+
+// ConfigHealthCheck checks a valid configuration is loaded
+func ConfigHealthCheck(live *LiveConfig) HealthCheck {
+	return func(ctx context.Context) error {
+		if live.Get() == nil {
+			return fmt.Errorf("configuration not loaded")
+		}
+		return nil
+	}
+}
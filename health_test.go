@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// getHealth requests path from h and decodes the JSON body
+func getHealth(t *testing.T, h *HealthServer, path string) (int, map[string]interface{}) {
+	t.Helper()
+	server := httptest.NewServer(h.Handler())
+	defer server.Close()
+	resp, err := http.Get(server.URL + path)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	defer resp.Body.Close()
+	var body map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding %s response: %v", path, err)
+	}
+	return resp.StatusCode, body
+}
+
+func TestHealthzReportsAliveAndPaused(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	bot.Pause()
+	health := NewHealthServer()
+	health.EnableControl(bot, "")
+	health.AddCheck("exchange", func(ctx context.Context) error { return errors.New("unreachable") })
+
+	status, body := getHealth(t, health, "/healthz")
+	if status != http.StatusOK || body["status"] != "ok" || body["paused"] != true {
+		t.Errorf("/healthz = %d %v, want 200 ok and paused even with a failing readiness check", status, body)
+	}
+}
+
+func TestReadyzFailsWhileAnyCheckFails(t *testing.T) {
+	var exchangeErr error
+	health := NewHealthServer()
+	health.AddCheck("config", func(ctx context.Context) error { return nil })
+	health.AddCheck("exchange", func(ctx context.Context) error { return exchangeErr })
+
+	if status, body := getHealth(t, health, "/readyz"); status != http.StatusOK || body["status"] != "ready" {
+		t.Errorf("/readyz = %d %v, want 200 ready", status, body)
+	}
+
+	exchangeErr = errors.New("exchange unreachable")
+	status, body := getHealth(t, health, "/readyz")
+	failures, _ := body["failures"].(map[string]interface{})
+	if status != http.StatusServiceUnavailable || failures["exchange"] != "exchange unreachable" || failures["config"] != nil {
+		t.Errorf("/readyz = %d %v, want 503 naming only the exchange check", status, body)
+	}
+}
+
+func TestReadyzFailsOnStaleMarketData(t *testing.T) {
+	stream, err := marketDataConfig(MarketDataWebSocket, "BNBUSDT").NewMarketDataStream(func(ctx context.Context, symbol string) (float64, error) {
+		return 1, nil
+	})
+	if err != nil {
+		t.Fatalf("NewMarketDataStream: %v", err)
+	}
+	health := NewHealthServer()
+	health.AddCheck("market data", stream.HealthCheck)
+
+	if status, _ := getHealth(t, health, "/readyz"); status != http.StatusServiceUnavailable {
+		t.Errorf("/readyz with the websocket disconnected = %d, want 503", status)
+	}
+
+	stream.connected.Store(true)
+	if status, _ := getHealth(t, health, "/readyz"); status != http.StatusServiceUnavailable {
+		t.Errorf("/readyz before any price arrived = %d, want 503", status)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		stream.Track()
+		close(done)
+	}()
+	stream.updates <- MarketUpdate{Symbol: "BNBUSDT", Price: 600}
+	close(stream.updates)
+	<-done
+	if status, body := getHealth(t, health, "/readyz"); status != http.StatusOK {
+		t.Errorf("/readyz with a fresh price = %d %v, want 200", status, body)
+	}
+
+	stream.pollInterval = 0
+	if status, _ := getHealth(t, health, "/readyz"); status != http.StatusServiceUnavailable {
+		t.Errorf("/readyz with a stale price = %d, want 503", status)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import "time"
+
+// PositionTimer enforces the maximum hold time of a position
+type PositionTimer struct {
+	openedAt time.Time
+	maxHold  time.Duration
+	enabled  bool
+}
+
+// NewPositionTimer creates a timer for a position opened at openedAt enforcing MaxHoldTime
+// when CloseOnTimeout is set
+func (c *Config) NewPositionTimer(openedAt time.Time) *PositionTimer {
+	return &PositionTimer{
+		openedAt: openedAt,
+		maxHold:  time.Duration(c.MultiTier.MaxHoldTime) * time.Minute,
+		enabled:  c.MultiTier.CloseOnTimeout,
+	}
+}
+
+// ShouldClose reports whether the position has been held for the maximum hold time,
+// regardless of which tiers were reached
+func (t *PositionTimer) ShouldClose(now time.Time) bool {
+	return t.enabled && now.Sub(t.openedAt) >= t.maxHold
+}
+
+// Deadline returns the time the position must be closed by, zero when the timeout is disabled
+func (t *PositionTimer) Deadline() time.Time {
+	if !t.enabled {
+		return time.Time{}
+	}
+	return t.openedAt.Add(t.maxHold)
+}
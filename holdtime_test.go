@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPositionTimerClosesAfterMaxHoldTime(t *testing.T) {
+	c := DefaultConfig()
+	c.MultiTier.CloseOnTimeout = true
+	c.MultiTier.MaxHoldTime = 240
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	timer := c.NewPositionTimer(now.Add(-4 * time.Hour))
+	if !timer.ShouldClose(now) {
+		t.Error("position opened 4 hours ago with a 240-minute limit should close")
+	}
+	if got, want := timer.Deadline(), now; !got.Equal(want) {
+		t.Errorf("Deadline = %s, want %s", got, want)
+	}
+	if c.NewPositionTimer(now.Add(-239 * time.Minute)).ShouldClose(now) {
+		t.Error("position opened 239 minutes ago should stay open")
+	}
+}
+
+func TestPositionTimerDisabled(t *testing.T) {
+	c := DefaultConfig()
+	c.MultiTier.CloseOnTimeout = false
+	c.MultiTier.MaxHoldTime = 240
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	timer := c.NewPositionTimer(now.Add(-24 * time.Hour))
+	if timer.ShouldClose(now) {
+		t.Error("timer without CloseOnTimeout closed the position")
+	}
+	if !timer.Deadline().IsZero() {
+		t.Errorf("Deadline = %s, want zero when disabled", timer.Deadline())
+	}
+}
+
+func TestPositionWithoutFillTimeDoesNotTimeOutImmediately(t *testing.T) {
+	c := DefaultConfig()
+	c.MultiTier.CloseOnTimeout = true
+	c.MultiTier.MaxHoldTime = 240
+
+	p := NewPositionFromFill("p", SideLong, Fill{Order: Order{Symbol: "BNBUSDT", Quantity: 1, Price: 100}, Price: 100, Quantity: 1})
+	if p.OpenedAt.IsZero() {
+		t.Fatal("position without a fill time has a zero open time")
+	}
+	if c.NewPositionTimer(p.OpenedAt).ShouldClose(time.Now()) {
+		t.Error("position without a fill time timed out immediately")
+	}
+}
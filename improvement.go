@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// entryImprovementPollInterval is how often a resting improved entry is checked for fills
+const entryImprovementPollInterval = time.Second
+
+// entryImprovementCancelTimeout bounds cancelling a resting entry after its context is done
+const entryImprovementCancelTimeout = 5 * time.Second
+
+// OrderQuerier reports the cumulative fill of an order
+type OrderQuerier interface {
+	QueryOrder(ctx context.Context, symbol, orderID string) (Fill, error)
+}
+
+// OrderManager can query and cancel the orders it submitted
+type OrderManager interface {
+	OrderQuerier
+	OrderCanceler
+}
+
+// ImprovedEntryPrice returns the limit price for copying a leader fill at leaderPrice,
+// OffsetPercentage better than the leader: below it for buys and above it for sells
+func (c *Config) ImprovedEntryPrice(leaderPrice float64, side string) float64 {
+	if isBuy(side) {
+		return leaderPrice * (1 - c.EntryImprovement.OffsetPercentage)
+	}
+	return leaderPrice * (1 + c.EntryImprovement.OffsetPercentage)
+}
+
+// EntryImprover tries to enter at a better price than the leader with a resting limit order,
+// market-filling whatever is left once the timeout passes
+type EntryImprover struct {
+	config   *Config
+	executor OrderExecutor
+	orders   OrderManager
+	timeout  time.Duration
+}
+
+// NewEntryImprover creates an entry improver submitting through executor and tracking the
+// resting order through orders
+func (c *Config) NewEntryImprover(executor OrderExecutor, orders OrderManager) *EntryImprover {
+	return &EntryImprover{
+		config:   c,
+		executor: executor,
+		orders:   orders,
+		timeout:  time.Duration(c.EntryImprovement.Timeout) * time.Second,
+	}
+}
+
+// Enter buys or sells quantity of symbol, first with a limit at ImprovedEntryPrice and then at
+// market for the remainder after the timeout. It returns the limit fill followed by the market
+// fill when one was needed; add the second to the position with ApplyFill. Fills are returned
+// alongside an error whenever part of the entry executed.
+func (e *EntryImprover) Enter(ctx context.Context, symbol, side string, quantity, leaderPrice float64) ([]Fill, error) {
+	limit := Order{
+		Symbol:      symbol,
+		Side:        side,
+		Quantity:    quantity,
+		Price:       e.config.ImprovedEntryPrice(leaderPrice, side),
+		Type:        OrderTypeLimit,
+		TimeInForce: TimeInForceGTC,
+	}
+	fill, err := e.executor.Submit(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error placing improved entry for %s: %v", symbol, err)
+	}
+
+	if fill.Quantity < quantity && fill.OrderID != "" {
+		fill, err = e.await(ctx, fill, quantity)
+		if err != nil {
+			if fill.Quantity > 0 {
+				return []Fill{fill}, err
+			}
+			return nil, err
+		}
+	}
+	fills := []Fill{fill}
+	remaining := quantity - fill.Quantity
+	if remaining <= quantity*tierPercentageEpsilon {
+		return fills, nil
+	}
+
+	market := Order{Symbol: symbol, Side: side, Quantity: remaining, Price: leaderPrice, Type: OrderTypeMarket}
+	marketFill, err := e.executor.Submit(ctx, market)
+	if err != nil {
+		return fills, fmt.Errorf("error market-filling %f of improved entry for %s: %v", remaining, symbol, err)
+	}
+	return append(fills, marketFill), nil
+}
+
+// await polls the resting order until it fills or the timeout passes, then cancels it and
+// returns its final cumulative fill. An order that filled before the cancel arrived is
+// reported by querying it. When ctx is done the order is cancelled and the fill seen so far
+// is returned with the context error.
+func (e *EntryImprover) await(ctx context.Context, fill Fill, quantity float64) (Fill, error) {
+	symbol, orderID := fill.Order.Symbol, fill.OrderID
+	deadline := time.NewTimer(e.timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(entryImprovementPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), entryImprovementCancelTimeout)
+			defer cancel()
+			if err := e.orders.CancelOrder(cancelCtx, symbol, orderID); err != nil {
+				return fill, fmt.Errorf("improved entry %s for %s interrupted and not cancelled: %v", orderID, symbol, err)
+			}
+			if final, err := e.orders.QueryOrder(cancelCtx, symbol, orderID); err == nil {
+				fill = final
+			}
+			return fill, ctx.Err()
+		case <-deadline.C:
+			// A cancel fails when the order filled in the meantime, which the query reports
+			cancelErr := e.orders.CancelOrder(ctx, symbol, orderID)
+			final, err := e.orders.QueryOrder(ctx, symbol, orderID)
+			if err != nil {
+				if cancelErr != nil {
+					return fill, fmt.Errorf("error cancelling improved entry %s for %s: %v", orderID, symbol, cancelErr)
+				}
+				return fill, fmt.Errorf("error querying cancelled entry %s for %s: %v", orderID, symbol, err)
+			}
+			if cancelErr != nil && final.Quantity < quantity*(1-tierPercentageEpsilon) {
+				// The unfilled order may still rest, so its remainder must not be market-filled
+				return final, fmt.Errorf("error cancelling improved entry %s for %s: %v", orderID, symbol, cancelErr)
+			}
+			return final, nil
+		case <-ticker.C:
+			current, err := e.orders.QueryOrder(ctx, symbol, orderID)
+			if err != nil {
+				continue
+			}
+			fill = current
+			if fill.Quantity >= quantity {
+				return fill, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeOrderBook rests every limit order unfilled and reports queries from its fills map
+type fakeOrderBook struct {
+	mu        sync.Mutex
+	submitted []Order
+	fills     map[string]Fill
+	cancelErr error
+	cancelled []string
+}
+
+func (b *fakeOrderBook) Submit(ctx context.Context, order Order) (Fill, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.submitted = append(b.submitted, order)
+	if order.Type == OrderTypeLimit {
+		return Fill{OrderID: "limit", Order: order}, nil
+	}
+	return Fill{OrderID: "market", Order: order, Price: order.Price, Quantity: order.Quantity}, nil
+}
+
+func (b *fakeOrderBook) QueryOrder(ctx context.Context, symbol, orderID string) (Fill, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.fills[orderID], nil
+}
+
+func (b *fakeOrderBook) CancelOrder(ctx context.Context, symbol, orderID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cancelled = append(b.cancelled, orderID)
+	return b.cancelErr
+}
+
+func TestEntryImproverReportsOrderFilledBeforeCancel(t *testing.T) {
+	limit := Order{Symbol: "BNBUSDT", Side: SideBuy, Quantity: 2, Price: 297, Type: OrderTypeLimit}
+	book := &fakeOrderBook{
+		fills:     map[string]Fill{"limit": {OrderID: "limit", Order: limit, Price: 297, Quantity: 2}},
+		cancelErr: errors.New("Unknown order sent."),
+	}
+	improver := DefaultConfig().NewEntryImprover(book, book)
+	improver.timeout = 10 * time.Millisecond
+
+	fills, err := improver.Enter(context.Background(), "BNBUSDT", SideBuy, 2, 300)
+	if err != nil {
+		t.Fatalf("Enter: %v", err)
+	}
+	if len(fills) != 1 || fills[0].Quantity != 2 || fills[0].Price != 297 {
+		t.Errorf("fills = %+v, want the limit filled at 297", fills)
+	}
+	if len(book.submitted) != 1 {
+		t.Errorf("submitted %+v, want no market order for a filled entry", book.submitted)
+	}
+}
+
+func TestEntryImproverMarketFillsRemainderAfterTimeout(t *testing.T) {
+	limit := Order{Symbol: "BNBUSDT", Side: SideBuy, Quantity: 2, Price: 297, Type: OrderTypeLimit}
+	book := &fakeOrderBook{fills: map[string]Fill{"limit": {OrderID: "limit", Order: limit, Price: 297, Quantity: 0.5}}}
+	improver := DefaultConfig().NewEntryImprover(book, book)
+	improver.timeout = 10 * time.Millisecond
+
+	fills, err := improver.Enter(context.Background(), "BNBUSDT", SideBuy, 2, 300)
+	if err != nil {
+		t.Fatalf("Enter: %v", err)
+	}
+	if len(fills) != 2 || fills[0].Quantity != 0.5 || fills[1].Quantity != 1.5 || fills[1].Order.Type != OrderTypeMarket {
+		t.Errorf("fills = %+v, want 0.5 at the limit and 1.5 at market", fills)
+	}
+}
+
+func TestEntryImproverReturnsPartialFillWhenInterrupted(t *testing.T) {
+	limit := Order{Symbol: "BNBUSDT", Side: SideBuy, Quantity: 2, Price: 297, Type: OrderTypeLimit}
+	book := &fakeOrderBook{fills: map[string]Fill{"limit": {OrderID: "limit", Order: limit, Price: 297, Quantity: 0.5}}}
+	improver := DefaultConfig().NewEntryImprover(book, book)
+	improver.timeout = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	fills, err := improver.Enter(ctx, "BNBUSDT", SideBuy, 2, 300)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want the context error", err)
+	}
+	if len(fills) != 1 || fills[0].Quantity != 0.5 {
+		t.Errorf("fills = %+v, want the 0.5 filled before the interruption", fills)
+	}
+	if len(book.cancelled) != 1 || len(book.submitted) != 1 {
+		t.Errorf("cancelled %v after submitting %+v, want the resting limit cancelled and nothing more sent", book.cancelled, book.submitted)
+	}
+}
+
+func TestHandleSignalTracksImprovedEntry(t *testing.T) {
+	book := &fakeOrderBook{fills: map[string]Fill{}}
+	bot := newTestBot(t, book)
+	bot.EnableEntryImprovement(book)
+	bot.improver.timeout = 10 * time.Millisecond
+
+	p, err := bot.HandleSignal(context.Background(), testSignal())
+	if err != nil {
+		t.Fatalf("HandleSignal: %v", err)
+	}
+	if p == nil || p.FilledQuantity != 1 || !p.FullyFilled() {
+		t.Fatalf("position = %+v, want 1 filled at market after the limit timed out", p)
+	}
+	if len(book.cancelled) != 1 {
+		t.Errorf("cancelled %v, want the improved limit cancelled", book.cancelled)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import "math"
+
+// ATR computes the average true range with Wilder's smoothing
+type ATR struct {
+	period    int
+	count     int
+	prevClose float64
+	sum       float64
+	value     float64
+}
+
+// NewATR creates an ATR over period bars; a period below 1 is treated as 1
+func NewATR(period int) *ATR {
+	if period < 1 {
+		period = 1
+	}
+	return &ATR{period: period}
+}
+
+// Update adds a bar's high, low and close
+func (a *ATR) Update(high, low, close float64) {
+	trueRange := high - low
+	if a.count > 0 {
+		trueRange = math.Max(trueRange, math.Max(math.Abs(high-a.prevClose), math.Abs(low-a.prevClose)))
+	}
+	a.prevClose = close
+	a.count++
+
+	switch {
+	case a.count < a.period:
+		a.sum += trueRange
+	case a.count == a.period:
+		a.value = (a.sum + trueRange) / float64(a.period)
+	default:
+		a.value = (a.value*float64(a.period-1) + trueRange) / float64(a.period)
+	}
+}
+
+// Ready reports whether period bars have been seen
+func (a *ATR) Ready() bool {
+	return a.count >= a.period
+}
+
+// Value returns the current ATR, 0 until period bars have been seen
+func (a *ATR) Value() float64 {
+	return a.value
+}
+
+// SMA computes the simple moving average of the last period values
+type SMA struct {
+	period int
+	window []float64
+	next   int
+	sum    float64
+}
+
+// NewSMA creates an SMA over period values
+func NewSMA(period int) *SMA {
+	return &SMA{period: period, window: make([]float64, 0, period)}
+}
+
+// Update adds a value, dropping the oldest once period values have been seen
+func (s *SMA) Update(value float64) {
+	if len(s.window) < s.period {
+		s.window = append(s.window, value)
+		s.sum += value
+		return
+	}
+	s.sum += value - s.window[s.next]
+	s.window[s.next] = value
+	s.next = (s.next + 1) % s.period
+}
+
+// Ready reports whether period values have been seen
+func (s *SMA) Ready() bool {
+	return s.period > 0 && len(s.window) == s.period
+}
+
+// Value returns the current average, 0 until period values have been seen
+func (s *SMA) Value() float64 {
+	if !s.Ready() {
+		return 0
+	}
+	return s.sum / float64(s.period)
+}
+
+// RSI computes the relative strength index with Wilder's smoothing
+type RSI struct {
+	period    int
+	count     int
+	prevClose float64
+	avgGain   float64
+	avgLoss   float64
+}
+
+// NewRSI creates an RSI over period price changes
+func NewRSI(period int) *RSI {
+	return &RSI{period: period}
+}
+
+// Update adds a close
+func (r *RSI) Update(close float64) {
+	r.count++
+	if r.count == 1 {
+		r.prevClose = close
+		return
+	}
+	change := close - r.prevClose
+	r.prevClose = close
+	gain, loss := math.Max(change, 0), math.Max(-change, 0)
+
+	// count-1 changes have been seen
+	switch changes := r.count - 1; {
+	case changes <= r.period:
+		r.avgGain += gain / float64(r.period)
+		r.avgLoss += loss / float64(r.period)
+	default:
+		r.avgGain = (r.avgGain*float64(r.period-1) + gain) / float64(r.period)
+		r.avgLoss = (r.avgLoss*float64(r.period-1) + loss) / float64(r.period)
+	}
+}
+
+// Ready reports whether period price changes have been seen
+func (r *RSI) Ready() bool {
+	return r.period > 0 && r.count > r.period
+}
+
+// Value returns the current RSI between 0 and 100, 50 until period changes have been seen
+func (r *RSI) Value() float64 {
+	if !r.Ready() {
+		return 50
+	}
+	if r.avgLoss == 0 {
+		if r.avgGain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+r.avgGain/r.avgLoss)
+}
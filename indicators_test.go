@@ -0,0 +1,32 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestATRWilderSmoothing(t *testing.T) {
+	atr := NewATR(2)
+	atr.Update(11, 9, 10) // true range 2
+	if atr.Ready() {
+		t.Fatal("ATR ready after one bar of two")
+	}
+	atr.Update(12, 10, 11) // true range 2
+	if !atr.Ready() || atr.Value() != 2 {
+		t.Fatalf("ATR after two bars = %f, want 2", atr.Value())
+	}
+	atr.Update(15, 11, 14) // true range 4, smoothed (2*1+4)/2
+	if atr.Value() != 3 {
+		t.Errorf("ATR after three bars = %f, want 3", atr.Value())
+	}
+}
+
+func TestNewATRGuardsNonPositivePeriod(t *testing.T) {
+	for _, period := range []int{0, -3} {
+		atr := NewATR(period)
+		atr.Update(11, 9, 10)
+		if math.IsNaN(atr.Value()) || math.IsInf(atr.Value(), 0) || atr.Value() != 2 {
+			t.Errorf("NewATR(%d) value = %f, want the single bar's true range", period, atr.Value())
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// neutralLeaderScore is the score of a leader without recorded trades
+const neutralLeaderScore = 0.5
+
+// leaderScorePrior is the number of neutral pseudo-trades blended into every score, so a leader
+// whose trades have decayed away drifts back to neutral
+const leaderScorePrior = 1
+
+// leaderScoreDecayPeriod is the time over which leader statistics are scaled by the decay once
+const leaderScoreDecayPeriod = 24 * time.Hour
+
+// leaderStats holds a leader's exponentially decayed trade statistics
+type leaderStats struct {
+	wins    float64
+	trades  float64
+	pnl     float64
+	updated time.Time
+}
+
+// decayTo scales the statistics by decay for each decay period elapsed since they were updated
+func (s *leaderStats) decayTo(now time.Time, decay float64) {
+	if !s.updated.IsZero() && now.After(s.updated) {
+		factor := math.Pow(decay, float64(now.Sub(s.updated))/float64(leaderScoreDecayPeriod))
+		s.wins *= factor
+		s.trades *= factor
+		s.pnl *= factor
+	}
+	s.updated = now
+}
+
+// score returns the decayed win rate blended with a neutral prior, halved while decayed P&L is
+// negative
+func (s *leaderStats) score() float64 {
+	score := (s.wins + leaderScorePrior*neutralLeaderScore) / (s.trades + leaderScorePrior)
+	if s.pnl < 0 {
+		score /= 2
+	}
+	return score
+}
+
+// LeaderScorer weights leaders by their decayed realized P&L and win rate
+type LeaderScorer struct {
+	mu    sync.Mutex
+	decay float64
+	stats map[string]*leaderStats
+	now   func() time.Time
+}
+
+// NewLeaderScorer creates a scorer for the configured leaders. A leader's statistics are
+// scaled by LeaderScoreDecay for every day that passes, so a decay of 1 never forgets.
+func (c *Config) NewLeaderScorer() *LeaderScorer {
+	s := &LeaderScorer{
+		decay: c.CopyTrading.LeaderScoreDecay,
+		stats: make(map[string]*leaderStats, len(c.CopyTrading.LeaderAddresses)),
+		now:   time.Now,
+	}
+	for _, address := range c.CopyTrading.LeaderAddresses {
+		s.stats[strings.ToLower(address)] = &leaderStats{}
+	}
+	return s
+}
+
+// reconfigure switches the scorer to the LeaderScoreDecay of c, decaying the statistics
+// kept so far at the old rate first
+func (s *LeaderScorer) reconfigure(c *Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for _, stats := range s.stats {
+		stats.decayTo(now, s.decay)
+	}
+	s.decay = c.CopyTrading.LeaderScoreDecay
+}
+
+// RecordLeaderTrade records the realized profit of a trade copied from address
+func (s *LeaderScorer) RecordLeaderTrade(address string, profit float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	address = strings.ToLower(address)
+	stats, ok := s.stats[address]
+	if !ok {
+		stats = &leaderStats{}
+		s.stats[address] = stats
+	}
+
+	stats.decayTo(s.now(), s.decay)
+	stats.trades++
+	stats.pnl += profit
+	if profit > 0 {
+		stats.wins++
+	}
+}
+
+// WeightFor returns the leader's score relative to the mean score of all active leaders,
+// so weights average 1 across leaders. Statistics are decayed to now first, so a leader that
+// stopped trading fades toward neutral. Unknown leaders are weighted as neutral.
+func (s *LeaderScorer) WeightFor(address string) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for _, stats := range s.stats {
+		stats.decayTo(now, s.decay)
+	}
+
+	score := neutralLeaderScore
+	if stats, ok := s.stats[strings.ToLower(address)]; ok {
+		score = stats.score()
+	}
+
+	var total float64
+	for _, stats := range s.stats {
+		total += stats.score()
+	}
+	if len(s.stats) == 0 || total == 0 {
+		return 1
+	}
+	return score / (total / float64(len(s.stats)))
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestLeaderScorer returns a scorer for leaders decaying by decay per day
+func newTestLeaderScorer(decay float64, leaders ...string) *LeaderScorer {
+	c := DefaultConfig()
+	c.CopyTrading.LeaderScoreDecay = decay
+	c.CopyTrading.LeaderAddresses = leaders
+	return c.NewLeaderScorer()
+}
+
+func TestLeaderScorerWeightsProfitableLeaderHigher(t *testing.T) {
+	scorer := newTestLeaderScorer(0.9, "0xGood", "0xBad")
+	for i := 0; i < 5; i++ {
+		scorer.RecordLeaderTrade("0xgood", 10)
+		scorer.RecordLeaderTrade("0xBAD", -10)
+	}
+	good, bad := scorer.WeightFor("0xGood"), scorer.WeightFor("0xBad")
+	if good <= 1 || bad >= 1 {
+		t.Errorf("weights good %f, bad %f; want good above 1 and bad below", good, bad)
+	}
+	if sum := good + bad; sum < 2-1e-9 || sum > 2+1e-9 {
+		t.Errorf("weights sum to %f, want them to average 1", sum)
+	}
+}
+
+func TestLeaderScorerDecaysStaleLeadersByTime(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	scorer := newTestLeaderScorer(0.5, "0xa", "0xb")
+	scorer.now = func() time.Time { return now }
+	for i := 0; i < 5; i++ {
+		scorer.RecordLeaderTrade("0xa", 10)
+	}
+	fresh := scorer.WeightFor("0xa")
+
+	// Neither leader trades for a month
+	now = now.Add(30 * 24 * time.Hour)
+	stale := scorer.WeightFor("0xa")
+	if stale >= fresh {
+		t.Errorf("weight after a month idle = %f, want below the fresh %f", stale, fresh)
+	}
+	if stale > 1.01 {
+		t.Errorf("weight after a month idle = %f, want close to neutral", stale)
+	}
+}
+
+func TestBotScaleSignalAppliesLeaderWeight(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	bot.config.Load().CopyTrading.LeaderAddresses = []string{"0xgood", "0xbad"}
+	bot.config.Load().FixedCapital.MaxCapitalPerTrade = 1e9
+	bot.config.Load().Trading.MaxOrderQuantity = 1e9
+	bot.config.Load().CopyTrading.MaxCopyNotional = 1e9
+	bot.leaders = bot.config.Load().NewLeaderScorer()
+	bot.leaders.RecordLeaderTrade("0xgood", 10)
+	bot.leaders.RecordLeaderTrade("0xbad", -10)
+
+	signal := CopySignal{LeaderAddress: "0xgood", Symbol: "BNBUSDT", Side: SideBuy, LeaderSize: 10, LeaderEquity: 1000}
+	good := bot.ScaleSignal(signal, 1000, 1)
+	signal.LeaderAddress = "0xbad"
+	bad := bot.ScaleSignal(signal, 1000, 1)
+	if good <= bad {
+		t.Errorf("good leader copy %f not larger than bad leader copy %f", good, bad)
+	}
+}
+
+func TestBotRecordsLeaderTradeOnClose(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	bot.leaders = newTestLeaderScorer(0.9, "0xa", "0xb")
+	bot.UpdatePrice("BNBUSDT", 110)
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 1, FilledQuantity: 1, LeaderAddress: "0xa"})
+	if _, err := bot.ClosePosition(t.Context(), "p", 1); err != nil {
+		t.Fatalf("ClosePosition: %v", err)
+	}
+	if weight := bot.LeaderScorer().WeightFor("0xa"); weight <= 1 {
+		t.Errorf("weight after a profitable close = %f, want above neutral", weight)
+	}
+}
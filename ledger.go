@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EquityBasis selects which equity the daily loss and drawdown checks measure
+type EquityBasis string
+
+const (
+	// EquityRealized measures cash balance only, ignoring open positions until they close
+	EquityRealized EquityBasis = "realized"
+	// EquityTotal measures cash balance plus the unrealized PnL of open positions
+	EquityTotal EquityBasis = "total"
+)
+
+// ParseEquityBasis parses an equity basis case-insensitively
+func ParseEquityBasis(s string) (EquityBasis, error) {
+	switch basis := EquityBasis(strings.ToLower(strings.TrimSpace(s))); basis {
+	case EquityRealized, EquityTotal:
+		return basis, nil
+	default:
+		return "", fmt.Errorf("unknown equity basis %q", s)
+	}
+}
+
+// PnLLedger separates the realized PnL of closed trades from the unrealized PnL of open
+// positions, which it reads from positions on demand
+type PnLLedger struct {
+	mu        sync.Mutex
+	cash      float64
+	realized  float64
+	positions func() []*Position
+	marks     map[string]float64
+}
+
+// NewPnLLedger creates a ledger starting from cash whose open positions are listed by positions
+func NewPnLLedger(cash float64, positions func() []*Position) *PnLLedger {
+	return &PnLLedger{
+		cash:      cash,
+		positions: positions,
+		marks:     make(map[string]float64),
+	}
+}
+
+// NewPnLLedger creates a ledger over the bot's open positions starting from TotalCapital
+func (b *Bot) NewPnLLedger() *PnLLedger {
+	return NewPnLLedger(b.config.Load().FixedCapital.TotalCapital, b.OpenPositions)
+}
+
+// RecordRealized books the realized profit or loss of a closed trade into the cash balance
+func (l *PnLLedger) RecordRealized(pnl float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.realized += pnl
+	l.cash += pnl
+}
+
+// RealizedPnL returns the profit or loss of all closed trades
+func (l *PnLLedger) RealizedPnL() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.realized
+}
+
+// CashBalance returns the starting cash plus realized PnL
+func (l *PnLLedger) CashBalance() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.cash
+}
+
+// MarkPrice records the latest price of symbol used by TotalEquity
+func (l *PnLLedger) MarkPrice(symbol string, price float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.marks[symbol] = price
+}
+
+// UnrealizedPnL returns the PnL of the open positions at currentPrices. Positions without a
+// price are valued at their entry and contribute nothing.
+func (l *PnLLedger) UnrealizedPnL(currentPrices map[string]float64) float64 {
+	if l.positions == nil {
+		return 0
+	}
+	var pnl float64
+	for _, p := range l.positions() {
+		price, ok := currentPrices[p.Symbol]
+		if !ok || p.FilledQuantity == 0 {
+			continue
+		}
+		move := price - p.EntryPrice
+		if !isLong(p.Side) {
+			move = -move
+		}
+		pnl += move * p.FilledQuantity
+	}
+	return pnl
+}
+
+// TotalEquity returns the cash balance plus the unrealized PnL at the marked prices
+func (l *PnLLedger) TotalEquity() float64 {
+	l.mu.Lock()
+	cash := l.cash
+	marks := make(map[string]float64, len(l.marks))
+	for symbol, price := range l.marks {
+		marks[symbol] = price
+	}
+	l.mu.Unlock()
+	return cash + l.UnrealizedPnL(marks)
+}
+
+// Equity returns the equity measured under basis
+func (l *PnLLedger) Equity(basis EquityBasis) float64 {
+	if basis == EquityRealized {
+		return l.CashBalance()
+	}
+	return l.TotalEquity()
+}
+
+// RiskEquity returns the equity the risk gates measure under RiskEquityBasis: the realized
+// capital alone, or with the unrealized PnL of the open positions at their last prices
+func (b *Bot) RiskEquity() float64 {
+	if b.config.Load().RiskManagement.RiskEquityBasis == EquityRealized {
+		return b.capital.Equity()
+	}
+	return b.Equity()
+}
+
+// CheckEquityGates records the equity under RiskEquityBasis with the drawdown monitor and
+// reports whether the daily loss and drawdown limits still allow trading, with the reason when
+// they do not
+func (b *Bot) CheckEquityGates(now time.Time) (bool, string) {
+	equity := b.RiskEquity()
+	b.drawdown.Record(equity)
+	if !b.daily.CanTrade(now, equity) {
+		return false, fmt.Sprintf("daily loss limit reached, equity %f from %f at the session start", equity, b.daily.StartingEquity())
+	}
+	if b.config.Load().RiskManagement.DrawdownMonitoringEnabled && !b.drawdown.WithinLimit() {
+		return false, fmt.Sprintf("drawdown %.2f%% exceeds the limit", b.drawdown.CurrentDrawdown()*100)
+	}
+	return true, ""
+}
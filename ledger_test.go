@@ -0,0 +1,80 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPnLLedgerSeparatesRealizedAndUnrealized(t *testing.T) {
+	positions := []*Position{
+		{ID: "a", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, FilledQuantity: 2},
+		{ID: "b", Symbol: "ETHUSDT", Side: SideShort, EntryPrice: 200, FilledQuantity: 1},
+	}
+	ledger := NewPnLLedger(1000, func() []*Position { return positions })
+	ledger.RecordRealized(-50)
+	ledger.MarkPrice("BNBUSDT", 110)
+	ledger.MarkPrice("ETHUSDT", 190)
+
+	if got := ledger.RealizedPnL(); got != -50 {
+		t.Errorf("RealizedPnL = %f, want -50", got)
+	}
+	if got := ledger.Equity(EquityRealized); got != 950 {
+		t.Errorf("realized equity = %f, want 950", got)
+	}
+	// long gains 20, short gains 10
+	if got := ledger.Equity(EquityTotal); math.Abs(got-980) > 1e-9 {
+		t.Errorf("total equity = %f, want 980", got)
+	}
+}
+
+func TestParseEquityBasis(t *testing.T) {
+	if basis, err := ParseEquityBasis(" Total "); err != nil || basis != EquityTotal {
+		t.Errorf("ParseEquityBasis(Total) = %q, %v", basis, err)
+	}
+	if _, err := ParseEquityBasis("mark"); err == nil {
+		t.Error("expected an error for an unknown basis")
+	}
+}
+
+func TestAllowEntryStopsAtDailyLossLimit(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	config := bot.config.Load()
+	config.RiskManagement.RiskEquityBasis = EquityRealized
+	config.RiskManagement.MaxDailyLossPercentage = 0.05
+	config.RiskManagement.DrawdownMonitoringEnabled = false
+	capital := bot.capital.Equity()
+
+	// The first check anchors the session's starting equity
+	if !bot.AllowEntry(testSignal()) {
+		t.Fatal("AllowEntry refused a signal before any loss")
+	}
+	bot.capital.RecordRealizedPnL(-capital * 0.04)
+	if !bot.AllowEntry(testSignal()) {
+		t.Error("AllowEntry refused a signal within the daily loss limit")
+	}
+	bot.capital.RecordRealizedPnL(-capital * 0.02)
+	if bot.AllowEntry(testSignal()) {
+		t.Error("AllowEntry admitted a signal past the daily loss limit")
+	}
+}
+
+func TestAllowEntryStopsAtDrawdownLimit(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	config := bot.config.Load()
+	config.RiskManagement.RiskEquityBasis = EquityTotal
+	config.RiskManagement.MaxDailyLossPercentage = 1
+	config.RiskManagement.DrawdownMonitoringEnabled = true
+	config.RiskManagement.MaxDrawdownPercentage = 0.10
+	bot.TrackPosition(&Position{ID: "p", Symbol: "ETHUSDT", Side: SideLong, EntryPrice: 100, Quantity: 5, FilledQuantity: 5})
+	capital := bot.capital.Equity()
+
+	// An open profit raises the peak the drawdown is measured from
+	bot.UpdatePrice("ETHUSDT", 100+capital*0.1/5)
+	if !bot.AllowEntry(testSignal()) {
+		t.Fatal("AllowEntry refused a signal at the equity peak")
+	}
+	bot.UpdatePrice("ETHUSDT", 100-capital*0.02/5)
+	if bot.AllowEntry(testSignal()) {
+		t.Error("AllowEntry admitted a signal with total equity past the drawdown limit")
+	}
+}
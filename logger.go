@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogLevel is a logging severity
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+// logLevelNames maps log levels to their configuration names
+var logLevelNames = map[LogLevel]string{
+	LogDebug: "DEBUG",
+	LogInfo:  "INFO",
+	LogWarn:  "WARN",
+	LogError: "ERROR",
+}
+
+// ParseLogLevel parses a log level case-insensitively
+func ParseLogLevel(s string) (LogLevel, error) {
+	name := strings.ToUpper(strings.TrimSpace(s))
+	for level, levelName := range logLevelNames {
+		if levelName == name {
+			return level, nil
+		}
+	}
+	return LogInfo, fmt.Errorf("unknown log level %q, expected DEBUG, INFO, WARN or ERROR", s)
+}
+
+// String returns the configuration name of the log level
+func (level LogLevel) String() string {
+	if name, ok := logLevelNames[level]; ok {
+		return name
+	}
+	return fmt.Sprintf("LogLevel(%d)", int(level))
+}
+
+// Logger writes leveled log lines to the console and a size-rotated log file
+type Logger struct {
+	mu      sync.Mutex
+	level   LogLevel
+	console io.Writer
+	file    *rotatingFile
+}
+
+// NewLogger creates a logger from the logging configuration
+func NewLogger(cfg LoggingConfig) (*Logger, error) {
+	level, err := ParseLogLevel(cfg.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := &Logger{level: level}
+	if cfg.ConsoleLogging {
+		logger.console = os.Stdout
+	}
+	if cfg.FileLogging {
+		if err := os.MkdirAll(filepath.Dir(cfg.LogFilePath), 0o755); err != nil {
+			return nil, fmt.Errorf("error creating log directory: %v", err)
+		}
+		file, err := newRotatingFile(cfg.LogFilePath, int64(cfg.MaxLogFileSize)*1024*1024, cfg.MaxBackupFiles)
+		if err != nil {
+			return nil, err
+		}
+		logger.file = file
+	}
+
+	return logger, nil
+}
+
+// Debug logs a DEBUG message
+func (l *Logger) Debug(format string, args ...interface{}) {
+	l.log(LogDebug, format, args...)
+}
+
+// Info logs an INFO message
+func (l *Logger) Info(format string, args ...interface{}) {
+	l.log(LogInfo, format, args...)
+}
+
+// Warn logs a WARN message
+func (l *Logger) Warn(format string, args ...interface{}) {
+	l.log(LogWarn, format, args...)
+}
+
+// Error logs an ERROR message
+func (l *Logger) Error(format string, args ...interface{}) {
+	l.log(LogError, format, args...)
+}
+
+func (l *Logger) log(level LogLevel, format string, args ...interface{}) {
+	if level < l.level {
+		return
+	}
+
+	line := fmt.Sprintf("%s [%s] %s\n", time.Now().Format("2006/01/02 15:04:05"), level, fmt.Sprintf(format, args...))
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.console != nil {
+		_, _ = io.WriteString(l.console, line)
+	}
+	if l.file != nil {
+		if err := l.file.write([]byte(line)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing log file: %v\n", err)
+		}
+	}
+}
+
+// Close closes the log file
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	return l.file.close()
+}
+
+// rotatingFile is a log file rotated once it exceeds maxSize bytes, keeping maxBackups
+// numbered backups (path.1 is the most recent)
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+func newRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening log file %s: %v", r.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("error reading log file %s: %v", r.path, err)
+	}
+	r.file = file
+	r.size = info.Size()
+	return nil
+}
+
+func (r *rotatingFile) write(p []byte) error {
+	if r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return err
+}
+
+func (r *rotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+
+	if r.maxBackups == 0 {
+		if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return r.open()
+	}
+
+	_ = os.Remove(fmt.Sprintf("%s.%d", r.path, r.maxBackups))
+	for i := r.maxBackups - 1; i >= 1; i-- {
+		_ = os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+	}
+	if err := os.Rename(r.path, r.path+".1"); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) close() error {
+	return r.file.Close()
+}
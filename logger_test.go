@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newFileLogger creates a file-only logger at level writing under a temporary directory
+func newFileLogger(t *testing.T, level string) (*Logger, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "logs", "bot.log")
+	logger, err := NewLogger(LoggingConfig{LogLevel: level, LogFilePath: path, FileLogging: true, MaxLogFileSize: 1, MaxBackupFiles: 1})
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	t.Cleanup(func() { logger.Close() })
+	return logger, path
+}
+
+func TestNewLoggerFiltersBelowLevel(t *testing.T) {
+	logger, path := newFileLogger(t, "warn")
+	logger.Debug("debug line")
+	logger.Info("info line")
+	logger.Warn("warn line")
+	logger.Error("error line")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	got := string(data)
+	for _, dropped := range []string{"debug line", "info line"} {
+		if strings.Contains(got, dropped) {
+			t.Errorf("log contains %q below WARN", dropped)
+		}
+	}
+	for _, kept := range []string{"[WARN] warn line", "[ERROR] error line"} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("log missing %q:\n%s", kept, got)
+		}
+	}
+}
+
+func TestNewLoggerRejectsUnknownLevel(t *testing.T) {
+	if _, err := NewLogger(LoggingConfig{LogLevel: "verbose"}); err == nil {
+		t.Fatal("NewLogger accepted an unknown level")
+	}
+}
+
+func TestNewLoggerRotatesPastMaxSize(t *testing.T) {
+	logger, path := newFileLogger(t, "INFO")
+	line := strings.Repeat("x", 64*1024)
+	for i := 0; i < 20; i++ {
+		logger.Info("%s", line)
+	}
+
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Fatalf("no backup after passing 1 MB: %v", err)
+	}
+	if _, err := os.Stat(path + ".2"); !os.IsNotExist(err) {
+		t.Errorf("backup beyond MaxBackupFiles kept: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Size() > 1024*1024 {
+		t.Errorf("log size = %d, want at most 1 MB after rotation", info.Size())
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    LogLevel
+		wantErr bool
+	}{
+		{"DEBUG", LogDebug, false},
+		{"info", LogInfo, false},
+		{" Warn ", LogWarn, false},
+		{"error", LogError, false},
+		{"", LogInfo, true},
+		{"WARNING", LogInfo, true},
+		{"trace", LogInfo, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLogLevel(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLogLevel(%q) = %v, %v; want %v, error %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateRejectsUnknownLogLevel(t *testing.T) {
+	c := DefaultConfig()
+	c.Trading.TestnetEnabled = true
+	c.Logging.LogLevel = "loud"
+	if err := c.Validate(); err == nil || !strings.Contains(err.Error(), "unknown log level") {
+		t.Fatalf("Validate = %v, want the unknown log level reported", err)
+	}
+}
@@ -19,23 +19,8 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/joho/godotenv"
 )
 
-// Config holds bot configuration
-type Config struct {
-	BSCNodeURL         string
-	MasterWalletAddr   string
-	FollowerPrivateKey string
-	CopyPercentage     float64
-	TokenAddresses     []string
-	RouterAddress      string
-	WBNBAddress        string
-	GasPrice           *big.Int
-	GasLimit           uint64
-	Testnet            bool
-}
-
 // CopyTradingBot handles copy trading on BSC
 type CopyTradingBot struct {
 	config          *Config
@@ -131,22 +116,66 @@ const erc20ABI = `[
 ]`
 
 func main() {
-	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using environment variables")
+	if len(os.Args) > 1 && os.Args[1] == validateCommand {
+		os.Exit(runValidate(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	for _, warning := range config.Warnings() {
+		log.Printf("⚠️  %s", warning)
 	}
+	if !config.BSC.Enabled() && !config.CopyTrading.Enabled {
+		log.Fatalf("Nothing to run: set MASTER_WALLET_ADDRESS to copy swaps or COPY_TRADING_ENABLED to copy exchange trades")
+	}
+
+	// Setup graceful shutdown
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	config := loadConfig()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 
+	go func() {
+		<-sigChan
+		log.Println("Shutting down...")
+		cancel()
+	}()
+
+	traderDone := make(chan error, 1)
+	if config.CopyTrading.Enabled {
+		live := NewLiveConfig(config, LoadConfig)
+		go func() {
+			err := runTrader(ctx, live)
+			if err != nil {
+				cancel()
+			}
+			traderDone <- err
+		}()
+	}
+	if config.BSC.Enabled() {
+		runSwapCopier(ctx, config)
+	}
+	if config.CopyTrading.Enabled {
+		if err := <-traderDone; err != nil {
+			log.Fatalf("Copy trader stopped: %v", err)
+		}
+	}
+}
+
+// runSwapCopier copies the master wallet's PancakeSwap swaps until ctx is done
+func runSwapCopier(ctx context.Context, config *Config) {
 	// Initialize Ethereum client
-	client, err := ethclient.Dial(config.BSCNodeURL)
+	client, err := ethclient.Dial(config.BSC.ActiveNodeURL())
 	if err != nil {
 		log.Fatalf("Failed to connect to BSC node: %v", err)
 	}
 	defer client.Close()
 
 	// Load follower private key
-	followerKey, err := crypto.HexToECDSA(strings.TrimPrefix(config.FollowerPrivateKey, "0x"))
+	followerKey, err := crypto.HexToECDSA(trimHexPrefix(config.BSC.FollowerPrivateKey))
 	if err != nil {
 		log.Fatalf("Invalid private key: %v", err)
 	}
@@ -162,64 +191,24 @@ func main() {
 	}
 
 	// Get current block number
-	header, err := client.HeaderByNumber(context.Background(), nil)
+	header, err := client.HeaderByNumber(ctx, nil)
 	if err != nil {
 		log.Fatalf("Failed to get latest block: %v", err)
 	}
 	bot.lastBlockNumber = header.Number.Uint64()
 
 	log.Println("🚀 BSC Copy Trading Bot Started")
-	log.Printf("📡 Connected to BSC: %s", config.BSCNodeURL)
-	log.Printf("👤 Master Wallet: %s", config.MasterWalletAddr)
+	log.Printf("📡 Connected to BSC: %s", config.BSC.ActiveNodeURL())
+	log.Printf("👤 Master Wallet: %s", config.BSC.MasterWalletAddress)
 	log.Printf("🤖 Follower Wallet: %s", followerAddress.Hex())
-	log.Printf("📊 Copy Percentage: %.2f%%", config.CopyPercentage)
-	log.Printf("🪙 Monitoring tokens: %v", config.TokenAddresses)
-	log.Printf("🏪 Router: %s", config.RouterAddress)
-
-	// Setup graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-
-	go func() {
-		<-sigChan
-		log.Println("Shutting down...")
-		cancel()
-	}()
+	log.Printf("📊 Copy Percentage: %.2f%%", config.BSC.CopyPercentage)
+	log.Printf("🪙 Monitoring tokens: %v", config.BSC.TokenAddresses)
+	log.Printf("🏪 Router: %s", config.BSC.RouterAddress)
 
 	// Start monitoring
 	bot.startMonitoring(ctx)
 }
 
-func loadConfig() *Config {
-	testnet := getEnv("TESTNET", "false") == "true"
-
-	var bscNodeURL string
-	if testnet {
-		bscNodeURL = getEnv("BSC_TESTNET_URL", "https://data-seed-prebsc-1-s1.binance.org:8545")
-	} else {
-		bscNodeURL = getEnv("BSC_NODE_URL", "https://bsc-dataseed1.binance.org")
-	}
-
-	gasPriceGwei := parseFloat(getEnv("GAS_PRICE_GWEI", "5"))
-	gasPrice := new(big.Int).Mul(big.NewInt(int64(gasPriceGwei)), big.NewInt(1000000000)) // Convert to Wei
-
-	return &Config{
-		BSCNodeURL:         bscNodeURL,
-		MasterWalletAddr:   getEnv("MASTER_WALLET_ADDRESS", ""),
-		FollowerPrivateKey: getEnv("FOLLOWER_PRIVATE_KEY", ""),
-		CopyPercentage:     parseFloat(getEnv("COPY_PERCENTAGE", "100")),
-		TokenAddresses:     parseAddresses(getEnv("TOKEN_ADDRESSES", "")),
-		RouterAddress:      getEnv("ROUTER_ADDRESS", "0x10ED43C718714eb63d5aA57B78B54704E256024E"), // PancakeSwap V2 Router
-		WBNBAddress:        getEnv("WBNB_ADDRESS", "0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c"),
-		GasPrice:           gasPrice,
-		GasLimit:           parseUint64(getEnv("GAS_LIMIT", "300000")),
-		Testnet:            testnet,
-	}
-}
-
 func (bot *CopyTradingBot) startMonitoring(ctx context.Context) {
 	ticker := time.NewTicker(3 * time.Second) // Check every 3 seconds
 	defer ticker.Stop()
@@ -273,8 +262,8 @@ func (bot *CopyTradingBot) scanBlock(ctx context.Context, blockNumber uint64) {
 		return
 	}
 
-	masterAddr := common.HexToAddress(bot.config.MasterWalletAddr)
-	routerAddr := common.HexToAddress(bot.config.RouterAddress)
+	masterAddr := common.HexToAddress(bot.config.BSC.MasterWalletAddress)
+	routerAddr := common.HexToAddress(bot.config.BSC.RouterAddress)
 
 	// Check each transaction in the block
 	for _, tx := range block.Transactions() {
@@ -323,14 +312,14 @@ func (bot *CopyTradingBot) processSwapTransaction(ctx context.Context, tx *types
 	}
 
 	// Find Swap events
-	for _, log := range receipt.Logs {
-		if len(log.Topics) == 0 {
+	for _, eventLog := range receipt.Logs {
+		if len(eventLog.Topics) == 0 {
 			continue
 		}
 
 		// Check if this is a Swap event (topic[0] is the event signature)
 		swapEventSig := crypto.Keccak256Hash([]byte("Swap(address,uint256,uint256,uint256,uint256,address)"))
-		if log.Topics[0] != swapEventSig {
+		if eventLog.Topics[0] != swapEventSig {
 			continue
 		}
 
@@ -344,7 +333,7 @@ func (bot *CopyTradingBot) processSwapTransaction(ctx context.Context, tx *types
 			To         common.Address
 		}
 
-		err = routerABIParsed.UnpackIntoInterface(&swapEvent, "Swap", log.Data)
+		err = routerABIParsed.UnpackIntoInterface(&swapEvent, "Swap", eventLog.Data)
 		if err != nil {
 			log.Printf("Error unpacking swap event: %v", err)
 			continue
@@ -418,7 +407,7 @@ func (bot *CopyTradingBot) executeETHForTokensSwap(ctx context.Context, values [
 	_ = values[3].(*big.Int)       // deadline - not used
 
 	// Get master wallet balance to determine swap amount
-	masterAddr := common.HexToAddress(bot.config.MasterWalletAddr)
+	masterAddr := common.HexToAddress(bot.config.BSC.MasterWalletAddress)
 	balance, err := bot.client.BalanceAt(ctx, masterAddr, nil)
 	if err != nil {
 		log.Printf("Error getting master balance: %v", err)
@@ -426,7 +415,7 @@ func (bot *CopyTradingBot) executeETHForTokensSwap(ctx context.Context, values [
 	}
 
 	// Calculate copy amount
-	amountIn := new(big.Int).Mul(balance, big.NewInt(int64(bot.config.CopyPercentage)))
+	amountIn := new(big.Int).Mul(balance, big.NewInt(int64(bot.config.BSC.CopyPercentage)))
 	amountIn.Div(amountIn, big.NewInt(100))
 
 	if amountIn.Cmp(big.NewInt(0)) == 0 {
@@ -447,7 +436,7 @@ func (bot *CopyTradingBot) executeETHForTokensSwap(ctx context.Context, values [
 	}
 
 	// Calculate minimum output with 5% slippage tolerance
-	copyAmountOutMin := new(big.Int).Mul(amountOutMin, big.NewInt(int64(bot.config.CopyPercentage)))
+	copyAmountOutMin := new(big.Int).Mul(amountOutMin, big.NewInt(int64(bot.config.BSC.CopyPercentage)))
 	copyAmountOutMin.Div(copyAmountOutMin, big.NewInt(100))
 	copyAmountOutMin.Mul(copyAmountOutMin, big.NewInt(95)) // 5% slippage
 	copyAmountOutMin.Div(copyAmountOutMin, big.NewInt(100))
@@ -478,7 +467,7 @@ func (bot *CopyTradingBot) executeTokensForETHSwap(ctx context.Context, values [
 	_ = values[4].(*big.Int)       // deadline - not used
 
 	// Calculate copy amount
-	copyAmountIn := new(big.Int).Mul(amountIn, big.NewInt(int64(bot.config.CopyPercentage)))
+	copyAmountIn := new(big.Int).Mul(amountIn, big.NewInt(int64(bot.config.BSC.CopyPercentage)))
 	copyAmountIn.Div(copyAmountIn, big.NewInt(100))
 
 	// Check token approval and balance
@@ -489,7 +478,7 @@ func (bot *CopyTradingBot) executeTokensForETHSwap(ctx context.Context, values [
 	}
 
 	// Calculate minimum output
-	copyAmountOutMin := new(big.Int).Mul(amountOutMin, big.NewInt(int64(bot.config.CopyPercentage)))
+	copyAmountOutMin := new(big.Int).Mul(amountOutMin, big.NewInt(int64(bot.config.BSC.CopyPercentage)))
 	copyAmountOutMin.Div(copyAmountOutMin, big.NewInt(100))
 	copyAmountOutMin.Mul(copyAmountOutMin, big.NewInt(95))
 	copyAmountOutMin.Div(copyAmountOutMin, big.NewInt(100))
@@ -517,7 +506,7 @@ func (bot *CopyTradingBot) executeTokensForTokensSwap(ctx context.Context, value
 	_ = values[4].(*big.Int)       // deadline - not used
 
 	// Calculate copy amount
-	copyAmountIn := new(big.Int).Mul(amountIn, big.NewInt(int64(bot.config.CopyPercentage)))
+	copyAmountIn := new(big.Int).Mul(amountIn, big.NewInt(int64(bot.config.BSC.CopyPercentage)))
 	copyAmountIn.Div(copyAmountIn, big.NewInt(100))
 
 	// Check token approval and balance
@@ -528,7 +517,7 @@ func (bot *CopyTradingBot) executeTokensForTokensSwap(ctx context.Context, value
 	}
 
 	// Calculate minimum output
-	copyAmountOutMin := new(big.Int).Mul(amountOutMin, big.NewInt(int64(bot.config.CopyPercentage)))
+	copyAmountOutMin := new(big.Int).Mul(amountOutMin, big.NewInt(int64(bot.config.BSC.CopyPercentage)))
 	copyAmountOutMin.Div(copyAmountOutMin, big.NewInt(100))
 	copyAmountOutMin.Mul(copyAmountOutMin, big.NewInt(95))
 	copyAmountOutMin.Div(copyAmountOutMin, big.NewInt(100))
@@ -549,7 +538,7 @@ func (bot *CopyTradingBot) checkAndApproveToken(ctx context.Context, tokenAddr c
 		return fmt.Errorf("error parsing ERC20 ABI: %v", err)
 	}
 
-	routerAddr := common.HexToAddress(bot.config.RouterAddress)
+	routerAddr := common.HexToAddress(bot.config.BSC.RouterAddress)
 
 	// Check current allowance
 	allowanceData, err := erc20ABIParsed.Pack("allowance", bot.followerAddress, routerAddr)
@@ -597,7 +586,7 @@ func (bot *CopyTradingBot) checkAndApproveToken(ctx context.Context, tokenAddr c
 	}
 
 	auth.Nonce = big.NewInt(int64(nonce))
-	auth.GasPrice = bot.config.GasPrice
+	auth.GasPrice = bot.config.BSC.GasPrice()
 	auth.GasLimit = 50000
 	auth.Value = big.NewInt(0)
 
@@ -623,7 +612,7 @@ func (bot *CopyTradingBot) checkAndApproveToken(ctx context.Context, tokenAddr c
 
 func (bot *CopyTradingBot) callSwapExactETHForTokens(ctx context.Context, amountIn, amountOutMin *big.Int, path []common.Address, to common.Address, deadline *big.Int) {
 	routerABIParsed, _ := abi.JSON(strings.NewReader(routerABI))
-	routerAddr := common.HexToAddress(bot.config.RouterAddress)
+	routerAddr := common.HexToAddress(bot.config.BSC.RouterAddress)
 
 	swapData, err := routerABIParsed.Pack("swapExactETHForTokens", amountOutMin, path, to, deadline)
 	if err != nil {
@@ -636,7 +625,7 @@ func (bot *CopyTradingBot) callSwapExactETHForTokens(ctx context.Context, amount
 
 func (bot *CopyTradingBot) callSwapExactTokensForETH(ctx context.Context, amountIn, amountOutMin *big.Int, path []common.Address, to common.Address, deadline *big.Int) {
 	routerABIParsed, _ := abi.JSON(strings.NewReader(routerABI))
-	routerAddr := common.HexToAddress(bot.config.RouterAddress)
+	routerAddr := common.HexToAddress(bot.config.BSC.RouterAddress)
 
 	swapData, err := routerABIParsed.Pack("swapExactTokensForETH", amountIn, amountOutMin, path, to, deadline)
 	if err != nil {
@@ -649,7 +638,7 @@ func (bot *CopyTradingBot) callSwapExactTokensForETH(ctx context.Context, amount
 
 func (bot *CopyTradingBot) callSwapExactTokensForTokens(ctx context.Context, amountIn, amountOutMin *big.Int, path []common.Address, to common.Address, deadline *big.Int) {
 	routerABIParsed, _ := abi.JSON(strings.NewReader(routerABI))
-	routerAddr := common.HexToAddress(bot.config.RouterAddress)
+	routerAddr := common.HexToAddress(bot.config.BSC.RouterAddress)
 
 	swapData, err := routerABIParsed.Pack("swapExactTokensForTokens", amountIn, amountOutMin, path, to, deadline)
 	if err != nil {
@@ -673,7 +662,7 @@ func (bot *CopyTradingBot) sendSwapTransaction(ctx context.Context, to common.Ad
 		return
 	}
 
-	tx := types.NewTransaction(nonce, to, value, bot.config.GasLimit, bot.config.GasPrice, data)
+	tx := types.NewTransaction(nonce, to, value, uint64(bot.config.BSC.GasLimit), bot.config.BSC.GasPrice(), data)
 
 	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), bot.followerKey)
 	if err != nil {
@@ -694,50 +683,3 @@ func weiToEther(wei *big.Int) string {
 	ether := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e18))
 	return ether.Text('f', 8)
 }
-
-// Helper functions
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
-}
-
-func parseAddresses(s string) []string {
-	if s == "" {
-		return []string{}
-	}
-	addrs := strings.Split(s, ",")
-	var result []string
-	for _, addr := range addrs {
-		addr = strings.TrimSpace(addr)
-		if addr != "" {
-			result = append(result, addr)
-		}
-	}
-	return result
-}
-
-func parseFloat(s string) float64 {
-	if s == "" {
-		return 0
-	}
-	var f float64
-	_, err := fmt.Sscanf(s, "%f", &f)
-	if err != nil {
-		return 0
-	}
-	return f
-}
-
-func parseUint64(s string) uint64 {
-	if s == "" {
-		return 0
-	}
-	var u uint64
-	_, err := fmt.Sscanf(s, "%d", &u)
-	if err != nil {
-		return 0
-	}
-	return u
-}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// ManagePosition manages an open position at price, whether or not the bot is paused. The
+// whole position is closed once its stop is crossed, the stop moving through the ExitManager's
+// break-even and trailing phases; otherwise a crossed soft loss threshold is alerted once and
+// the next profit tier reached is closed.
+func (b *Bot) ManagePosition(ctx context.Context, id string, price float64) error {
+	b.mu.Lock()
+	p, ok := b.positions[id]
+	if !ok {
+		b.mu.Unlock()
+		return fmt.Errorf("no open position %s", id)
+	}
+	if p.FilledQuantity <= 0 {
+		b.mu.Unlock()
+		return nil
+	}
+	exits, ok := b.exits[id]
+	if !ok {
+		exits = b.config.Load().NewExitManager(p)
+		b.exits[id] = exits
+	}
+	stop, crossed := exits.Update(price)
+	symbol, side, leader := p.Symbol, p.Side, p.LeaderAddress
+	snapshot := *p
+	b.mu.Unlock()
+
+	if !crossed {
+		b.softLoss.Check(&snapshot, price)
+		// Keep the exchange exits in step with a stop moved to break-even or trailing
+		b.placeExits(ctx, id)
+		_, err := b.ExecuteNextTier(ctx, id, price)
+		return err
+	}
+
+	b.closeMu.Lock()
+	defer b.closeMu.Unlock()
+	fill, err := b.submitClose(ctx, id, math.Inf(1))
+	if err != nil {
+		return fmt.Errorf("error closing position %s at its %s stop %f: %v", id, exits.Phase(), stop, err)
+	}
+	if fill.Quantity > 0 {
+		b.reentry.RecordStopOut(reEntrySetupID(leader, symbol, side))
+		b.logf("Stopped out %f of position %s (%s %s) at %f, %s stop %f", fill.Quantity, id, symbol, side, price, exits.Phase(), stop)
+	}
+	return nil
+}
+
+// SyncEntryFills adds what partially filled entry orders filled since they were last checked,
+// as reported by orders
+func (b *Bot) SyncEntryFills(ctx context.Context, orders OrderQuerier) error {
+	var errs []error
+	for _, p := range b.OpenPositions() {
+		b.mu.Lock()
+		pending := !p.FullyFilled() && p.EntryOrderID != ""
+		symbol, orderID := p.Symbol, p.EntryOrderID
+		b.mu.Unlock()
+		if !pending {
+			continue
+		}
+
+		fill, err := orders.QueryOrder(ctx, symbol, orderID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error checking entry of position %s: %v", p.ID, err))
+			continue
+		}
+
+		b.mu.Lock()
+		// The order reports its cumulative fill; apply what was added since the last check at
+		// the price that moves the average to the reported one
+		added := fill.Quantity - p.FilledQuantity
+		if added > p.Quantity*tierPercentageEpsilon {
+			price := (fill.Price*fill.Quantity - p.EntryPrice*p.FilledQuantity) / added
+			p.ApplyFill(Fill{OrderID: orderID, Price: price, Quantity: added, Time: fill.Time})
+			b.volume.Record(price * added)
+			b.recordFees(Fill{Order: fill.Order, Price: price, Quantity: added})
+			// Stops derive from the entry price, which just moved
+			delete(b.exits, p.ID)
+			b.logf("Entry of position %s (%s) filled %f more, %f of %f at %f", p.ID, symbol, added, p.FilledQuantity, p.Quantity, p.EntryPrice)
+		}
+		b.mu.Unlock()
+		if added > 0 {
+			b.placeExits(ctx, p.ID)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ChaseEntries re-prices resting limit entries toward the last price when ChaseEnabled, within
+// MaxChaseDistance of the price they were first placed at, and cancels those the market ran
+// beyond. Only entries with nothing filled are chased: a replacement order would restart the
+// cumulative fill SyncEntryFills reads. An entry that filled while being cancelled keeps its
+// fill and is no longer chased.
+func (b *Bot) ChaseEntries(ctx context.Context, orders OrderManager) error {
+	config := b.config.Load()
+	if !config.Trading.ChaseEnabled || config.Trading.OrderType != OrderTypeLimit {
+		return nil
+	}
+
+	var errs []error
+	for _, p := range b.OpenPositions() {
+		b.mu.Lock()
+		price, priced := b.lastPrices[p.Symbol]
+		resting := p.FilledQuantity == 0 && p.EntryOrderID != ""
+		chaser, ok := b.chasers[p.ID]
+		if !resting {
+			delete(b.chasers, p.ID)
+		} else if !ok {
+			chaser = NewEntryChaser(p.EntryPrice, config.Trading.MaxChaseDistance, p.Side)
+			b.chasers[p.ID] = chaser
+		}
+		symbol, side, quantity, orderID, limit := p.Symbol, p.Side, p.Quantity, p.EntryOrderID, p.EntryPrice
+		b.mu.Unlock()
+		if !resting || !priced {
+			continue
+		}
+
+		chased, status := chaser.Update(price)
+		if status == ChaseFilled || (status == ChaseResting && chased == limit) {
+			continue
+		}
+		if err := orders.CancelOrder(ctx, symbol, orderID); err != nil {
+			errs = append(errs, fmt.Errorf("error cancelling entry order of position %s to chase it: %v", p.ID, err))
+			continue
+		}
+		filled, err := orders.QueryOrder(ctx, symbol, orderID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error checking entry of position %s: %v", p.ID, err))
+			continue
+		}
+		if filled.Quantity > 0 {
+			b.mu.Lock()
+			p.ApplyFill(Fill{OrderID: orderID, Price: filled.Price, Quantity: filled.Quantity, Time: filled.Time})
+			p.Quantity = p.FilledQuantity
+			b.volume.Record(filled.Price * filled.Quantity)
+			b.recordFees(filled)
+			delete(b.chasers, p.ID)
+			b.mu.Unlock()
+			b.logf("Entry of position %s (%s) filled %f at %f before it was chased, remainder cancelled", p.ID, symbol, filled.Quantity, filled.Price)
+			b.placeExits(ctx, p.ID)
+			continue
+		}
+
+		if status == ChaseAbandoned {
+			b.mu.Lock()
+			delete(b.positions, p.ID)
+			delete(b.chasers, p.ID)
+			saveErr := b.saveState()
+			b.mu.Unlock()
+			if saveErr != nil {
+				errs = append(errs, saveErr)
+			}
+			b.logf("Abandoned entry of position %s (%s %s): price %f ran beyond the chase bound from %f", p.ID, symbol, side, price, chaser.SignalPrice())
+			continue
+		}
+
+		orderSide := SideSell
+		if isBuy(side) {
+			orderSide = SideBuy
+		}
+		order := config.EntryOrder(symbol, orderSide, quantity, chased)
+		order.Price = chased
+		fill, err := b.submit(ctx, order)
+		b.mu.Lock()
+		if err != nil {
+			// The cancelled entry left nothing on the book
+			delete(b.positions, p.ID)
+			delete(b.chasers, p.ID)
+		} else {
+			p.EntryOrderID = fill.OrderID
+			p.EntryPrice = chased
+			p.ApplyFill(fill)
+			b.volume.Record(fill.Price * fill.Quantity)
+		}
+		saveErr := b.saveState()
+		b.mu.Unlock()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error re-placing chased entry of position %s: %v", p.ID, err), saveErr)
+			continue
+		}
+		if saveErr != nil {
+			errs = append(errs, saveErr)
+		}
+		b.logf("Chased entry of position %s (%s %s) from %f to %f", p.ID, symbol, side, limit, chased)
+		if fill.Quantity > 0 {
+			b.placeExits(ctx, p.ID)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// HandleQueuedSignals copies the signals queued at the position limit while slots are free
+func (b *Bot) HandleQueuedSignals(ctx context.Context) error {
+	var errs []error
+	for {
+		signal, ok := b.limiter.Next(len(b.OpenPositions()))
+		if !ok {
+			return errors.Join(errs...)
+		}
+		if _, err := b.HandleSignal(ctx, signal); err != nil {
+			errs = append(errs, err)
+		}
+	}
+}
+
+// TradedSymbols returns the configured pairs and the symbols of open positions, sorted
+func (b *Bot) TradedSymbols() []string {
+	seen := make(map[string]bool)
+	var symbols []string
+	add := func(symbol string) {
+		if !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+	for _, symbol := range b.config.Load().Pairs() {
+		add(symbol)
+	}
+	for _, p := range b.OpenPositions() {
+		add(p.Symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// RefreshPrices fetches the prices of symbols concurrently on Trading.Workers workers and
+// records them for the next management pass, which then runs over the symbols in a fixed order.
+// Each refreshed price is also the latest close seen by the entry filters.
+func (b *Bot) RefreshPrices(ctx context.Context, symbols []string, fetch PriceFunc) error {
+	b.mu.Lock()
+	filters := b.filters
+	b.mu.Unlock()
+	results := EvaluateSymbols(ctx, symbols, b.config.Load().Trading.Workers, func(ctx context.Context, symbol string) (*Order, error) {
+		price, err := fetch(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+		b.UpdatePrice(symbol, price)
+		UpdateCopyFilters(filters, symbol, price)
+		return nil, nil
+	})
+
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("error fetching %s price: %v", result.Symbol, result.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Manage runs one management pass at now over the open positions at their last prices:
+// exchange exits that executed are booked, resting entries are synced, chased and timed out
+// through orders, which may be nil when fills are simulated, positions past their max hold time
+// are closed, stops and tiers are managed and queued signals are copied into free slots
+func (b *Bot) Manage(ctx context.Context, now time.Time, orders OrderManager) error {
+	var errs []error
+	if err := b.SyncProtectiveStops(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := b.SyncOCOExits(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if orders != nil {
+		if err := b.SyncEntryFills(ctx, orders); err != nil {
+			errs = append(errs, err)
+		}
+		if err := b.ChaseEntries(ctx, orders); err != nil {
+			errs = append(errs, err)
+		}
+		if err := b.CancelUnfilledEntries(ctx, orders); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if _, err := b.CloseTimedOutPositions(ctx, now); err != nil {
+		errs = append(errs, err)
+	}
+	for _, p := range b.OpenPositions() {
+		price, ok := b.LastPrice(p.Symbol)
+		if !ok {
+			continue
+		}
+		if err := b.ManagePosition(ctx, p.ID, price); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := b.HandleQueuedSignals(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
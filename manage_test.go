@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func testSignal() CopySignal {
+	return CopySignal{LeaderAddress: "0xleader", Symbol: "bnbusdt", Side: SideBuy, LeaderSize: 10, LeaderEquity: 10000, Price: 300}
+}
+
+func TestHandleSignalOpensScaledPosition(t *testing.T) {
+	executor := &fakeExecutor{fillRatio: 1}
+	bot := newTestBot(t, executor)
+
+	p, err := bot.HandleSignal(context.Background(), testSignal())
+	if err != nil {
+		t.Fatalf("HandleSignal: %v", err)
+	}
+	// 10 leader units scaled by 1000 of our equity to the leader's 10000
+	if p == nil || p.Symbol != "BNBUSDT" || p.Side != SideLong || p.FilledQuantity != 1 || p.EntryPrice != 300 {
+		t.Fatalf("position = %+v, want a long of 1 BNBUSDT at 300", p)
+	}
+	if len(executor.orders) != 1 || executor.orders[0].Side != SideBuy {
+		t.Errorf("orders = %+v, want one buy", executor.orders)
+	}
+	if open := bot.OpenPositions(); len(open) != 1 || open[0].LeaderAddress != "0xleader" {
+		t.Errorf("open positions = %+v, want the copied position", open)
+	}
+}
+
+func TestHandleSignalSkippedWhilePaused(t *testing.T) {
+	executor := &fakeExecutor{fillRatio: 1}
+	bot := newTestBot(t, executor)
+	bot.Pause()
+
+	p, err := bot.HandleSignal(context.Background(), testSignal())
+	if err != nil || p != nil {
+		t.Fatalf("HandleSignal = %+v, %v; want the signal skipped", p, err)
+	}
+	if len(executor.orders) != 0 {
+		t.Errorf("orders = %+v, want none while paused", executor.orders)
+	}
+}
+
+func TestHandleSignalRejectsUnknownSide(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	signal := testSignal()
+	signal.Side = "hold"
+	if _, err := bot.HandleSignal(context.Background(), signal); err == nil {
+		t.Fatal("expected an unknown side to be rejected")
+	}
+}
+
+func TestManagePositionClosesAtStop(t *testing.T) {
+	executor := &fakeExecutor{fillRatio: 1}
+	bot := newTestBot(t, executor)
+	p, err := bot.HandleSignal(context.Background(), testSignal())
+	if err != nil || p == nil {
+		t.Fatalf("HandleSignal = %+v, %v", p, err)
+	}
+
+	// Above the 3% stop nothing closes
+	if err := bot.ManagePosition(context.Background(), p.ID, 295); err != nil {
+		t.Fatalf("ManagePosition: %v", err)
+	}
+	if len(bot.OpenPositions()) != 1 {
+		t.Fatal("position closed above its stop")
+	}
+
+	bot.UpdatePrice("BNBUSDT", 290)
+	if err := bot.ManagePosition(context.Background(), p.ID, 290); err != nil {
+		t.Fatalf("ManagePosition: %v", err)
+	}
+	if open := bot.OpenPositions(); len(open) != 0 {
+		t.Errorf("open positions = %+v, want the position stopped out", open)
+	}
+	if last := executor.orders[len(executor.orders)-1]; last.Side != SideSell || last.Quantity != 1 {
+		t.Errorf("close order = %+v, want a sell of 1", last)
+	}
+}
+
+func newChasingTestBot(t *testing.T, book *fakeOrderBook) *Bot {
+	t.Helper()
+	bot := newTestBot(t, book)
+	config := bot.config.Load()
+	config.Trading.OrderType = OrderTypeLimit
+	config.Trading.ChaseEnabled = true
+	config.Trading.MaxChaseDistance = 0.01
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 2, EntryOrderID: "limit"})
+	return bot
+}
+
+func TestChaseEntriesRepricesWithinBoundThenAbandons(t *testing.T) {
+	book := &fakeOrderBook{}
+	bot := newChasingTestBot(t, book)
+
+	bot.UpdatePrice("BNBUSDT", 100.5)
+	if err := bot.ChaseEntries(context.Background(), book); err != nil {
+		t.Fatalf("ChaseEntries: %v", err)
+	}
+	if len(book.submitted) != 1 || book.submitted[0].Price != 100.5 || book.submitted[0].Type != OrderTypeLimit {
+		t.Fatalf("submitted %+v, want the entry re-placed at 100.5", book.submitted)
+	}
+	if positions := bot.OpenPositions(); len(positions) != 1 || positions[0].EntryPrice != 100.5 {
+		t.Fatalf("positions = %+v, want the entry anchored at 100.5", positions)
+	}
+
+	// 101.5 is beyond 1% from the original 100
+	bot.UpdatePrice("BNBUSDT", 101.5)
+	if err := bot.ChaseEntries(context.Background(), book); err != nil {
+		t.Fatalf("ChaseEntries: %v", err)
+	}
+	if len(book.cancelled) != 2 || len(book.submitted) != 1 {
+		t.Errorf("cancelled %v and submitted %+v, want the entry cancelled without a new order", book.cancelled, book.submitted)
+	}
+	if positions := bot.OpenPositions(); len(positions) != 0 {
+		t.Errorf("positions = %+v, want the abandoned entry dropped", positions)
+	}
+}
+
+func TestChaseEntriesKeepsFillFoundOnCancel(t *testing.T) {
+	book := &fakeOrderBook{fills: map[string]Fill{"limit": {OrderID: "limit", Price: 100, Quantity: 0.5}}}
+	bot := newChasingTestBot(t, book)
+
+	bot.UpdatePrice("BNBUSDT", 100.5)
+	if err := bot.ChaseEntries(context.Background(), book); err != nil {
+		t.Fatalf("ChaseEntries: %v", err)
+	}
+	if len(book.submitted) != 0 {
+		t.Errorf("submitted %+v, want no replacement for a filled entry", book.submitted)
+	}
+	if positions := bot.OpenPositions(); len(positions) != 1 || positions[0].FilledQuantity != 0.5 || positions[0].Quantity != 0.5 {
+		t.Errorf("positions = %+v, want the 0.5 filled kept and the remainder dropped", positions)
+	}
+}
+
+func TestChaseEntriesDisabled(t *testing.T) {
+	book := &fakeOrderBook{}
+	bot := newChasingTestBot(t, book)
+	bot.config.Load().Trading.ChaseEnabled = false
+
+	bot.UpdatePrice("BNBUSDT", 101.5)
+	if err := bot.ChaseEntries(context.Background(), book); err != nil {
+		t.Fatalf("ChaseEntries: %v", err)
+	}
+	if len(book.cancelled) != 0 || len(bot.OpenPositions()) != 1 {
+		t.Errorf("cancelled %v, want entries left resting with chasing disabled", book.cancelled)
+	}
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// MarketDataMode selects how market data is received
+type MarketDataMode string
+
+const (
+	// MarketDataWebSocket streams prices over the exchange WebSocket, polling REST while disconnected
+	MarketDataWebSocket MarketDataMode = "ws"
+	// MarketDataREST polls prices over REST every refresh interval
+	MarketDataREST MarketDataMode = "rest"
+)
+
+// ParseMarketDataMode parses a market data mode case-insensitively
+func ParseMarketDataMode(s string) (MarketDataMode, error) {
+	switch mode := MarketDataMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case MarketDataWebSocket, MarketDataREST:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown market data mode %q", s)
+	}
+}
+
+const (
+	binanceStreamURL        = "wss://stream.binance.com:9443"
+	binanceTestnetStreamURL = "wss://stream.testnet.binance.vision"
+	// Kline interval subscribed to alongside the ticker
+	marketDataKlineInterval = "1m"
+	// A connection silent for this long is treated as dead
+	marketDataReadTimeout = time.Minute
+	// Bounds of the reconnect backoff, during which prices are polled over REST
+	marketDataMinBackoff = time.Second
+	marketDataMaxBackoff = time.Minute
+)
+
+// MarketUpdate is a price update for a symbol, carrying the kline when it came from a kline stream
+type MarketUpdate struct {
+	Symbol string
+	Price  float64
+	Time   time.Time
+	Candle *Candle
+	// Whether the kline is closed
+	Final bool
+}
+
+// PriceFunc fetches the latest price of a symbol over REST
+type PriceFunc func(ctx context.Context, symbol string) (float64, error)
+
+// MarketDataStream delivers market data for a set of symbols over a channel, from the exchange
+// WebSocket with automatic reconnects or from REST polling
+type MarketDataStream struct {
+	mode         MarketDataMode
+	baseURL      string
+	symbols      []string
+	pollInterval time.Duration
+	fetchPrice   PriceFunc
+	updates      chan MarketUpdate
+	connected    atomic.Bool
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+
+	mu sync.Mutex
+	// Latest price of each symbol received by Track and when it arrived
+	latest map[string]streamedPrice
+}
+
+// streamedPrice is a price received from the stream
+type streamedPrice struct {
+	price      float64
+	receivedAt time.Time
+}
+
+// NewMarketDataStream creates a market data stream for the configured pairs using MarketDataMode
+// and polling every refresh interval. fetchPrice is required: it is used in REST mode and while
+// the WebSocket is disconnected.
+func (c *Config) NewMarketDataStream(fetchPrice PriceFunc) (*MarketDataStream, error) {
+	if fetchPrice == nil {
+		return nil, fmt.Errorf("market data stream needs a REST price function")
+	}
+	baseURL := binanceStreamURL
+	if c.Trading.TestnetEnabled {
+		baseURL = binanceTestnetStreamURL
+	}
+	return &MarketDataStream{
+		mode:         c.MarketDataMode,
+		baseURL:      baseURL,
+		symbols:      c.Pairs(),
+		pollInterval: time.Duration(c.RefreshInterval) * time.Second,
+		fetchPrice:   fetchPrice,
+		updates:      make(chan MarketUpdate, 256),
+		minBackoff:   marketDataMinBackoff,
+		maxBackoff:   marketDataMaxBackoff,
+		latest:       make(map[string]streamedPrice),
+	}, nil
+}
+
+// Track consumes Updates until the stream ends, keeping each symbol's latest price for Price.
+// Run it instead of reading Updates directly.
+func (s *MarketDataStream) Track() {
+	for update := range s.updates {
+		s.mu.Lock()
+		s.latest[update.Symbol] = streamedPrice{price: update.Price, receivedAt: time.Now()}
+		s.mu.Unlock()
+	}
+}
+
+// Price returns the latest price of symbol received by Track within two poll intervals, and
+// fetches it over REST otherwise, such as for symbols outside the stream; it is a PriceFunc
+func (s *MarketDataStream) Price(ctx context.Context, symbol string) (float64, error) {
+	s.mu.Lock()
+	latest, ok := s.latest[symbol]
+	s.mu.Unlock()
+	if ok && time.Since(latest.receivedAt) <= 2*s.pollInterval {
+		return latest.price, nil
+	}
+	return s.fetchPrice(ctx, symbol)
+}
+
+// Updates returns the channel market updates are delivered on; it is closed when Run returns
+func (s *MarketDataStream) Updates() <-chan MarketUpdate {
+	return s.updates
+}
+
+// Run delivers market data until ctx is done
+func (s *MarketDataStream) Run(ctx context.Context) {
+	defer close(s.updates)
+
+	if s.mode == MarketDataREST {
+		s.poll(ctx, time.Time{})
+		return
+	}
+
+	backoff := s.minBackoff
+	for ctx.Err() == nil {
+		started := time.Now()
+		connected, err := s.stream(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			// Only a connection that stayed up resets the backoff, so a connection dropped
+			// right after the handshake is not retried in a tight loop
+			if time.Since(started) >= s.maxBackoff {
+				backoff = s.minBackoff
+			}
+			log.Printf("Market data stream disconnected, polling REST for %s before reconnecting: %v", backoff, err)
+		} else {
+			log.Printf("Market data stream unavailable, polling REST for %s: %v", backoff, err)
+		}
+		s.poll(ctx, time.Now().Add(backoff))
+		backoff = min(backoff*2, s.maxBackoff)
+	}
+}
+
+// streamURL returns the combined stream URL subscribing to the ticker and kline of every symbol
+func (s *MarketDataStream) streamURL() string {
+	streams := make([]string, 0, 2*len(s.symbols))
+	for _, symbol := range s.symbols {
+		symbol = strings.ToLower(symbol)
+		streams = append(streams, symbol+"@ticker", symbol+"@kline_"+marketDataKlineInterval)
+	}
+	return s.baseURL + "/stream?streams=" + strings.Join(streams, "/")
+}
+
+// stream reads the WebSocket until it fails, reporting whether it connected
+func (s *MarketDataStream) stream(ctx context.Context) (bool, error) {
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, s.streamURL(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	s.connected.Store(true)
+	defer s.connected.Store(false)
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(marketDataReadTimeout))
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return true, err
+		}
+		update, ok, err := parseStreamMessage(message)
+		if err != nil {
+			log.Printf("Error parsing market data message: %v", err)
+			continue
+		}
+		if ok && !s.send(ctx, update) {
+			return true, ctx.Err()
+		}
+	}
+}
+
+// Connected reports whether the WebSocket is currently connected
+func (s *MarketDataStream) Connected() bool {
+	return s.connected.Load()
+}
+
+// HealthCheck fails while a WebSocket stream is disconnected or any of its symbols has no price
+// received by Track within two poll intervals; REST polling is always ready
+func (s *MarketDataStream) HealthCheck(ctx context.Context) error {
+	if s.mode != MarketDataWebSocket {
+		return nil
+	}
+	if !s.Connected() {
+		return fmt.Errorf("market data websocket disconnected")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, symbol := range s.symbols {
+		latest, ok := s.latest[strings.ToUpper(symbol)]
+		if !ok {
+			return fmt.Errorf("no %s price received yet", symbol)
+		}
+		if age := time.Since(latest.receivedAt); age > 2*s.pollInterval {
+			return fmt.Errorf("%s price is stale, last received %s ago", symbol, age.Round(time.Second))
+		}
+	}
+	return nil
+}
+
+// streamEvent is the subset of Binance ticker and kline events the stream uses
+type streamEvent struct {
+	Type      string `json:"e"`
+	EventTime int64  `json:"E"`
+	Symbol    string `json:"s"`
+	LastPrice string `json:"c"`
+	Kline     struct {
+		OpenTime int64  `json:"t"`
+		Open     string `json:"o"`
+		High     string `json:"h"`
+		Low      string `json:"l"`
+		Close    string `json:"c"`
+		Volume   string `json:"v"`
+		Final    bool   `json:"x"`
+	} `json:"k"`
+}
+
+// parseStreamMessage decodes a combined stream message, reporting false for events it ignores
+func parseStreamMessage(message []byte) (MarketUpdate, bool, error) {
+	var envelope struct {
+		Data streamEvent `json:"data"`
+	}
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		return MarketUpdate{}, false, err
+	}
+	event := envelope.Data
+	update := MarketUpdate{Symbol: event.Symbol, Time: time.UnixMilli(event.EventTime).UTC()}
+
+	switch event.Type {
+	case "24hrTicker":
+		price, err := strconv.ParseFloat(event.LastPrice, 64)
+		if err != nil {
+			return MarketUpdate{}, false, fmt.Errorf("invalid ticker price %q", event.LastPrice)
+		}
+		update.Price = price
+	case "kline":
+		candle := Candle{OpenTime: time.UnixMilli(event.Kline.OpenTime).UTC()}
+		fields := []struct {
+			value *float64
+			raw   string
+		}{
+			{&candle.Open, event.Kline.Open},
+			{&candle.High, event.Kline.High},
+			{&candle.Low, event.Kline.Low},
+			{&candle.Close, event.Kline.Close},
+			{&candle.Volume, event.Kline.Volume},
+		}
+		for _, field := range fields {
+			parsed, err := strconv.ParseFloat(field.raw, 64)
+			if err != nil {
+				return MarketUpdate{}, false, fmt.Errorf("invalid kline value %q", field.raw)
+			}
+			*field.value = parsed
+		}
+		update.Price = candle.Close
+		update.Candle = &candle
+		update.Final = event.Kline.Final
+	default:
+		return MarketUpdate{}, false, nil
+	}
+	return update, true, nil
+}
+
+// poll fetches every symbol's price each poll interval until ctx is done or until, when set, is reached
+func (s *MarketDataStream) poll(ctx context.Context, until time.Time) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	var deadline <-chan time.Time
+	if !until.IsZero() {
+		timer := time.NewTimer(time.Until(until))
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	for {
+		for _, symbol := range s.symbols {
+			price, err := s.fetchPrice(ctx, symbol)
+			if err != nil {
+				log.Printf("Error fetching %s price: %v", symbol, err)
+				continue
+			}
+			if !s.send(ctx, MarketUpdate{Symbol: symbol, Price: price, Time: time.Now().UTC()}) {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// send delivers an update, returning false when ctx is done first
+func (s *MarketDataStream) send(ctx context.Context, update MarketUpdate) bool {
+	select {
+	case s.updates <- update:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
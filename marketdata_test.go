@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// marketDataConfig returns a config streaming pairs in mode and polling hourly
+func marketDataConfig(mode MarketDataMode, pairs ...string) *Config {
+	c := DefaultConfig()
+	c.MarketDataMode = mode
+	c.Trading.TradingPairs = pairs
+	c.RefreshInterval = 3600
+	return c
+}
+
+func TestNewMarketDataStreamRequiresPriceFunc(t *testing.T) {
+	for _, mode := range []MarketDataMode{MarketDataREST, MarketDataWebSocket} {
+		if _, err := marketDataConfig(mode, "BNBUSDT").NewMarketDataStream(nil); err == nil {
+			t.Errorf("%s mode accepted a nil price function", mode)
+		}
+	}
+}
+
+func TestMarketDataStreamBacksOffAfterDroppedConnection(t *testing.T) {
+	var connections atomic.Int32
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		connections.Add(1)
+		// Drop the connection right after the handshake
+		conn.Close()
+	}))
+	defer server.Close()
+
+	var polls atomic.Int32
+	fetchPrice := func(ctx context.Context, symbol string) (float64, error) {
+		polls.Add(1)
+		return 600, nil
+	}
+	stream, err := marketDataConfig(MarketDataWebSocket, "BNBUSDT").NewMarketDataStream(fetchPrice)
+	if err != nil {
+		t.Fatalf("NewMarketDataStream: %v", err)
+	}
+	stream.baseURL = "ws" + strings.TrimPrefix(server.URL, "http")
+	stream.minBackoff = 50 * time.Millisecond
+	stream.maxBackoff = time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 400*time.Millisecond)
+	defer cancel()
+	go stream.Run(ctx)
+	for update := range stream.Updates() {
+		if update.Symbol != "BNBUSDT" || update.Price != 600 {
+			t.Errorf("unexpected update %+v", update)
+		}
+	}
+
+	// Backoffs of 50, 100 and 200ms allow about four connections in 400ms
+	if got := connections.Load(); got < 2 || got > 5 {
+		t.Errorf("connections = %d, want between 2 and 5 with backoff", got)
+	}
+	if polls.Load() == 0 {
+		t.Error("prices were not polled over REST while disconnected")
+	}
+}
+
+func TestMarketDataStreamPollsInRESTMode(t *testing.T) {
+	stream, err := marketDataConfig(MarketDataREST, "BNBUSDT", "ETHUSDT").NewMarketDataStream(func(ctx context.Context, symbol string) (float64, error) {
+		return 1, nil
+	})
+	if err != nil {
+		t.Fatalf("NewMarketDataStream: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go stream.Run(ctx)
+
+	seen := make(map[string]bool)
+	for update := range stream.Updates() {
+		seen[update.Symbol] = true
+		if len(seen) == 2 {
+			cancel()
+		}
+	}
+	if !seen["BNBUSDT"] || !seen["ETHUSDT"] {
+		t.Errorf("polled symbols = %v, want both pairs", seen)
+	}
+}
+
+func TestParseStreamMessage(t *testing.T) {
+	ticker := `{"stream":"bnbusdt@ticker","data":{"e":"24hrTicker","E":1700000000000,"s":"BNBUSDT","c":"612.5"}}`
+	update, ok, err := parseStreamMessage([]byte(ticker))
+	if err != nil || !ok || update.Symbol != "BNBUSDT" || update.Price != 612.5 || update.Candle != nil {
+		t.Errorf("ticker = %+v, %t, %v", update, ok, err)
+	}
+
+	kline := `{"data":{"e":"kline","E":1700000000000,"s":"BNBUSDT","k":{"t":1699999980000,"o":"610","h":"613","l":"609","c":"612","v":"42","x":true}}}`
+	update, ok, err = parseStreamMessage([]byte(kline))
+	if err != nil || !ok || update.Candle == nil || update.Candle.High != 613 || !update.Final || update.Price != 612 {
+		t.Errorf("kline = %+v, %t, %v", update, ok, err)
+	}
+
+	if _, ok, err := parseStreamMessage([]byte(`{"data":{"e":"trade"}}`)); ok || err != nil {
+		t.Errorf("ignored event = %t, %v", ok, err)
+	}
+	if _, _, err := parseStreamMessage([]byte(`{"data":{"e":"24hrTicker","c":"x"}}`)); err == nil {
+		t.Error("invalid ticker price was accepted")
+	}
+}
+
+func TestMarketDataStreamPriceFallsBackToREST(t *testing.T) {
+	var fetches atomic.Int32
+	stream, err := marketDataConfig(MarketDataREST, "BNBUSDT").NewMarketDataStream(func(ctx context.Context, symbol string) (float64, error) {
+		fetches.Add(1)
+		return 500, nil
+	})
+	if err != nil {
+		t.Fatalf("NewMarketDataStream: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		stream.Track()
+		close(done)
+	}()
+	stream.updates <- MarketUpdate{Symbol: "BNBUSDT", Price: 612.5}
+	close(stream.updates)
+	<-done
+
+	if price, err := stream.Price(context.Background(), "BNBUSDT"); err != nil || price != 612.5 || fetches.Load() != 0 {
+		t.Errorf("Price(BNBUSDT) = %f, %v after %d fetches; want the streamed 612.5 without fetching", price, err, fetches.Load())
+	}
+	if price, err := stream.Price(context.Background(), "ETHUSDT"); err != nil || price != 500 || fetches.Load() != 1 {
+		t.Errorf("Price(ETHUSDT) = %f, %v after %d fetches; want 500 fetched over REST", price, err, fetches.Load())
+	}
+
+	// A streamed price older than two poll intervals is refetched
+	stream.pollInterval = time.Nanosecond
+	if price, _ := stream.Price(context.Background(), "BNBUSDT"); price != 500 {
+		t.Errorf("Price of a stale BNBUSDT = %f, want 500 fetched over REST", price)
+	}
+}
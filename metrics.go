@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics exposes the bot's trading and risk state to Prometheus
+type Metrics struct {
+	registry          *prometheus.Registry
+	trades            *prometheus.CounterVec
+	orders            *prometheus.CounterVec
+	openPositions     prometheus.Gauge
+	realizedPnL       prometheus.Gauge
+	winRate           prometheus.Gauge
+	consecutiveLosses prometheus.Gauge
+	drawdown          prometheus.Gauge
+	equity            prometheus.Gauge
+}
+
+// NewMetrics creates the bot metrics in their own registry
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		trades: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "bot_trades_total",
+			Help: "Closed trades by outcome.",
+		}, []string{"outcome"}),
+		orders: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "bot_orders_total",
+			Help: "Submitted orders by side and result.",
+		}, []string{"side", "result"}),
+		openPositions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "bot_open_positions",
+			Help: "Number of open positions.",
+		}),
+		realizedPnL: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "bot_realized_pnl",
+			Help: "Cumulative realized profit and loss in quote currency.",
+		}),
+		winRate: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "bot_win_rate",
+			Help: "Fraction of closed trades that were profitable.",
+		}),
+		consecutiveLosses: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "bot_consecutive_losses",
+			Help: "Current run of consecutive losing trades.",
+		}),
+		drawdown: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "bot_drawdown_ratio",
+			Help: "Current drawdown from peak equity as a fraction.",
+		}),
+		equity: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "bot_equity",
+			Help: "Current account equity in quote currency.",
+		}),
+	}
+	m.registry.MustRegister(m.trades, m.orders, m.openPositions, m.realizedPnL, m.winRate,
+		m.consecutiveLosses, m.drawdown, m.equity)
+	return m
+}
+
+// RecordTrade counts a closed trade and adds its profit to the realized P&L
+func (m *Metrics) RecordTrade(profit float64) {
+	outcome := "breakeven"
+	switch {
+	case profit > 0:
+		outcome = "win"
+	case profit < 0:
+		outcome = "loss"
+	}
+	m.trades.WithLabelValues(outcome).Inc()
+	m.realizedPnL.Add(profit)
+}
+
+// RecordOrder counts a submitted order and whether it succeeded
+func (m *Metrics) RecordOrder(side string, err error) {
+	result := "filled"
+	if err != nil {
+		result = "error"
+	}
+	m.orders.WithLabelValues(side, result).Inc()
+}
+
+// SetOpenPositions sets the number of open positions
+func (m *Metrics) SetOpenPositions(n int) {
+	m.openPositions.Set(float64(n))
+}
+
+// SetWinRate sets the win rate
+func (m *Metrics) SetWinRate(winRate float64) {
+	m.winRate.Set(winRate)
+}
+
+// SetConsecutiveLosses sets the consecutive loss count
+func (m *Metrics) SetConsecutiveLosses(n int) {
+	m.consecutiveLosses.Set(float64(n))
+}
+
+// SetDrawdown sets the current drawdown fraction
+func (m *Metrics) SetDrawdown(drawdown float64) {
+	m.drawdown.Set(drawdown)
+}
+
+// SetEquity sets the current equity
+func (m *Metrics) SetEquity(equity float64) {
+	m.equity.Set(equity)
+}
+
+// Handler returns the /metrics HTTP handler
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Serve serves /metrics on port until ctx is done
+func (m *Metrics) Serve(ctx context.Context, port int) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", port),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("error serving metrics on port %d: %v", port, err)
+	}
+	return nil
+}
+
+// EnableMetrics has the bot count its orders and closed trades in m and report its state
+// through ReportMetrics
+func (b *Bot) EnableMetrics(m *Metrics) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.metrics = m
+}
+
+// ReportMetrics records the current equity with the drawdown monitor and sets the gauges of
+// the enabled metrics: open positions, the win rate and worst consecutive loss run across
+// pairs, drawdown and equity
+func (b *Bot) ReportMetrics() {
+	equity := b.Equity()
+	b.drawdown.Record(equity)
+
+	b.mu.Lock()
+	m := b.metrics
+	open := len(b.positions)
+	var wins, trades, losses int
+	for _, pair := range b.pairs {
+		wins += pair.outcomes.Wins()
+		trades += pair.outcomes.TotalTrades()
+		if n := pair.losses.ConsecutiveLosses(); n > losses {
+			losses = n
+		}
+	}
+	b.mu.Unlock()
+	if m == nil {
+		return
+	}
+
+	m.SetOpenPositions(open)
+	if trades > 0 {
+		m.SetWinRate(float64(wins) / float64(trades))
+	}
+	m.SetConsecutiveLosses(losses)
+	m.SetDrawdown(b.drawdown.CurrentDrawdown())
+	m.SetEquity(equity)
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBotRecordsOrdersTradesAndGauges(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	metrics := NewMetrics()
+	bot.EnableMetrics(metrics)
+
+	if _, err := bot.HandleSignal(context.Background(), testSignal()); err != nil {
+		t.Fatalf("HandleSignal: %v", err)
+	}
+	bot.ReportMetrics()
+	if body := scrapeMetrics(t, metrics); !strings.Contains(body, `bot_orders_total{result="filled",side="buy"} 1`) || !strings.Contains(body, "bot_open_positions 1") {
+		t.Fatalf("metrics after the entry do not count its order and open position:\n%s", body)
+	}
+
+	p := bot.OpenPositions()[0]
+	if _, err := bot.ClosePosition(context.Background(), p.ID, p.FilledQuantity); err != nil {
+		t.Fatalf("ClosePosition: %v", err)
+	}
+	bot.ReportMetrics()
+	body := scrapeMetrics(t, metrics)
+	for _, want := range []string{`bot_orders_total{result="filled",side="sell"} 1`, "bot_open_positions 0", "bot_equity "} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics after the close are missing %q:\n%s", want, body)
+		}
+	}
+	if !strings.Contains(body, `bot_trades_total{outcome="loss"} 1`) && !strings.Contains(body, `bot_trades_total{outcome="win"} 1`) {
+		t.Errorf("metrics after the close do not count the trade:\n%s", body)
+	}
+}
+
+// scrapeMetrics returns the exposition of metrics
+func scrapeMetrics(t *testing.T, metrics *Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WebhookFormat selects the JSON body shape posted to the webhook
+type WebhookFormat string
+
+const (
+	// WebhookGeneric posts {"message": ..., "timestamp": ...}
+	WebhookGeneric WebhookFormat = "generic"
+	// WebhookSlack posts {"text": ...}
+	WebhookSlack WebhookFormat = "slack"
+	// WebhookDiscord posts {"content": ...}
+	WebhookDiscord WebhookFormat = "discord"
+)
+
+// ParseWebhookFormat parses a webhook format case-insensitively
+func ParseWebhookFormat(s string) (WebhookFormat, error) {
+	switch format := WebhookFormat(strings.ToLower(strings.TrimSpace(s))); format {
+	case WebhookGeneric, WebhookSlack, WebhookDiscord:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown webhook format %q", s)
+	}
+}
+
+const (
+	// notifierMaxRetries is the number of retries after the first failed delivery
+	notifierMaxRetries = 3
+	// notifierInitialBackoff is the delay before the first retry, doubled on each retry
+	notifierInitialBackoff = 500 * time.Millisecond
+)
+
+// webhookPayload is the JSON body posted to a generic webhook
+type webhookPayload struct {
+	Message   string    `json:"message"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// slackPayload is the JSON body posted to a Slack incoming webhook
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// discordPayload is the JSON body posted to a Discord webhook
+type discordPayload struct {
+	Content string `json:"content"`
+}
+
+// encodePayload encodes message as the JSON body for the webhook format
+func encodePayload(format WebhookFormat, message string) ([]byte, error) {
+	switch format {
+	case WebhookSlack:
+		return json.Marshal(slackPayload{Text: message})
+	case WebhookDiscord:
+		return json.Marshal(discordPayload{Content: message})
+	default:
+		return json.Marshal(webhookPayload{Message: message, Timestamp: time.Now().UTC()})
+	}
+}
+
+// Notifier posts notification messages to a webhook
+type Notifier struct {
+	url     string
+	format  WebhookFormat
+	enabled bool
+	client  *http.Client
+	backoff time.Duration
+}
+
+// NewNotifier creates a notifier posting to WebhookURL in WebhookFormat with a WebhookTimeout
+// per request
+func (c *Config) NewNotifier() *Notifier {
+	return &Notifier{
+		url:     c.WebhookURL,
+		format:  c.WebhookFormat,
+		enabled: c.NotificationsEnabled,
+		client:  &http.Client{Timeout: time.Duration(c.WebhookTimeout) * time.Second},
+		backoff: notifierInitialBackoff,
+	}
+}
+
+// Send posts message to the webhook, retrying with exponential backoff on 5xx responses
+// and timeouts. It does nothing when notifications are disabled or no webhook is set.
+func (n *Notifier) Send(ctx context.Context, message string) error {
+	if !n.enabled || n.url == "" {
+		return nil
+	}
+
+	body, err := encodePayload(n.format, message)
+	if err != nil {
+		return fmt.Errorf("error encoding webhook payload: %v", err)
+	}
+
+	backoff := n.backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := n.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt == notifierMaxRetries {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post sends one webhook request and reports whether a failure is worth retrying
+func (n *Notifier) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("error creating webhook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		var netErr net.Error
+		timeout := errors.As(err, &netErr) && netErr.Timeout()
+		return timeout && ctx.Err() == nil, fmt.Errorf("error sending webhook: %v", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 500 {
+		return true, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	if resp.StatusCode >= 300 {
+		return false, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return false, nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestNotifier returns a notifier posting to server with a short retry backoff
+func newTestNotifier(server *httptest.Server, format WebhookFormat) *Notifier {
+	c := DefaultConfig()
+	c.WebhookURL = server.URL
+	c.WebhookFormat = format
+	c.NotificationsEnabled = true
+	c.WebhookTimeout = 1
+	n := c.NewNotifier()
+	n.backoff = time.Millisecond
+	return n
+}
+
+func TestNotifierRetriesServerErrors(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request %s with content type %q, want a JSON POST", r.Method, r.Header.Get("Content-Type"))
+		}
+		var payload webhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.Message != "stop hit on BNBUSDT" || payload.Timestamp.IsZero() {
+			t.Errorf("payload = %+v, %v", payload, err)
+		}
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	if err := newTestNotifier(server, WebhookGeneric).Send(context.Background(), "stop hit on BNBUSDT"); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+}
+
+func TestNotifierGivesUpAfterMaxRetries(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if err := newTestNotifier(server, WebhookGeneric).Send(context.Background(), "message"); err == nil {
+		t.Fatal("Send succeeded against a failing webhook")
+	}
+	if attempts != notifierMaxRetries+1 {
+		t.Errorf("attempts = %d, want %d", attempts, notifierMaxRetries+1)
+	}
+}
+
+func TestNotifierDoesNotRetryClientErrors(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	if err := newTestNotifier(server, WebhookGeneric).Send(context.Background(), "message"); err == nil {
+		t.Fatal("Send succeeded against a missing webhook")
+	}
+	if attempts != 1 {
+		t.Errorf("attempts = %d, want 1", attempts)
+	}
+}
+
+func TestNotifierFormatsAndDisabled(t *testing.T) {
+	bodies := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- string(body)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	if err := newTestNotifier(server, WebhookSlack).Send(ctx, "hi"); err != nil {
+		t.Fatalf("Send slack: %v", err)
+	}
+	if err := newTestNotifier(server, WebhookDiscord).Send(ctx, "hi"); err != nil {
+		t.Fatalf("Send discord: %v", err)
+	}
+	if got := <-bodies; got != `{"text":"hi"}` {
+		t.Errorf("slack body = %s", got)
+	}
+	if got := <-bodies; got != `{"content":"hi"}` {
+		t.Errorf("discord body = %s", got)
+	}
+
+	disabled := newTestNotifier(server, WebhookGeneric)
+	disabled.enabled = false
+	if err := disabled.Send(ctx, "hi"); err != nil {
+		t.Fatalf("disabled Send: %v", err)
+	}
+	if len(bodies) != 0 {
+		t.Error("disabled notifier posted")
+	}
+}
+
+func TestNotifierRetriesTimeouts(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	n := newTestNotifier(server, WebhookGeneric)
+	n.client.Timeout = 50 * time.Millisecond
+	if err := n.Send(context.Background(), "message"); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("attempts = %d, want 2", attempts)
+	}
+}
+
+func TestBotNotifiesTradeOpenAndClose(t *testing.T) {
+	webhook := &recordingWebhook{}
+	server := httptest.NewServer(webhook)
+	defer server.Close()
+
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	config := *bot.config.Load()
+	config.WebhookURL = server.URL
+	config.NotificationsEnabled = true
+	bot.Reconfigure(&config)
+
+	ctx := context.Background()
+	p, err := bot.HandleSignal(ctx, testSignal())
+	if err != nil {
+		t.Fatalf("HandleSignal: %v", err)
+	}
+	if _, err := bot.ClosePosition(ctx, p.ID, p.Quantity); err != nil {
+		t.Fatalf("ClosePosition: %v", err)
+	}
+	bot.notifications.Wait()
+
+	got := webhook.received()
+	if len(got) != 2 {
+		t.Fatalf("notifications = %v, want an open and a close", got)
+	}
+	if !strings.HasPrefix(got[0], "Opened BNBUSDT") || !strings.Contains(got[0], "0xleader") {
+		t.Errorf("open notification = %q", got[0])
+	}
+	if !strings.HasPrefix(got[1], "Closed BNBUSDT") || !strings.Contains(got[1], "net profit") {
+		t.Errorf("close notification = %q", got[1])
+	}
+}
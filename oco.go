@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+)
+
+// OCOOrder is a linked stop loss and take profit pair closing a position; when one leg fills
+// the exchange cancels the other
+type OCOOrder struct {
+	Symbol string
+	// Closing side: buy or sell
+	Side     string
+	Quantity float64
+	// Trigger price of the stop-market leg
+	StopPrice float64
+	// Limit price of the take profit leg
+	TakeProfitPrice float64
+}
+
+// OCOPlacer places, cancels and checks exchange OCO order lists. QueryOCO reports the executed
+// fill of a list and whether the list is done, with one leg filled or both cancelled.
+type OCOPlacer interface {
+	PlaceOCO(ctx context.Context, order OCOOrder) (string, error)
+	CancelOCO(ctx context.Context, symbol, listID string) error
+	QueryOCO(ctx context.Context, symbol, listID string) (Fill, bool, error)
+}
+
+// ExitOCO builds the OCO order bracketing p's filled quantity between its effective stop and
+// take profit prices
+func (c *Config) ExitOCO(p *Position) (OCOOrder, error) {
+	stopPrice := c.EffectiveStopPrice(p)
+	takeProfitPrice := c.EffectiveTakeProfitPrice(p)
+	if stopPrice <= 0 || takeProfitPrice <= 0 {
+		return OCOOrder{}, fmt.Errorf("position %s needs both a stop and a take profit price for an OCO exit", p.ID)
+	}
+	side := SideSell
+	if !isBuy(p.Side) {
+		side = SideBuy
+	}
+	return OCOOrder{
+		Symbol:          p.Symbol,
+		Side:            side,
+		Quantity:        p.FilledQuantity,
+		StopPrice:       stopPrice,
+		TakeProfitPrice: takeProfitPrice,
+	}, nil
+}
+
+// placedOCO is the OCO list resting for one position
+type placedOCO struct {
+	listID string
+	order  OCOOrder
+}
+
+// OCOExits keeps one OCO exit resting on the exchange per open position, re-placing it when
+// the position's quantity or levels change, such as after a tier's partial close
+type OCOExits struct {
+	mu     sync.Mutex
+	config *Config
+	placer OCOPlacer
+	lists  map[string]placedOCO
+}
+
+// NewOCOExits creates OCO exits placed through placer
+func (c *Config) NewOCOExits(placer OCOPlacer) *OCOExits {
+	return &OCOExits{
+		config: c,
+		placer: placer,
+		lists:  make(map[string]placedOCO),
+	}
+}
+
+// reconfigure switches later exits to the stop and take profit levels of c
+func (e *OCOExits) reconfigure(c *Config) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.config = c
+}
+
+// Place ensures p has a resting OCO exit matching its current quantity and levels, cancelling
+// the previous list first when it differs. A position with nothing filled has no exit.
+func (e *OCOExits) Place(ctx context.Context, p *Position) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	current, ok := e.lists[p.ID]
+	if p.FilledQuantity <= 0 {
+		if ok {
+			return e.cancel(ctx, p.ID, current)
+		}
+		return nil
+	}
+	order, err := e.config.ExitOCO(p)
+	if err != nil {
+		return err
+	}
+	if ok && current.order.StopPrice == order.StopPrice && current.order.TakeProfitPrice == order.TakeProfitPrice &&
+		math.Abs(current.order.Quantity-order.Quantity) <= order.Quantity*tierPercentageEpsilon {
+		return nil
+	}
+	if ok {
+		if err := e.cancel(ctx, p.ID, current); err != nil {
+			return err
+		}
+	}
+
+	listID, err := e.placer.PlaceOCO(ctx, order)
+	if err != nil {
+		return fmt.Errorf("error placing OCO exit for position %s: %v", p.ID, err)
+	}
+	e.lists[p.ID] = placedOCO{listID: listID, order: order}
+	log.Printf("OCO exit %s for %s %s %f: stop %f, take profit %f", listID, p.Symbol, p.Side, order.Quantity, order.StopPrice, order.TakeProfitPrice)
+	return nil
+}
+
+// Cancel cancels the OCO exit of a position closed by the bot
+func (e *OCOExits) Cancel(ctx context.Context, id string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	current, ok := e.lists[id]
+	if !ok {
+		return nil
+	}
+	return e.cancel(ctx, id, current)
+}
+
+// cancel cancels a placed list and forgets it; must be called with the lock held
+func (e *OCOExits) cancel(ctx context.Context, id string, placed placedOCO) error {
+	if err := e.placer.CancelOCO(ctx, placed.order.Symbol, placed.listID); err != nil {
+		return fmt.Errorf("error cancelling OCO exit %s of position %s: %v", placed.listID, id, err)
+	}
+	delete(e.lists, id)
+	return nil
+}
+
+// Filled returns the fills of the OCO exits that executed on the exchange by position ID and
+// forgets every list that is done, so a list cancelled outside the bot is placed again
+func (e *OCOExits) Filled(ctx context.Context) (map[string]Fill, error) {
+	e.mu.Lock()
+	lists := make(map[string]placedOCO, len(e.lists))
+	for id, placed := range e.lists {
+		lists[id] = placed
+	}
+	e.mu.Unlock()
+
+	var errs []error
+	filled := make(map[string]Fill)
+	done := make(map[string]bool)
+	for id, placed := range lists {
+		fill, ok, err := e.placer.QueryOCO(ctx, placed.order.Symbol, placed.listID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error checking OCO exit %s of position %s: %v", placed.listID, id, err))
+			continue
+		}
+		if !ok {
+			continue
+		}
+		done[id] = true
+		if fill.Quantity > 0 {
+			filled[id] = fill
+		}
+	}
+
+	e.mu.Lock()
+	for id := range done {
+		if e.lists[id].listID == lists[id].listID {
+			delete(e.lists, id)
+		}
+	}
+	e.mu.Unlock()
+	return filled, errors.Join(errs...)
+}
+
+// SyncOCOExits books the OCO exits that executed on the exchange as closes of their positions
+// and re-places the lists that were cancelled outside the bot
+func (b *Bot) SyncOCOExits(ctx context.Context) error {
+	b.mu.Lock()
+	oco := b.oco
+	b.mu.Unlock()
+	if oco == nil {
+		return nil
+	}
+
+	b.closeMu.Lock()
+	defer b.closeMu.Unlock()
+	filled, err := oco.Filled(ctx)
+	return errors.Join(err, b.bookExitFills(ctx, filled, "OCO exit"), oco.Sync(ctx, b.OpenPositions()))
+}
+
+// Sync places the OCO exits of every open position and cancels those of positions no longer open
+func (e *OCOExits) Sync(ctx context.Context, positions []*Position) error {
+	var errs []error
+	open := make(map[string]bool, len(positions))
+	for _, p := range positions {
+		open[p.ID] = true
+		if err := e.Place(ctx, p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	e.mu.Lock()
+	var closed []string
+	for id := range e.lists {
+		if !open[id] {
+			closed = append(closed, id)
+		}
+	}
+	e.mu.Unlock()
+
+	for _, id := range closed {
+		if err := e.Cancel(ctx, id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
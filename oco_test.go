@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// fakeOCOExchange records OCO placements, cancels and market orders in the order they arrive
+type fakeOCOExchange struct {
+	mu     sync.Mutex
+	events []string
+	lists  int
+	// Executed fills reported for queried lists by list ID; a listed ID is done
+	fills map[string]Fill
+}
+
+func (e *fakeOCOExchange) record(format string, args ...interface{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, fmt.Sprintf(format, args...))
+}
+
+func (e *fakeOCOExchange) PlaceOCO(ctx context.Context, order OCOOrder) (string, error) {
+	e.mu.Lock()
+	e.lists++
+	id := fmt.Sprintf("oco-%d", e.lists)
+	e.mu.Unlock()
+	e.record("place %s %g", id, order.Quantity)
+	return id, nil
+}
+
+func (e *fakeOCOExchange) CancelOCO(ctx context.Context, symbol, listID string) error {
+	e.record("cancel %s", listID)
+	return nil
+}
+
+func (e *fakeOCOExchange) QueryOCO(ctx context.Context, symbol, listID string) (Fill, bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	fill, done := e.fills[listID]
+	return fill, done, nil
+}
+
+func (e *fakeOCOExchange) Submit(ctx context.Context, order Order) (Fill, error) {
+	e.record("%s %g", order.Side, order.Quantity)
+	return Fill{Order: order, Price: order.Price, Quantity: order.Quantity}, nil
+}
+
+func TestOCOExitCancelledBeforeClose(t *testing.T) {
+	exchange := &fakeOCOExchange{}
+	bot := newTestBot(t, exchange)
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 4, FilledQuantity: 4})
+	if err := bot.EnableOCOExits(context.Background(), bot.config.Load().NewOCOExits(exchange)); err != nil {
+		t.Fatalf("EnableOCOExits: %v", err)
+	}
+
+	if _, err := bot.ClosePosition(context.Background(), "p", 1); err != nil {
+		t.Fatalf("ClosePosition: %v", err)
+	}
+
+	// The close never races the resting list; the remainder is bracketed again afterwards
+	want := []string{"place oco-1 4", "cancel oco-1", "sell 1", "place oco-2 3"}
+	if fmt.Sprint(exchange.events) != fmt.Sprint(want) {
+		t.Errorf("events = %v, want %v", exchange.events, want)
+	}
+}
+
+func TestFilledOCOExitClosesPosition(t *testing.T) {
+	exchange := &fakeOCOExchange{}
+	bot := newTestBot(t, exchange)
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 4, FilledQuantity: 4})
+	if err := bot.EnableOCOExits(context.Background(), bot.config.Load().NewOCOExits(exchange)); err != nil {
+		t.Fatalf("EnableOCOExits: %v", err)
+	}
+
+	exchange.fills = map[string]Fill{"oco-1": {OrderID: "tp", Price: 105, Quantity: 4}}
+	if err := bot.SyncOCOExits(context.Background()); err != nil {
+		t.Fatalf("SyncOCOExits: %v", err)
+	}
+	if open := bot.OpenPositions(); len(open) != 0 {
+		t.Errorf("open positions = %+v, want the position closed by its take profit", open)
+	}
+	if pnl := bot.CapitalBase().RealizedPnL(); pnl <= 0 {
+		t.Errorf("realized PnL = %f, want the take profit booked", pnl)
+	}
+}
+
+func TestOCOExitCancelledOutsideBotIsPlacedAgain(t *testing.T) {
+	exchange := &fakeOCOExchange{}
+	bot := newTestBot(t, exchange)
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 4, FilledQuantity: 4})
+	if err := bot.EnableOCOExits(context.Background(), bot.config.Load().NewOCOExits(exchange)); err != nil {
+		t.Fatalf("EnableOCOExits: %v", err)
+	}
+
+	exchange.fills = map[string]Fill{"oco-1": {}}
+	if err := bot.SyncOCOExits(context.Background()); err != nil {
+		t.Fatalf("SyncOCOExits: %v", err)
+	}
+	if len(bot.OpenPositions()) != 1 {
+		t.Fatal("position closed by a list that executed nothing")
+	}
+	if last := exchange.events[len(exchange.events)-1]; last != "place oco-2 4" {
+		t.Errorf("last event = %q, want the exit placed again", last)
+	}
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Position sides
+const (
+	SideLong  = "long"
+	SideShort = "short"
+)
+
+// Order sides
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+)
+
+// isLong reports whether side denotes a long position
+func isLong(side string) bool {
+	return strings.ToLower(side) == SideLong
+}
+
+// isBuy reports whether side denotes buying, accepting either order or position sides
+func isBuy(side string) bool {
+	side = strings.ToLower(side)
+	return side == SideBuy || side == SideLong
+}
+
+// isKnownSide reports whether side is an order or position side, so callers can reject empty
+// or misspelled sides instead of treating them as sells
+func isKnownSide(side string) bool {
+	switch strings.ToLower(side) {
+	case SideBuy, SideSell, SideLong, SideShort:
+		return true
+	default:
+		return false
+	}
+}
+
+// OrderType is the order style used for entries
+type OrderType string
+
+const (
+	// OrderTypeMarket takes liquidity at the market price
+	OrderTypeMarket OrderType = "market"
+	// OrderTypeLimit rests a limit order offset from the mid price
+	OrderTypeLimit OrderType = "limit"
+	// OrderTypeStopLimit places a limit order offset from the mid price once a stop is triggered
+	OrderTypeStopLimit OrderType = "stop_limit"
+)
+
+// ParseOrderType parses an order type case-insensitively
+func ParseOrderType(s string) (OrderType, error) {
+	switch orderType := OrderType(strings.ToLower(strings.TrimSpace(s))); orderType {
+	case OrderTypeMarket, OrderTypeLimit, OrderTypeStopLimit:
+		return orderType, nil
+	default:
+		return "", fmt.Errorf("unknown order type %q", s)
+	}
+}
+
+// TimeInForce controls how long a limit order stays on the book
+type TimeInForce string
+
+const (
+	// TimeInForceGTC rests until filled or cancelled
+	TimeInForceGTC TimeInForce = "GTC"
+	// TimeInForceIOC fills what it can immediately and cancels the rest
+	TimeInForceIOC TimeInForce = "IOC"
+	// TimeInForceFOK fills completely at once or is cancelled
+	TimeInForceFOK TimeInForce = "FOK"
+)
+
+// ParseTimeInForce parses a time in force case-insensitively
+func ParseTimeInForce(s string) (TimeInForce, error) {
+	switch tif := TimeInForce(strings.ToUpper(strings.TrimSpace(s))); tif {
+	case TimeInForceGTC, TimeInForceIOC, TimeInForceFOK:
+		return tif, nil
+	default:
+		return "", fmt.Errorf("unknown time in force %q", s)
+	}
+}
+
+// restsOnBook reports whether an order of orderType and tif rests on the book as a maker. Only
+// GTC limit orders rest; IOC and FOK orders fill against the book or cancel, and stop-limit
+// orders usually cross the book when their stop triggers.
+func restsOnBook(orderType OrderType, tif TimeInForce) bool {
+	return orderType == OrderTypeLimit && (tif == TimeInForceGTC || tif == "")
+}
+
+// EntryOrder builds an entry order of the configured type, priced by LimitPrice and carrying
+// the configured time in force for limit-type orders. Stop-limit entries trigger at midPrice.
+func (c *Config) EntryOrder(symbol, side string, quantity, midPrice float64) Order {
+	order := Order{
+		Symbol:   symbol,
+		Side:     side,
+		Quantity: quantity,
+		Price:    c.LimitPrice(midPrice, side),
+		Type:     c.Trading.OrderType,
+	}
+	if c.Trading.OrderType != OrderTypeMarket {
+		order.TimeInForce = c.Trading.TimeInForce
+	}
+	if c.Trading.OrderType == OrderTypeStopLimit {
+		order.StopPrice = midPrice
+	}
+	return order
+}
+
+// LimitPrice returns the limit price for an entry, LimitOffsetPercentage below the mid
+// price for buys and above it for sells. Market orders use the mid price.
+func (c *Config) LimitPrice(midPrice float64, side string) float64 {
+	if c.Trading.OrderType == OrderTypeMarket {
+		return midPrice
+	}
+	if isBuy(side) {
+		return midPrice * (1 - c.Trading.LimitOffsetPercentage)
+	}
+	return midPrice * (1 + c.Trading.LimitOffsetPercentage)
+}
+
+// ReduceOnlyExit marks a closing order reduce-only and caps it at the open quantity so a
+// sizing error can never flip the position, when ReduceOnlyExits is set. Stop-limit exits are
+// rejected: a resting stop cannot be capped against a quantity that changes before it triggers.
+func (c *Config) ReduceOnlyExit(order Order, openQuantity float64) (Order, error) {
+	if !c.Trading.ReduceOnlyExits {
+		return order, nil
+	}
+	if order.Type == OrderTypeStopLimit {
+		return Order{}, fmt.Errorf("reduce-only exit for %s cannot be a %s order", order.Symbol, order.Type)
+	}
+	if openQuantity <= 0 {
+		return Order{}, fmt.Errorf("reduce-only exit for %s with no open quantity", order.Symbol)
+	}
+	order.ReduceOnly = true
+	if order.Quantity > openQuantity {
+		order.Quantity = openQuantity
+	}
+	return order, nil
+}
+
+// ChaseStatus describes the state of a chased limit entry
+type ChaseStatus int
+
+const (
+	// ChaseResting means the entry is resting on the book
+	ChaseResting ChaseStatus = iota
+	// ChaseFilled means the market traded through the entry price
+	ChaseFilled
+	// ChaseAbandoned means the market ran beyond the maximum chase distance
+	ChaseAbandoned
+)
+
+// EntryChaser re-prices a resting limit entry toward the market within a bounded distance
+type EntryChaser struct {
+	signalPrice float64
+	limitPrice  float64
+	bound       float64
+	long        bool
+	status      ChaseStatus
+}
+
+// NewEntryChaser creates a chaser for a limit entry placed at signalPrice.
+// maxChaseDistance is the fraction of signalPrice the entry may be moved toward the market.
+// side may be the entry's order side or its position side.
+func NewEntryChaser(signalPrice, maxChaseDistance float64, side string) *EntryChaser {
+	long := isBuy(side)
+	bound := signalPrice * (1 + maxChaseDistance)
+	if !long {
+		bound = signalPrice * (1 - maxChaseDistance)
+	}
+	return &EntryChaser{
+		signalPrice: signalPrice,
+		limitPrice:  signalPrice,
+		bound:       bound,
+		long:        long,
+	}
+}
+
+// Update processes a new market price and returns the current limit price and entry status
+func (e *EntryChaser) Update(marketPrice float64) (float64, ChaseStatus) {
+	if e.status != ChaseResting {
+		return e.limitPrice, e.status
+	}
+
+	if e.long {
+		switch {
+		case marketPrice <= e.limitPrice:
+			e.status = ChaseFilled
+		case marketPrice > e.bound:
+			e.status = ChaseAbandoned
+		default:
+			e.limitPrice = marketPrice
+		}
+	} else {
+		switch {
+		case marketPrice >= e.limitPrice:
+			e.status = ChaseFilled
+		case marketPrice < e.bound:
+			e.status = ChaseAbandoned
+		default:
+			e.limitPrice = marketPrice
+		}
+	}
+
+	return e.limitPrice, e.status
+}
+
+// SignalPrice returns the original signal price of the entry
+func (e *EntryChaser) SignalPrice() float64 {
+	return e.signalPrice
+}
+
+// OrderRejectReason identifies why order validation rejected an order
+type OrderRejectReason string
+
+const (
+	RejectQuantityTooSmall   OrderRejectReason = "QUANTITY_TOO_SMALL"
+	RejectQuantityTooLarge   OrderRejectReason = "QUANTITY_TOO_LARGE"
+	RejectSlippage           OrderRejectReason = "SLIPPAGE"
+	RejectMaxCapitalPerTrade OrderRejectReason = "MAX_CAPITAL_PER_TRADE"
+	RejectInsufficientEquity OrderRejectReason = "INSUFFICIENT_EQUITY"
+	RejectInsufficientMargin OrderRejectReason = "INSUFFICIENT_MARGIN"
+)
+
+// OrderRejectedError is returned by ValidateOrder; use errors.As to inspect the reason
+type OrderRejectedError struct {
+	Reason  OrderRejectReason
+	Message string
+}
+
+func (e *OrderRejectedError) Error() string {
+	return fmt.Sprintf("order rejected (%s): %s", e.Reason, e.Message)
+}
+
+// rejectOrder builds an OrderRejectedError
+func rejectOrder(reason OrderRejectReason, format string, args ...interface{}) error {
+	return &OrderRejectedError{Reason: reason, Message: fmt.Sprintf(format, args...)}
+}
+
+// ValidateOrder checks an order against the quantity limits, the slippage tolerance from
+// marketPrice, MaxCapitalPerTrade and the available equity, which must cover the order's
+// estimated total cost, or its required margin plus fees and slippage in futures mode. It
+// accepts every order when OrderValidationEnabled is false, and skips the slippage check when
+// marketPrice is 0.
+func (c *Config) ValidateOrder(order Order, marketPrice, availableEquity float64) error {
+	if !c.Trading.OrderValidationEnabled {
+		return nil
+	}
+
+	if order.Quantity < c.Trading.MinOrderQuantity {
+		return rejectOrder(RejectQuantityTooSmall, "quantity %f below minimum %f", order.Quantity, c.Trading.MinOrderQuantity)
+	}
+	if order.Quantity > c.Trading.MaxOrderQuantity {
+		return rejectOrder(RejectQuantityTooLarge, "quantity %f above maximum %f", order.Quantity, c.Trading.MaxOrderQuantity)
+	}
+	if marketPrice > 0 && !c.IsSlippageAcceptable(marketPrice, order.Price, order.Side) {
+		return rejectOrder(RejectSlippage, "price %f is beyond the slippage tolerance %f of market price %f",
+			order.Price, c.Trading.SlippageTolerance, marketPrice)
+	}
+
+	notional := order.Notional()
+	if notional > c.FixedCapital.MaxCapitalPerTrade {
+		return rejectOrder(RejectMaxCapitalPerTrade, "notional %f exceeds max capital per trade %f", notional, c.FixedCapital.MaxCapitalPerTrade)
+	}
+	cost := c.EstimateTradeCost(order.Price, order.Quantity, order.IsMaker())
+	if c.FuturesMode {
+		if margin := c.MarginRequired(notional) + cost.Fee + cost.SlippageCost; margin > availableEquity {
+			return rejectOrder(RejectInsufficientMargin, "required margin %f with fees and slippage exceeds available balance %f", margin, availableEquity)
+		}
+		return nil
+	}
+	if cost.Total > availableEquity {
+		return rejectOrder(RejectInsufficientEquity, "total cost %f (notional %f, fee %f, slippage %f) exceeds available equity %f",
+			cost.Total, cost.Notional, cost.Fee, cost.SlippageCost, availableEquity)
+	}
+	return nil
+}
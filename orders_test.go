@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReduceOnlyExitCapsQuantity(t *testing.T) {
+	c := DefaultConfig()
+	order, err := c.ReduceOnlyExit(Order{Symbol: "BNBUSDT", Side: SideSell, Quantity: 5, Type: OrderTypeMarket}, 3)
+	if err != nil {
+		t.Fatalf("ReduceOnlyExit: %v", err)
+	}
+	if !order.ReduceOnly || order.Quantity != 3 {
+		t.Errorf("got %+v, want a reduce-only order for 3", order)
+	}
+	if _, err := c.ReduceOnlyExit(Order{Symbol: "BNBUSDT", Quantity: 1}, 0); err == nil {
+		t.Error("expected an error with nothing open")
+	}
+}
+
+func TestReduceOnlyExitRejectsStopLimitExits(t *testing.T) {
+	c := DefaultConfig()
+	if _, err := c.ReduceOnlyExit(Order{Symbol: "BNBUSDT", Quantity: 1, Type: OrderTypeStopLimit}, 1); err == nil {
+		t.Error("expected a stop-limit exit to be rejected")
+	}
+}
+
+func TestStopLimitEntriesValidateWithReduceOnlyExits(t *testing.T) {
+	c := testnetConfig()
+	c.Trading.OrderType = OrderTypeStopLimit
+	if err := c.Validate(); err != nil {
+		t.Errorf("stop-limit entries with reduce-only exits rejected: %v", err)
+	}
+}
+
+func TestEntryOrderStopLimitCarriesStopPrice(t *testing.T) {
+	c := DefaultConfig()
+	c.Trading.OrderType = OrderTypeStopLimit
+	order := c.EntryOrder("BNBUSDT", SideBuy, 1, 100)
+	if order.StopPrice != 100 || order.TimeInForce == "" {
+		t.Errorf("stop-limit entry = %+v, want a stop at the mid price and a time in force", order)
+	}
+
+	c.Trading.OrderType = OrderTypeMarket
+	if order := c.EntryOrder("BNBUSDT", SideBuy, 1, 100); order.StopPrice != 0 {
+		t.Errorf("market entry has stop price %f", order.StopPrice)
+	}
+}
+
+// validationConfig returns a spot config with distinct maker and taker fees and 1% slippage
+func validationConfig() *Config {
+	c := DefaultConfig()
+	c.Trading.MakerFee = 0.001
+	c.Trading.TakerFee = 0.002
+	c.Trading.MakerRebateRate = 0
+	c.Trading.SlippageTolerance = 0.01
+	c.Trading.OrderValidationEnabled = true
+	return c
+}
+
+func TestOrderIsMaker(t *testing.T) {
+	tests := []struct {
+		orderType OrderType
+		tif       TimeInForce
+		want      bool
+	}{
+		{OrderTypeLimit, TimeInForceGTC, true},
+		{OrderTypeLimit, "", true},
+		{OrderTypeLimit, TimeInForceIOC, false},
+		{OrderTypeLimit, TimeInForceFOK, false},
+		{OrderTypeMarket, "", false},
+		{OrderTypeStopLimit, TimeInForceGTC, false},
+	}
+	for _, tt := range tests {
+		if got := (Order{Type: tt.orderType, TimeInForce: tt.tif}).IsMaker(); got != tt.want {
+			t.Errorf("%s %s: IsMaker = %t, want %t", tt.orderType, tt.tif, got, tt.want)
+		}
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	// 2 at 100 costs 200.2 as a maker and 202.4 as a taker (0.4 fee, 2 slippage)
+	tests := []struct {
+		name        string
+		order       Order
+		marketPrice float64
+		equity      float64
+		futures     bool
+		want        OrderRejectReason
+	}{
+		{"maker buy covered", Order{Side: SideBuy, Quantity: 2, Price: 100, Type: OrderTypeLimit, TimeInForce: TimeInForceGTC}, 100, 200.3, false, ""},
+		{"maker buy uncovered", Order{Side: SideBuy, Quantity: 2, Price: 100, Type: OrderTypeLimit, TimeInForce: TimeInForceGTC}, 100, 200.1, false, RejectInsufficientEquity},
+		{"IOC buy pays taker costs", Order{Side: SideBuy, Quantity: 2, Price: 100, Type: OrderTypeLimit, TimeInForce: TimeInForceIOC}, 100, 201, false, RejectInsufficientEquity},
+		{"FOK buy pays taker costs", Order{Side: SideBuy, Quantity: 2, Price: 100, Type: OrderTypeLimit, TimeInForce: TimeInForceFOK}, 100, 201, false, RejectInsufficientEquity},
+		{"IOC buy covered", Order{Side: SideBuy, Quantity: 2, Price: 100, Type: OrderTypeLimit, TimeInForce: TimeInForceIOC}, 100, 202.5, false, ""},
+		{"market sell covered", Order{Side: SideSell, Quantity: 2, Price: 100, Type: OrderTypeMarket}, 100, 202.5, false, ""},
+		{"market sell uncovered", Order{Side: SideSell, Quantity: 2, Price: 100, Type: OrderTypeMarket}, 100, 202.3, false, RejectInsufficientEquity},
+		{"buy above slippage", Order{Side: SideBuy, Quantity: 2, Price: 102, Type: OrderTypeMarket}, 100, 1000, false, RejectSlippage},
+		{"sell below slippage", Order{Side: SideSell, Quantity: 2, Price: 98, Type: OrderTypeMarket}, 100, 1000, false, RejectSlippage},
+		{"no market price skips slippage", Order{Side: SideBuy, Quantity: 2, Price: 102, Type: OrderTypeMarket}, 0, 1000, false, ""},
+		{"quantity too small", Order{Side: SideBuy, Quantity: 0.001, Price: 100}, 100, 1000, false, RejectQuantityTooSmall},
+		{"quantity too large", Order{Side: SideBuy, Quantity: 2000, Price: 100}, 100, 1e9, false, RejectQuantityTooLarge},
+		{"above max capital per trade", Order{Side: SideBuy, Quantity: 6, Price: 100}, 100, 1e9, false, RejectMaxCapitalPerTrade},
+		{"futures margin covered", Order{Side: SideSell, Quantity: 2, Price: 100, Type: OrderTypeMarket}, 100, 42.5, true, ""},
+		{"futures margin uncovered", Order{Side: SideSell, Quantity: 2, Price: 100, Type: OrderTypeMarket}, 100, 42.3, true, RejectInsufficientMargin},
+	}
+	for _, tt := range tests {
+		c := validationConfig()
+		if tt.futures {
+			// 200 notional at 5x needs 40 margin plus the 0.4 fee and 2 slippage
+			c.FuturesMode = true
+			c.Futures.Leverage = 5
+		}
+		tt.order.Symbol = "BNBUSDT"
+		err := c.ValidateOrder(tt.order, tt.marketPrice, tt.equity)
+		var rejected *OrderRejectedError
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%s: ValidateOrder = %v, want accepted", tt.name, err)
+		case tt.want != "" && (!errors.As(err, &rejected) || rejected.Reason != tt.want):
+			t.Errorf("%s: ValidateOrder = %v, want %s", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestValidateOrderDisabled(t *testing.T) {
+	c := validationConfig()
+	c.Trading.OrderValidationEnabled = false
+	if err := c.ValidateOrder(Order{Side: SideBuy, Quantity: 1e6, Price: 100}, 100, 0); err != nil {
+		t.Errorf("ValidateOrder with validation disabled = %v", err)
+	}
+}
+
+func TestEntryChaserLong(t *testing.T) {
+	chaser := NewEntryChaser(100, 0.01, SideBuy)
+	steps := []struct {
+		market     float64
+		wantPrice  float64
+		wantStatus ChaseStatus
+	}{
+		{100.5, 100.5, ChaseResting},
+		{100.8, 100.8, ChaseResting},
+		{100.7, 100.8, ChaseFilled},
+		// Once filled the chaser ignores later prices
+		{105, 100.8, ChaseFilled},
+	}
+	for i, step := range steps {
+		price, status := chaser.Update(step.market)
+		if price != step.wantPrice || status != step.wantStatus {
+			t.Errorf("step %d: Update(%f) = %f, %d, want %f, %d", i, step.market, price, status, step.wantPrice, step.wantStatus)
+		}
+	}
+	if chaser.SignalPrice() != 100 {
+		t.Errorf("SignalPrice() = %f, want 100", chaser.SignalPrice())
+	}
+}
+
+func TestEntryChaserAbandonsBeyondBound(t *testing.T) {
+	long := NewEntryChaser(100, 0.01, SideBuy)
+	if price, status := long.Update(101.5); status != ChaseAbandoned || price != 100 {
+		t.Errorf("long Update(101.5) = %f, %d, want 100, abandoned", price, status)
+	}
+
+	short := NewEntryChaser(100, 0.01, SideSell)
+	if price, status := short.Update(99.5); status != ChaseResting || price != 99.5 {
+		t.Errorf("short Update(99.5) = %f, %d, want 99.5, resting", price, status)
+	}
+	if price, status := short.Update(98.5); status != ChaseAbandoned || price != 99.5 {
+		t.Errorf("short Update(98.5) = %f, %d, want 99.5, abandoned", price, status)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+// pairRisk is the risk state the bot keeps for one trading pair, so losses and trade outcomes
+// in one pair neither pause nor size trading in another
+type pairRisk struct {
+	losses   *LossTracker
+	outcomes *WinRateTracker
+}
+
+// pairRisk returns the risk state of symbol, creating it from the symbol's risk settings.
+// Must be called with mu held.
+func (b *Bot) pairRisk(symbol string) *pairRisk {
+	pair, ok := b.pairs[symbol]
+	if !ok {
+		pair = &pairRisk{
+			losses:   b.config.Load().ForSymbol(symbol).NewLossTracker(),
+			outcomes: NewWinRateTracker(0),
+		}
+		b.pairs[symbol] = pair
+	}
+	return pair
+}
+
+// LossTracker returns the consecutive loss tracker of symbol
+func (b *Bot) LossTracker(symbol string) *LossTracker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.pairRisk(symbol).losses
+}
+
+// WinRateTracker returns the closed trade outcomes of symbol
+func (b *Bot) WinRateTracker(symbol string) *WinRateTracker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.pairRisk(symbol).outcomes
+}
+
+// pairConsecutiveLosses returns the consecutive losses of every pair that has any. Must be
+// called with mu held.
+func (b *Bot) pairConsecutiveLosses() map[string]int {
+	losses := make(map[string]int)
+	for symbol, pair := range b.pairs {
+		if n := pair.losses.ConsecutiveLosses(); n > 0 {
+			losses[symbol] = n
+		}
+	}
+	return losses
+}
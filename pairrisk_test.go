@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestConsecutiveLossesPauseOnlyTheirPair(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	for i := 0; i < bot.config.Load().RiskManagement.MaxConsecutiveLosses; i++ {
+		bot.LossTracker("BNBUSDT").RecordTrade(-1)
+	}
+
+	if bot.AllowEntry(CopySignal{Symbol: "BNBUSDT", Side: SideLong}) {
+		t.Error("AllowEntry allowed BNBUSDT after its consecutive losses")
+	}
+	if !bot.AllowEntry(CopySignal{Symbol: "ETHUSDT", Side: SideLong}) {
+		t.Error("AllowEntry refused ETHUSDT after losses in BNBUSDT")
+	}
+}
+
+func TestClosesRecordOutcomesPerPair(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 1, FilledQuantity: 1})
+	bot.UpdatePrice("BNBUSDT", 90)
+	if _, err := bot.ClosePosition(context.Background(), "p", 1); err != nil {
+		t.Fatalf("ClosePosition: %v", err)
+	}
+
+	if got := bot.WinRateTracker("BNBUSDT").Losses(); got != 1 {
+		t.Errorf("BNBUSDT losses = %d, want 1", got)
+	}
+	if got := bot.LossTracker("BNBUSDT").ConsecutiveLosses(); got != 1 {
+		t.Errorf("BNBUSDT consecutive losses = %d, want 1", got)
+	}
+	if got := bot.WinRateTracker("ETHUSDT").TotalTrades(); got != 0 {
+		t.Errorf("ETHUSDT trades = %d, want 0", got)
+	}
+}
+
+func TestSizePositionUsesPairOutcomesAndOverrides(t *testing.T) {
+	c := DefaultConfig()
+	c.Logging.LogConfigOnStart = false
+	c.SizingStrategy = SizingKelly
+	c.FixedCapital.MaxCapitalPerTrade = 1e9
+	c.RiskManagement.MaxPositionSize = 1
+	half := 0.5
+	withOverride(c, "ETHUSDT", RiskOverride{MaxPositionSize: &half})
+	bot, err := NewBot(c, &fakeExecutor{fillRatio: 1}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewBot: %v", err)
+	}
+	// A strong edge in BNBUSDT only: BNBUSDT sizes by Kelly, ETHUSDT falls back to fixed risk
+	for _, profit := range []float64{30, 30, 30, -10} {
+		bot.WinRateTracker("BNBUSDT").RecordTrade(profit)
+	}
+
+	fixed := c.CalculatePositionSize(1000, 100, 95, SideLong)
+	if got := bot.SizePosition("ETHUSDT", 100, 95, SideLong); got != fixed {
+		t.Errorf("ETHUSDT size = %f, want the fixed risk size %f", got, fixed)
+	}
+	kelly := c.KellyPositionSize(0.75, 30, 10, 1000) / 100
+	if got := bot.SizePosition("BNBUSDT", 100, 95, SideLong); got != kelly {
+		t.Errorf("BNBUSDT size = %f, want the Kelly size %f", got, kelly)
+	}
+
+	// The ETHUSDT position cap override applies to its sizing only
+	c.SizingStrategy = SizingFixedRisk
+	c.FixedCapital.RiskPercentage = 0.9
+	if got, want := bot.SizePosition("ETHUSDT", 100, 95, SideLong), 5.0; got != want {
+		t.Errorf("capped ETHUSDT size = %f, want %f", got, want)
+	}
+	if got, want := bot.SizePosition("BNBUSDT", 100, 95, SideLong), 10.0; got != want {
+		t.Errorf("capped BNBUSDT size = %f, want %f", got, want)
+	}
+}
+
+func TestBotRestoresConsecutiveLossesPerPair(t *testing.T) {
+	store, err := NewFileStateStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStateStore: %v", err)
+	}
+	c := DefaultConfig()
+	c.Logging.LogConfigOnStart = false
+	bot, err := NewBot(c, &fakeExecutor{fillRatio: 1}, nil, store)
+	if err != nil {
+		t.Fatalf("NewBot: %v", err)
+	}
+	bot.LossTracker("BNBUSDT").RecordTrade(-1)
+	bot.LossTracker("BNBUSDT").RecordTrade(-1)
+	bot.LossTracker("ETHUSDT").RecordTrade(-1)
+	if err := bot.SaveState(); err != nil {
+		t.Fatalf("SaveState: %v", err)
+	}
+
+	restored, err := NewBot(c, &fakeExecutor{fillRatio: 1}, nil, store)
+	if err != nil {
+		t.Fatalf("NewBot after restart: %v", err)
+	}
+	if got := restored.LossTracker("BNBUSDT").ConsecutiveLosses(); got != 2 {
+		t.Errorf("restored BNBUSDT losses = %d, want 2", got)
+	}
+	if got := restored.LossTracker("ETHUSDT").ConsecutiveLosses(); got != 1 {
+		t.Errorf("restored ETHUSDT losses = %d, want 1", got)
+	}
+}
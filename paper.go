@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// paperAccountKey is the state store key of the paper trading account
+const paperAccountKey = "paper_account"
+
+// PaperAccount is the simulated paper trading account persisted across restarts
+type PaperAccount struct {
+	Balance float64
+	// Base quantity held per symbol; negative when net short in futures mode
+	Holdings  map[string]float64
+	FeesPaid  float64
+	Fills     int
+	StartedAt time.Time
+}
+
+// PaperBroker simulates a full account, filling orders with slippage and fees and
+// persisting the balance so a paper run survives restarts
+type PaperBroker struct {
+	mu      sync.Mutex
+	cfg     *Config
+	store   StateStore
+	account PaperAccount
+	now     func() time.Time
+}
+
+// NewPaperBroker creates a paper broker, resuming the account saved in store or opening one
+// with PaperTrading.StartingBalance. A store is required so the account survives restarts.
+func (c *Config) NewPaperBroker(store StateStore) (*PaperBroker, error) {
+	if store == nil {
+		return nil, fmt.Errorf("paper trading needs a state store")
+	}
+	b := &PaperBroker{cfg: c, store: store, now: time.Now}
+
+	err := store.Load(paperAccountKey, &b.account)
+	switch {
+	case errors.Is(err, ErrStateNotFound):
+		b.account = PaperAccount{
+			Balance:   c.PaperTrading.StartingBalance,
+			Holdings:  make(map[string]float64),
+			StartedAt: b.now(),
+		}
+	case err != nil:
+		return nil, fmt.Errorf("error restoring paper account: %v", err)
+	default:
+		if b.account.Holdings == nil {
+			b.account.Holdings = make(map[string]float64)
+		}
+		log.Printf("📄 Paper trading: resumed account started %s with balance %f",
+			b.account.StartedAt.UTC().Format(time.RFC3339), b.account.Balance)
+	}
+	return b, nil
+}
+
+// Submit fills the order at its market price adjusted by SlippageTolerance, charging the
+// taker fee, and saves the account before reporting the fill. Outside futures mode a sell
+// cannot exceed the quantity held.
+func (b *PaperBroker) Submit(ctx context.Context, order Order) (Fill, error) {
+	if order.Quantity <= 0 || order.Price <= 0 {
+		return Fill{}, fmt.Errorf("invalid paper order: quantity %f at price %f", order.Quantity, order.Price)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	price := b.cfg.EstimateFillPrice(order.Price, order.Side)
+	notional := price * order.Quantity
+	fee := notional * b.cfg.feeRate(false)
+
+	account := b.account
+	account.Holdings = make(map[string]float64, len(b.account.Holdings)+1)
+	for symbol, quantity := range b.account.Holdings {
+		account.Holdings[symbol] = quantity
+	}
+	if isBuy(order.Side) {
+		if notional+fee > account.Balance {
+			return Fill{}, fmt.Errorf("insufficient paper balance: need %f, have %f", notional+fee, account.Balance)
+		}
+		account.Balance -= notional + fee
+		account.Holdings[order.Symbol] += order.Quantity
+	} else {
+		if held := account.Holdings[order.Symbol]; !b.cfg.FuturesMode && order.Quantity > held {
+			return Fill{}, fmt.Errorf("insufficient paper holdings of %s: selling %f, holding %f", order.Symbol, order.Quantity, held)
+		}
+		account.Balance += notional - fee
+		account.Holdings[order.Symbol] -= order.Quantity
+	}
+	account.FeesPaid += fee
+	account.Fills++
+
+	if err := b.store.Save(paperAccountKey, account); err != nil {
+		return Fill{}, fmt.Errorf("error saving paper account: %v", err)
+	}
+	b.account = account
+
+	log.Printf("📄 Paper trading: %s %f %s at %f (fee %f, balance %f)", order.Side, order.Quantity, order.Symbol, price, fee, account.Balance)
+	return Fill{
+		Order:    order,
+		Price:    price,
+		Quantity: order.Quantity,
+		Fee:      fee,
+		Time:     b.now(),
+	}, nil
+}
+
+// Balance returns the paper quote balance
+func (b *PaperBroker) Balance() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.account.Balance
+}
+
+// Holding returns the paper base quantity held for symbol; negative when net short
+func (b *PaperBroker) Holding(symbol string) float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.account.Holdings[symbol]
+}
+
+// Equity returns the balance plus the holdings valued at prices; holdings without a price are skipped
+func (b *PaperBroker) Equity(prices map[string]float64) float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	equity := b.account.Balance
+	for symbol, quantity := range b.account.Holdings {
+		equity += quantity * prices[symbol]
+	}
+	return equity
+}
+
+// Account returns a copy of the paper account
+func (b *PaperBroker) Account() PaperAccount {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	account := b.account
+	account.Holdings = make(map[string]float64, len(b.account.Holdings))
+	for symbol, quantity := range b.account.Holdings {
+		account.Holdings[symbol] = quantity
+	}
+	return account
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func newTestPaperBroker(t *testing.T, c *Config) (*PaperBroker, StateStore) {
+	t.Helper()
+	store, err := NewFileStateStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStateStore: %v", err)
+	}
+	c.PaperTrading.StartingBalance = 1000
+	c.Trading.TakerFee = 0.001
+	c.Trading.SlippageTolerance = 0
+	broker, err := c.NewPaperBroker(store)
+	if err != nil {
+		t.Fatalf("NewPaperBroker: %v", err)
+	}
+	return broker, store
+}
+
+func TestNewPaperBrokerRequiresStore(t *testing.T) {
+	if _, err := DefaultConfig().NewPaperBroker(nil); err == nil {
+		t.Error("NewPaperBroker accepted a nil store")
+	}
+}
+
+func TestPaperBrokerBuyThenSell(t *testing.T) {
+	broker, _ := newTestPaperBroker(t, DefaultConfig())
+	ctx := context.Background()
+
+	if _, err := broker.Submit(ctx, Order{Symbol: "BNBUSDT", Side: SideBuy, Quantity: 2, Price: 100}); err != nil {
+		t.Fatalf("buy: %v", err)
+	}
+	if got := broker.Balance(); math.Abs(got-(1000-200.2)) > 1e-9 {
+		t.Errorf("balance after buy = %f, want 799.8", got)
+	}
+	if _, err := broker.Submit(ctx, Order{Symbol: "BNBUSDT", Side: SideSell, Quantity: 2, Price: 110}); err != nil {
+		t.Fatalf("sell: %v", err)
+	}
+	if got := broker.Holding("BNBUSDT"); got != 0 {
+		t.Errorf("holding after sell = %f, want 0", got)
+	}
+	if got := broker.Balance(); math.Abs(got-(799.8+220-0.22)) > 1e-9 {
+		t.Errorf("balance after sell = %f, want 1019.58", got)
+	}
+}
+
+func TestPaperBrokerRejectsSellWithoutHoldings(t *testing.T) {
+	broker, _ := newTestPaperBroker(t, DefaultConfig())
+	ctx := context.Background()
+
+	if _, err := broker.Submit(ctx, Order{Symbol: "BNBUSDT", Side: SideSell, Quantity: 1, Price: 100}); err == nil {
+		t.Error("sell without holdings was filled")
+	}
+	if _, err := broker.Submit(ctx, Order{Symbol: "BNBUSDT", Side: SideBuy, Quantity: 1, Price: 100}); err != nil {
+		t.Fatalf("buy: %v", err)
+	}
+	if _, err := broker.Submit(ctx, Order{Symbol: "BNBUSDT", Side: SideSell, Quantity: 2, Price: 100}); err == nil {
+		t.Error("sell above holdings was filled")
+	}
+	account := broker.Account()
+	if account.Fills != 1 || account.Holdings["BNBUSDT"] != 1 {
+		t.Errorf("account after rejected sells = %+v", account)
+	}
+}
+
+func TestPaperBrokerAllowsFuturesShorts(t *testing.T) {
+	c := DefaultConfig()
+	c.FuturesMode = true
+	broker, _ := newTestPaperBroker(t, c)
+
+	if _, err := broker.Submit(context.Background(), Order{Symbol: "BNBUSDT", Side: SideSell, Quantity: 1, Price: 100}); err != nil {
+		t.Fatalf("futures short: %v", err)
+	}
+	if got := broker.Holding("BNBUSDT"); got != -1 {
+		t.Errorf("holding after short = %f, want -1", got)
+	}
+}
+
+func TestPaperBrokerRejectsBuyAboveBalance(t *testing.T) {
+	broker, _ := newTestPaperBroker(t, DefaultConfig())
+	if _, err := broker.Submit(context.Background(), Order{Symbol: "BNBUSDT", Side: SideBuy, Quantity: 10, Price: 100}); err == nil {
+		t.Error("buy above the balance was filled")
+	}
+}
+
+func TestPaperBrokerResumesAccount(t *testing.T) {
+	c := DefaultConfig()
+	broker, store := newTestPaperBroker(t, c)
+	if _, err := broker.Submit(context.Background(), Order{Symbol: "BNBUSDT", Side: SideBuy, Quantity: 1, Price: 100}); err != nil {
+		t.Fatalf("buy: %v", err)
+	}
+
+	resumed, err := c.NewPaperBroker(store)
+	if err != nil {
+		t.Fatalf("NewPaperBroker after restart: %v", err)
+	}
+	if got, want := resumed.Balance(), broker.Balance(); got != want {
+		t.Errorf("resumed balance = %f, want %f", got, want)
+	}
+	if got := resumed.Holding("BNBUSDT"); got != 1 {
+		t.Errorf("resumed holding = %f, want 1", got)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+// NetProfit returns the profit of a long round trip after entry and exit fees. When FuturesMode
+// is enabled it also subtracts funding, the funding paid over the hold such as estimated by
+// EstimateFundingCost; funding is ignored for spot trades.
+func (c *Config) NetProfit(entryPrice, exitPrice, quantity float64, entryIsMaker, exitIsMaker bool, funding float64) float64 {
+	gross := (exitPrice - entryPrice) * quantity
+	fees := entryPrice*quantity*c.feeRate(entryIsMaker) + exitPrice*quantity*c.feeRate(exitIsMaker)
+	profit := gross - fees
+	if c.FuturesMode {
+		profit -= funding
+	}
+	return profit
+}
+
+// RealizedProfit returns the profit of closing quantity of p at exitPrice after its share of
+// the entry fee and the exit fee, for longs and shorts
+func (c *Config) RealizedProfit(p *Position, exitPrice, quantity float64) float64 {
+	move := exitPrice - p.EntryPrice
+	if !isBuy(p.Side) {
+		move = -move
+	}
+	fees := p.EntryPrice*quantity*c.entryFee() + exitPrice*quantity*c.exitFee()
+	return move*quantity - fees
+}
+
+// NetProfitPercentage returns NetProfit as a percentage of the entry notional (1.0 = 1%)
+func (c *Config) NetProfitPercentage(entryPrice, exitPrice, quantity float64, entryIsMaker, exitIsMaker bool, funding float64) float64 {
+	notional := entryPrice * quantity
+	if notional == 0 {
+		return 0
+	}
+	return c.NetProfit(entryPrice, exitPrice, quantity, entryIsMaker, exitIsMaker, funding) / notional * 100
+}
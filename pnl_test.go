@@ -0,0 +1,69 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNetProfitDeductsFees(t *testing.T) {
+	c := DefaultConfig()
+	c.Trading.MakerFee = 0.001
+	c.Trading.TakerFee = 0.002
+	c.Trading.MakerRebateRate = 0
+	c.Trading.FeeSchedule = FeeSchedule{}
+
+	// gross 10, fees 100*0.001 + 110*0.002
+	got := c.NetProfit(100, 110, 1, true, false, 0)
+	if want := 10 - 0.1 - 0.22; math.Abs(got-want) > 1e-9 {
+		t.Errorf("NetProfit = %f, want %f", got, want)
+	}
+	if pct := c.NetProfitPercentage(100, 110, 1, true, false, 0); math.Abs(pct-(10-0.32)) > 1e-9 {
+		t.Errorf("NetProfitPercentage = %f, want %f", pct, 10-0.32)
+	}
+	if pct := c.NetProfitPercentage(0, 110, 1, true, false, 0); pct != 0 {
+		t.Errorf("NetProfitPercentage with zero notional = %f, want 0", pct)
+	}
+}
+
+func TestNetProfitBreakEvenLosesFees(t *testing.T) {
+	c := DefaultConfig()
+	c.Trading.TakerFee = 0.001
+	c.Trading.FeeSchedule = FeeSchedule{}
+	// A round trip at the same price loses both taker fees: 0.1% of 500 twice
+	got := c.NetProfit(250, 250, 2, false, false, 0)
+	if math.Abs(got+1) > 1e-9 {
+		t.Errorf("break-even gross trade NetProfit = %f, want -1", got)
+	}
+	if pct := c.NetProfitPercentage(250, 250, 2, false, false, 0); math.Abs(pct+0.2) > 1e-9 {
+		t.Errorf("break-even gross trade NetProfitPercentage = %f, want -0.2", pct)
+	}
+}
+
+func TestRealizedProfitSides(t *testing.T) {
+	c := DefaultConfig()
+	c.Trading.OrderType = OrderTypeMarket
+	c.Trading.TakerFee = 0.001
+	c.Trading.FeeSchedule = FeeSchedule{}
+	long := &Position{Side: SideLong, EntryPrice: 100}
+	short := &Position{Side: SideShort, EntryPrice: 100}
+
+	// Closing 1 of either side after a 10 move pays a 0.1 entry fee and a 0.11 or 0.09 exit fee
+	if got := c.RealizedProfit(long, 110, 1); math.Abs(got-(10-0.1-0.11)) > 1e-9 {
+		t.Errorf("long RealizedProfit = %f, want %f", got, 10-0.1-0.11)
+	}
+	if got := c.RealizedProfit(short, 90, 1); math.Abs(got-(10-0.1-0.09)) > 1e-9 {
+		t.Errorf("short RealizedProfit = %f, want %f", got, 10-0.1-0.09)
+	}
+}
+
+func TestNetProfitSubtractsFundingInFuturesMode(t *testing.T) {
+	c := DefaultConfig()
+	spot := c.NetProfit(100, 110, 1, false, false, 0.5)
+	if want := c.NetProfit(100, 110, 1, false, false, 0); spot != want {
+		t.Errorf("spot NetProfit = %f, want funding ignored (%f)", spot, want)
+	}
+	c.FuturesMode = true
+	if got := c.NetProfit(100, 110, 1, false, false, 0.5); math.Abs(got-(spot-0.5)) > 1e-9 {
+		t.Errorf("futures NetProfit = %f, want %f", got, spot-0.5)
+	}
+}
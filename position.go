@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Position represents an open position
+type Position struct {
+	ID     string
+	Symbol string
+	// Position side: long or short
+	Side string
+	// Price the entry was requested at
+	RequestedPrice float64
+	// Confirmed average fill price; all exit levels derive from it
+	EntryPrice float64
+	// Quantity requested by the entry order
+	Quantity float64
+	// Quantity filled so far; tiers and stops operate on it
+	FilledQuantity float64
+	// Exchange ID of the entry order, empty for simulated fills
+	EntryOrderID string
+	// Leader whose trade the position copies, empty for the bot's own entries
+	LeaderAddress string
+	// Stop price overriding the percentage stop loss, 0 when unset
+	AbsoluteStopPrice float64
+	// Take profit price overriding the tier targets, 0 when unset
+	AbsoluteTakeProfitPrice float64
+	OpenedAt                time.Time
+	// Profit tiers of the position and which have already closed
+	TierState TierState
+}
+
+// NewPositionFromFill opens a position from an entry fill, anchoring it to the actual fill price.
+// A fill without a time opens the position now, so its hold time is not counted from the zero
+// time. Later fills of a partially filled entry are added with ApplyFill.
+func NewPositionFromFill(id, side string, fill Fill) *Position {
+	quantity := fill.Order.Quantity
+	if quantity < fill.Quantity {
+		quantity = fill.Quantity
+	}
+	openedAt := fill.Time
+	if openedAt.IsZero() {
+		openedAt = time.Now()
+	}
+	return &Position{
+		ID:             id,
+		Symbol:         fill.Order.Symbol,
+		Side:           side,
+		RequestedPrice: fill.Order.Price,
+		EntryPrice:     fill.Price,
+		Quantity:       quantity,
+		FilledQuantity: fill.Quantity,
+		EntryOrderID:   fill.OrderID,
+		OpenedAt:       openedAt,
+	}
+}
+
+// ApplyFill adds a fill of the entry order, moving the entry price to the average fill price
+func (p *Position) ApplyFill(fill Fill) {
+	if fill.Quantity <= 0 {
+		return
+	}
+	var vwap VWAP
+	vwap.Add(p.EntryPrice, p.FilledQuantity)
+	vwap.Add(fill.Price, fill.Quantity)
+	p.EntryPrice = vwap.Value()
+	p.FilledQuantity = vwap.Quantity()
+}
+
+// UnmarshalJSON decodes a saved position. Positions saved before partial fills were tracked
+// have no FilledQuantity field and were always fully filled; a saved zero is kept as unfilled.
+func (p *Position) UnmarshalJSON(data []byte) error {
+	type position Position
+	var saved struct {
+		position
+		FilledQuantity *float64
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	*p = Position(saved.position)
+	if saved.FilledQuantity != nil {
+		p.FilledQuantity = *saved.FilledQuantity
+	} else {
+		p.FilledQuantity = p.Quantity
+	}
+	return nil
+}
+
+// UnfilledQuantity returns the quantity of the entry order still waiting to fill
+func (p *Position) UnfilledQuantity() float64 {
+	return math.Max(p.Quantity-p.FilledQuantity, 0)
+}
+
+// FullyFilled reports whether the entry order has filled completely
+func (p *Position) FullyFilled() bool {
+	return p.UnfilledQuantity() <= p.Quantity*tierPercentageEpsilon
+}
+
+// SetAbsoluteLevels sets absolute stop and take profit prices, 0 leaving either to the
+// percentage config. The stop must be on the losing side of entry and the target on the winning side.
+func (p *Position) SetAbsoluteLevels(stopPrice, takeProfitPrice float64) error {
+	long := isLong(p.Side)
+	if stopPrice < 0 || takeProfitPrice < 0 {
+		return fmt.Errorf("absolute stop and take profit prices must be non-negative")
+	}
+	if stopPrice > 0 && ((long && stopPrice >= p.EntryPrice) || (!long && stopPrice <= p.EntryPrice)) {
+		return fmt.Errorf("absolute stop %f is on the wrong side of the %s entry at %f", stopPrice, p.Side, p.EntryPrice)
+	}
+	if takeProfitPrice > 0 && ((long && takeProfitPrice <= p.EntryPrice) || (!long && takeProfitPrice >= p.EntryPrice)) {
+		return fmt.Errorf("absolute take profit %f is on the wrong side of the %s entry at %f", takeProfitPrice, p.Side, p.EntryPrice)
+	}
+	p.AbsoluteStopPrice = stopPrice
+	p.AbsoluteTakeProfitPrice = takeProfitPrice
+	return nil
+}
+
+// EffectiveStopPrice returns the position's absolute stop when set, else the percentage stop loss
+func (c *Config) EffectiveStopPrice(p *Position) float64 {
+	if p.AbsoluteStopPrice > 0 {
+		return p.AbsoluteStopPrice
+	}
+	return c.ForSymbol(p.Symbol).StopLossPrice(p.EntryPrice, p.Side)
+}
+
+// EffectiveTakeProfitPrice returns the position's absolute take profit when set, else the
+// furthest enabled tier target from entry, the highest for longs and the lowest for shorts;
+// 0 when neither exists
+func (c *Config) EffectiveTakeProfitPrice(p *Position) float64 {
+	if p.AbsoluteTakeProfitPrice > 0 {
+		return p.AbsoluteTakeProfitPrice
+	}
+	long := isLong(p.Side)
+	var furthest float64
+	for _, target := range c.MultiTier.TierTargetPrices(p.EntryPrice, p.Side) {
+		if target <= 0 {
+			continue
+		}
+		if furthest == 0 || (long && target > furthest) || (!long && target < furthest) {
+			furthest = target
+		}
+	}
+	return furthest
+}
+
+// TierQuantities returns the quantity to close at each tier, split from the filled quantity only
+func (c *Config) TierQuantities(p *Position) ([]float64, error) {
+	return c.MultiTier.CalculateTierQuantities(p.FilledQuantity)
+}
+
+// ExitLevels holds the exit prices derived for a position
+type ExitLevels struct {
+	// Target price per tier, indexed like MultiTierConfig.Tiers
+	TierTargets []float64
+	StopPrice   float64
+	// Final take profit price closing whatever remains
+	TakeProfitPrice float64
+	// Break-even stop price, 0 when break-even stops are disabled
+	BreakEvenStop float64
+}
+
+// StopLossPrice returns the stop loss price for an entry, 0 when the stop is disabled
+func (c *Config) StopLossPrice(entryPrice float64, side string) float64 {
+	if c.RiskManagement.StopLossPercentage == 0 {
+		return 0
+	}
+	if isLong(side) {
+		return entryPrice * (1 - c.RiskManagement.StopLossPercentage)
+	}
+	return entryPrice * (1 + c.RiskManagement.StopLossPercentage)
+}
+
+// ExitLevels derives the tier targets, stop and break-even stop from the position's fill price,
+// honouring its absolute stop and take profit overrides
+func (c *Config) ExitLevels(p *Position) ExitLevels {
+	return ExitLevels{
+		TierTargets:     c.MultiTier.TierTargetPrices(p.EntryPrice, p.Side),
+		StopPrice:       c.EffectiveStopPrice(p),
+		TakeProfitPrice: c.EffectiveTakeProfitPrice(p),
+		BreakEvenStop:   c.ForSymbol(p.Symbol).CalculateBreakEvenStop(p.EntryPrice, p.Side),
+	}
+}
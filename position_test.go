@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestPositionUnmarshalBackfillsMissingFilledQuantity(t *testing.T) {
+	var p Position
+	if err := json.Unmarshal([]byte(`{"ID":"old","Quantity":3}`), &p); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if p.FilledQuantity != 3 {
+		t.Errorf("FilledQuantity = %f, want 3 for a position saved before fills were tracked", p.FilledQuantity)
+	}
+}
+
+func TestPositionUnmarshalKeepsUnfilledEntry(t *testing.T) {
+	var p Position
+	if err := json.Unmarshal([]byte(`{"ID":"new","Quantity":3,"FilledQuantity":0}`), &p); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if p.FilledQuantity != 0 || p.ID != "new" {
+		t.Errorf("got %+v, want an unfilled position", p)
+	}
+}
+
+func TestPositionRoundTrip(t *testing.T) {
+	in := Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 3, FilledQuantity: 1.5}
+	data, err := json.Marshal(&in)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var out Position
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if out.FilledQuantity != 1.5 || out.EntryPrice != 100 || out.Symbol != "BNBUSDT" {
+		t.Errorf("round trip got %+v", out)
+	}
+}
+
+func TestExitLevelsDeriveFromFillPrice(t *testing.T) {
+	c := DefaultConfig()
+	c.RiskManagement.StopLossPercentage = 0.02
+	c.RiskManagement.BreakEvenStopEnabled = true
+	c.MultiTier.Tiers = []TierProfit{
+		{ProfitPercentage: 1, ClosePercentage: 0.5, Enabled: true},
+		{ProfitPercentage: 5, ClosePercentage: 0.5, Enabled: true},
+	}
+	// The entry was requested at 100 but slipped to 102
+	p := NewPositionFromFill("p1", SideLong, Fill{Order: Order{Symbol: "BNBUSDT", Quantity: 1, Price: 100}, Quantity: 1, Price: 102})
+
+	levels := c.ExitLevels(p)
+	if math.Abs(levels.StopPrice-99.96) > 1e-9 {
+		t.Errorf("StopPrice = %f, want 99.96", levels.StopPrice)
+	}
+	if math.Abs(levels.TierTargets[0]-103.02) > 1e-9 || math.Abs(levels.TierTargets[1]-107.1) > 1e-9 {
+		t.Errorf("TierTargets = %v, want [103.02 107.1]", levels.TierTargets)
+	}
+	if math.Abs(levels.TakeProfitPrice-107.1) > 1e-9 {
+		t.Errorf("TakeProfitPrice = %f, want the last tier 107.1", levels.TakeProfitPrice)
+	}
+	if want := c.CalculateBreakEvenStop(102, SideLong); levels.BreakEvenStop != want || want <= 102 {
+		t.Errorf("BreakEvenStop = %f, want %f above the fill", levels.BreakEvenStop, want)
+	}
+
+	if err := p.SetAbsoluteLevels(101, 110); err != nil {
+		t.Fatalf("SetAbsoluteLevels: %v", err)
+	}
+	levels = c.ExitLevels(p)
+	if levels.StopPrice != 101 || levels.TakeProfitPrice != 110 {
+		t.Errorf("absolute levels = %f, %f, want 101, 110", levels.StopPrice, levels.TakeProfitPrice)
+	}
+}
+
+func TestEffectiveTakeProfitPriceIsFurthestTarget(t *testing.T) {
+	c := DefaultConfig()
+	// An unsorted absolute tier sits beyond the last percentage tier
+	c.MultiTier.Tiers = []TierProfit{
+		{ProfitPercentage: 1, ClosePercentage: 0.3, Enabled: true},
+		{TargetPrice: 110, ClosePercentage: 0.3, Enabled: true},
+		{ProfitPercentage: 5, ClosePercentage: 0.4, Enabled: true},
+	}
+	long := &Position{Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100}
+	if got := c.EffectiveTakeProfitPrice(long); got != 110 {
+		t.Errorf("long EffectiveTakeProfitPrice = %f, want the highest target 110", got)
+	}
+
+	c.MultiTier.Tiers[1].TargetPrice = 90
+	short := &Position{Symbol: "BNBUSDT", Side: SideShort, EntryPrice: 100}
+	if got := c.EffectiveTakeProfitPrice(short); got != 90 {
+		t.Errorf("short EffectiveTakeProfitPrice = %f, want the lowest target 90", got)
+	}
+}
+
+func TestExecuteNextTierClosesAtAbsoluteTakeProfit(t *testing.T) {
+	executor := &fakeExecutor{fillRatio: 1}
+	bot := newTestBot(t, executor)
+	p := &Position{ID: "p", Symbol: "BNBUSDT", Side: SideShort, EntryPrice: 100, Quantity: 2, FilledQuantity: 2}
+	if err := p.SetAbsoluteLevels(0, 99.5); err != nil {
+		t.Fatalf("SetAbsoluteLevels: %v", err)
+	}
+	bot.TrackPosition(p)
+
+	if fired, err := bot.ExecuteNextTier(context.Background(), "p", 99.8); err != nil || fired {
+		t.Fatalf("ExecuteNextTier above the take profit = %v, %v, want nothing", fired, err)
+	}
+	bot.UpdatePrice("BNBUSDT", 99.4)
+	if fired, err := bot.ExecuteNextTier(context.Background(), "p", 99.4); err != nil || !fired {
+		t.Fatalf("ExecuteNextTier past the take profit = %v, %v, want the position closed", fired, err)
+	}
+	if len(bot.OpenPositions()) != 0 || executor.orders[0].Quantity != 2 {
+		t.Errorf("close orders %+v, want the whole position closed", executor.orders)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxPositionsPolicy decides what happens to copy signals arriving at the position limit
+type MaxPositionsPolicy string
+
+const (
+	// MaxPositionsQueue holds signals until a position closes
+	MaxPositionsQueue MaxPositionsPolicy = "queue"
+	// MaxPositionsDrop discards signals
+	MaxPositionsDrop MaxPositionsPolicy = "drop"
+)
+
+// ParseMaxPositionsPolicy parses a max positions policy case-insensitively
+func ParseMaxPositionsPolicy(s string) (MaxPositionsPolicy, error) {
+	switch policy := MaxPositionsPolicy(strings.ToLower(strings.TrimSpace(s))); policy {
+	case MaxPositionsQueue, MaxPositionsDrop:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown max positions policy %q", s)
+	}
+}
+
+// CanOpenNewPosition reports whether another position may be opened with openCount already open
+func (c *Config) CanOpenNewPosition(openCount int) bool {
+	return openCount < c.RiskManagement.MaxConcurrentPositions
+}
+
+// CopySignal is a leader trade waiting to be copied
+type CopySignal struct {
+	LeaderAddress string
+	Symbol        string
+	Side          string
+	// Leader position quantity and equity used to scale the copy
+	LeaderSize   float64
+	LeaderEquity float64
+	Price        float64
+	ReceivedAt   time.Time
+}
+
+// PositionLimiter caps the number of concurrent positions, queueing or dropping copy signals
+// that arrive at the limit according to MaxPositionsPolicy
+type PositionLimiter struct {
+	mu     sync.Mutex
+	config *Config
+	queue  []CopySignal
+}
+
+// NewPositionLimiter creates a position limiter using the configured limit and policy
+func (c *Config) NewPositionLimiter() *PositionLimiter {
+	return &PositionLimiter{config: c}
+}
+
+// reconfigure switches the limiter to the limit and policy of c, keeping queued signals
+func (l *PositionLimiter) reconfigure(c *Config) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.config = c
+}
+
+// Admit reports whether signal may open a position now with openCount positions open.
+// At the limit the signal is queued under the queue policy and dropped otherwise.
+func (l *PositionLimiter) Admit(signal CopySignal, openCount int) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.config.CanOpenNewPosition(openCount) {
+		return true
+	}
+	if l.config.RiskManagement.MaxPositionsPolicy == MaxPositionsQueue {
+		l.queue = append(l.queue, signal)
+	}
+	return false
+}
+
+// Next returns the oldest queued signal once a position slot is free
+func (l *PositionLimiter) Next(openCount int) (CopySignal, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.config.CanOpenNewPosition(openCount) || len(l.queue) == 0 {
+		return CopySignal{}, false
+	}
+	signal := l.queue[0]
+	l.queue = l.queue[1:]
+	return signal, true
+}
+
+// Queued returns the number of queued signals
+func (l *PositionLimiter) Queued() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.queue)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPositionLimiterQueuesAtLimitUntilReleased(t *testing.T) {
+	c := DefaultConfig()
+	c.RiskManagement.MaxConcurrentPositions = 2
+	c.RiskManagement.MaxPositionsPolicy = MaxPositionsQueue
+	limiter := c.NewPositionLimiter()
+	signal := testSignal()
+
+	if !limiter.Admit(signal, 1) {
+		t.Fatal("Admit refused a signal below the limit")
+	}
+	if limiter.Admit(signal, 2) {
+		t.Fatal("Admit accepted a signal at the limit")
+	}
+	if limiter.Queued() != 1 {
+		t.Fatalf("Queued = %d, want 1", limiter.Queued())
+	}
+	if _, ok := limiter.Next(2); ok {
+		t.Fatal("Next released a signal while still at the limit")
+	}
+	// A close frees a slot
+	got, ok := limiter.Next(1)
+	if !ok || got != signal {
+		t.Fatalf("Next = %+v, %v; want the queued signal", got, ok)
+	}
+	if limiter.Queued() != 0 {
+		t.Errorf("Queued after release = %d, want 0", limiter.Queued())
+	}
+}
+
+func TestPositionLimiterDropsAtLimit(t *testing.T) {
+	c := DefaultConfig()
+	c.RiskManagement.MaxConcurrentPositions = 1
+	c.RiskManagement.MaxPositionsPolicy = MaxPositionsDrop
+	limiter := c.NewPositionLimiter()
+
+	if limiter.Admit(testSignal(), 1) {
+		t.Fatal("Admit accepted a signal at the limit")
+	}
+	if _, ok := limiter.Next(0); ok || limiter.Queued() != 0 {
+		t.Error("dropped signal was queued")
+	}
+}
+
+func TestHandleSignalCopiesQueuedSignalAfterClose(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	config := *bot.config.Load()
+	config.RiskManagement.MaxConcurrentPositions = 1
+	config.RiskManagement.MaxPositionsPolicy = MaxPositionsQueue
+	bot.Reconfigure(&config)
+	ctx := context.Background()
+
+	first, err := bot.HandleSignal(ctx, testSignal())
+	if err != nil || first == nil {
+		t.Fatalf("HandleSignal = %v, %v; want a position", first, err)
+	}
+	queued := testSignal()
+	queued.LeaderAddress = "0xother"
+	if p, err := bot.HandleSignal(ctx, queued); err != nil || p != nil {
+		t.Fatalf("HandleSignal at the limit = %v, %v; want it held back", p, err)
+	}
+	if bot.limiter.Queued() != 1 {
+		t.Fatalf("Queued = %d, want 1", bot.limiter.Queued())
+	}
+
+	if err := bot.HandleQueuedSignals(ctx); err != nil {
+		t.Fatalf("HandleQueuedSignals: %v", err)
+	}
+	if n := len(bot.OpenPositions()); n != 1 {
+		t.Fatalf("open positions before the close = %d, want 1", n)
+	}
+
+	if _, err := bot.ClosePosition(ctx, first.ID, first.Quantity); err != nil {
+		t.Fatalf("ClosePosition: %v", err)
+	}
+	if err := bot.HandleQueuedSignals(ctx); err != nil {
+		t.Fatalf("HandleQueuedSignals: %v", err)
+	}
+	open := bot.OpenPositions()
+	if len(open) != 1 || open[0].LeaderAddress != "0xother" {
+		t.Fatalf("open positions after the close = %+v, want the queued copy", open)
+	}
+	if bot.limiter.Queued() != 0 {
+		t.Errorf("Queued after release = %d, want 0", bot.limiter.Queued())
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// rateLimitWindow is the window Binance request weight limits are measured over
+const rateLimitWindow = time.Minute
+
+// weightedRequest is a request counted against the rate limit
+type weightedRequest struct {
+	at     time.Time
+	weight int
+}
+
+// RateLimiter tracks exchange request weight over a sliding window and delays requests
+// that would exceed the limit
+type RateLimiter struct {
+	mu       sync.Mutex
+	limit    int
+	window   time.Duration
+	requests []weightedRequest
+	used     int
+	now      func() time.Time
+}
+
+// NewRateLimiter creates a rate limiter allowing APIWeightLimit weight per minute
+func (c *Config) NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		limit:  c.Trading.APIWeightLimit,
+		window: rateLimitWindow,
+		now:    time.Now,
+	}
+}
+
+// prune drops requests outside the window; must be called with the lock held
+func (l *RateLimiter) prune(now time.Time) {
+	expired := 0
+	for _, request := range l.requests {
+		if now.Sub(request.at) < l.window {
+			break
+		}
+		l.used -= request.weight
+		expired++
+	}
+	l.requests = l.requests[expired:]
+}
+
+// Wait blocks until a request of weight fits within the limit and records it, returning
+// early when ctx is done
+func (l *RateLimiter) Wait(ctx context.Context, weight int) error {
+	if weight > l.limit {
+		return fmt.Errorf("request weight %d exceeds the limit of %d", weight, l.limit)
+	}
+
+	for {
+		l.mu.Lock()
+		now := l.now()
+		l.prune(now)
+		if l.used+weight <= l.limit {
+			l.requests = append(l.requests, weightedRequest{at: now, weight: weight})
+			l.used += weight
+			l.mu.Unlock()
+			return nil
+		}
+
+		// Wait until enough of the oldest requests leave the window
+		freed := l.limit - l.used
+		var wait time.Duration
+		for _, request := range l.requests {
+			freed += request.weight
+			if freed >= weight {
+				wait = request.at.Add(l.window).Sub(now)
+				break
+			}
+		}
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// RemainingWeight returns the weight still available in the current window
+func (l *RateLimiter) RemainingWeight() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prune(l.now())
+	return l.limit - l.used
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+)
+
+// BalanceFunc fetches the account's total equity in quote currency from the exchange, with
+// held assets and open positions valued at market
+type BalanceFunc func(ctx context.Context) (float64, error)
+
+// EquityLedger is internal equity state the Reconciler checks and may correct. Its equity must
+// value open positions at market like the exchange does, as the Bot's does.
+type EquityLedger interface {
+	Equity() float64
+	SetEquity(equity float64)
+}
+
+// ReconcileResult is the outcome of one reconciliation
+type ReconcileResult struct {
+	Internal float64
+	Exchange float64
+	// Difference between internal and exchange equity as a fraction of the exchange equity
+	Drift     float64
+	Diverged  bool
+	Corrected bool
+}
+
+// Reconciler periodically compares internal equity to the exchange equity, alerting when
+// they drift apart by more than ReconcileTolerance and optionally adopting the exchange value
+type Reconciler struct {
+	fetch       BalanceFunc
+	ledger      EquityLedger
+	notifier    *Notifier
+	tolerance   float64
+	interval    time.Duration
+	autoCorrect bool
+}
+
+// NewReconciler creates a reconciler from the reconcile settings; notifier may be nil to only log
+func (c *Config) NewReconciler(fetch BalanceFunc, ledger EquityLedger, notifier *Notifier) *Reconciler {
+	return &Reconciler{
+		fetch:       fetch,
+		ledger:      ledger,
+		notifier:    notifier,
+		tolerance:   c.ReconcileTolerance,
+		interval:    time.Duration(c.ReconcileInterval) * time.Second,
+		autoCorrect: c.ReconcileAutoCorrect,
+	}
+}
+
+// Check reconciles once, alerting on divergence and correcting the ledger when enabled
+func (r *Reconciler) Check(ctx context.Context) (ReconcileResult, error) {
+	balance, err := r.fetch(ctx)
+	if err != nil {
+		return ReconcileResult{}, fmt.Errorf("error fetching exchange equity: %v", err)
+	}
+
+	result := ReconcileResult{Internal: r.ledger.Equity(), Exchange: balance}
+	if balance > 0 {
+		result.Drift = (result.Internal - balance) / balance
+	} else if result.Internal != 0 {
+		result.Drift = math.Inf(1)
+	}
+	if math.Abs(result.Drift) <= r.tolerance {
+		return result, nil
+	}
+
+	result.Diverged = true
+	message := fmt.Sprintf("⚠️ Internal equity %f differs from exchange equity %f by %.2f%% (tolerance %.2f%%)",
+		result.Internal, balance, result.Drift*100, r.tolerance*100)
+	if r.autoCorrect {
+		r.ledger.SetEquity(balance)
+		result.Corrected = true
+		message += "; internal equity corrected to the exchange equity"
+	}
+	log.Println(message)
+	if r.notifier != nil {
+		if err := r.notifier.Send(ctx, message); err != nil {
+			return result, fmt.Errorf("error sending reconciliation alert: %v", err)
+		}
+	}
+	return result, nil
+}
+
+// Equity returns the capital base's equity plus the unrealized profit of the open positions at
+// their last prices, comparable with the exchange's total equity
+func (b *Bot) Equity() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.capital.Equity() + b.unrealizedPnL()
+}
+
+// SetEquity corrects the capital base so Equity returns equity at the current prices and saves it
+func (b *Bot) SetEquity(equity float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.capital.SetEquity(equity - b.unrealizedPnL())
+	if err := b.saveState(); err != nil {
+		b.logf("Error saving corrected equity: %v", err)
+	}
+}
+
+// unrealizedPnL returns the gross profit of the filled open positions at their last prices.
+// Must be called with mu held.
+func (b *Bot) unrealizedPnL() float64 {
+	var pnl float64
+	for _, p := range b.positions {
+		price, ok := b.lastPrices[p.Symbol]
+		if !ok {
+			continue
+		}
+		move := price - p.EntryPrice
+		if !isBuy(p.Side) {
+			move = -move
+		}
+		pnl += move * p.FilledQuantity
+	}
+	return pnl
+}
+
+// Run reconciles every interval until ctx is done; a zero interval disables it
+func (r *Reconciler) Run(ctx context.Context) {
+	if r.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Check(ctx); err != nil {
+				log.Printf("Error reconciling account equity: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestReconcilerValuesOpenPositionsLikeTheExchange(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	bot.config.Load().ReconcileAutoCorrect = true
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 2, FilledQuantity: 2})
+	bot.UpdatePrice("BNBUSDT", 110)
+
+	// 1000 capital of which 200 is held as 2 BNB now worth 220
+	exchange := 1020.0
+	reconciler := bot.config.Load().NewReconciler(func(ctx context.Context) (float64, error) { return exchange, nil }, bot, nil)
+	result, err := reconciler.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if result.Diverged || result.Internal != 1020 {
+		t.Errorf("result = %+v, want internal equity 1020 matching the exchange", result)
+	}
+
+	// A 30 fee charged outside the bot is adopted into realized PnL, not the open position
+	exchange = 990
+	result, err = reconciler.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if !result.Diverged || !result.Corrected {
+		t.Fatalf("result = %+v, want the drift corrected", result)
+	}
+	if pnl := bot.CapitalBase().RealizedPnL(); math.Abs(pnl+30) > 1e-9 {
+		t.Errorf("realized PnL = %f, want -30", pnl)
+	}
+	if equity := bot.Equity(); math.Abs(equity-990) > 1e-9 {
+		t.Errorf("Equity = %f, want 990", equity)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// reEntrySetup tracks stop-outs and re-entries for a single setup
+type reEntrySetup struct {
+	stoppedOut  bool
+	stoppedAt   time.Time
+	freshSignal bool
+	reEntries   int
+}
+
+// ReEntryGate only allows re-entering a stopped-out setup after a fresh confirming signal,
+// up to a maximum number of re-entries per setup
+type ReEntryGate struct {
+	mu           sync.Mutex
+	maxReEntries int
+	setups       map[string]*reEntrySetup
+	now          func() time.Time
+}
+
+// NewReEntryGate creates a re-entry gate using the configured maximum re-entries
+func (c *Config) NewReEntryGate() *ReEntryGate {
+	return &ReEntryGate{
+		maxReEntries: c.RiskManagement.MaxReEntries,
+		setups:       make(map[string]*reEntrySetup),
+		now:          time.Now,
+	}
+}
+
+// reconfigure switches the gate to the maximum re-entries of c
+func (g *ReEntryGate) reconfigure(c *Config) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.maxReEntries = c.RiskManagement.MaxReEntries
+}
+
+func (g *ReEntryGate) setup(setupID string) *reEntrySetup {
+	s, ok := g.setups[setupID]
+	if !ok {
+		s = &reEntrySetup{}
+		g.setups[setupID] = s
+	}
+	return s
+}
+
+// RecordStopOut marks the setup as stopped out, invalidating any earlier signal
+func (g *ReEntryGate) RecordStopOut(setupID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s := g.setup(setupID)
+	s.stoppedOut = true
+	s.stoppedAt = g.now()
+	s.freshSignal = false
+}
+
+// RecordSignal records a fresh confirming signal for the setup
+func (g *ReEntryGate) RecordSignal(setupID string) {
+	g.RecordSignalAt(setupID, g.now())
+}
+
+// RecordSignalAt records a signal for the setup received at receivedAt, which confirms a
+// re-entry only when it arrived after the last stop-out
+func (g *ReEntryGate) RecordSignalAt(setupID string, receivedAt time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s := g.setup(setupID)
+	if s.stoppedOut && !receivedAt.Before(s.stoppedAt) {
+		s.freshSignal = true
+	}
+}
+
+// CanEnter reports whether the setup may be entered
+func (g *ReEntryGate) CanEnter(setupID string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s, ok := g.setups[setupID]
+	if !ok || !s.stoppedOut {
+		return true
+	}
+	return s.freshSignal && s.reEntries < g.maxReEntries
+}
+
+// RecordEntry records an entry, consuming the fresh signal if it was a re-entry
+func (g *ReEntryGate) RecordEntry(setupID string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	s, ok := g.setups[setupID]
+	if !ok || !s.stoppedOut {
+		return
+	}
+	s.reEntries++
+	s.stoppedOut = false
+	s.freshSignal = false
+}
+
+// reEntrySetupID identifies the setup of a copy: the leader, symbol and position side it
+// follows
+func reEntrySetupID(leader, symbol, side string) string {
+	if isBuy(side) {
+		side = SideLong
+	} else {
+		side = SideShort
+	}
+	return strings.ToLower(leader) + "/" + strings.ToUpper(symbol) + "/" + side
+}
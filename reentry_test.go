@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReEntryGate(t *testing.T) {
+	c := DefaultConfig()
+	c.RiskManagement.MaxReEntries = 1
+	gate := c.NewReEntryGate()
+
+	if !gate.CanEnter("setup") {
+		t.Fatal("fresh setup cannot be entered")
+	}
+	gate.RecordEntry("setup")
+
+	// A signal from before the stop-out does not count as fresh
+	gate.RecordSignal("setup")
+	gate.RecordStopOut("setup")
+	if gate.CanEnter("setup") {
+		t.Fatal("stopped-out setup can be re-entered without a fresh signal")
+	}
+
+	gate.RecordSignal("setup")
+	if !gate.CanEnter("setup") {
+		t.Fatal("stopped-out setup cannot be re-entered after a fresh signal")
+	}
+	gate.RecordEntry("setup")
+
+	gate.RecordStopOut("setup")
+	gate.RecordSignal("setup")
+	if gate.CanEnter("setup") {
+		t.Error("setup can be re-entered beyond the maximum re-entries")
+	}
+	if !gate.CanEnter("other") {
+		t.Error("an unrelated setup is blocked")
+	}
+}
+
+func TestHandleSignalReEntersStoppedOutSetupOnlyOnFreshSignal(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	bot.config.Load().RiskManagement.MaxReEntries = 1
+	stopOut := func(p *Position) {
+		t.Helper()
+		bot.UpdatePrice("BNBUSDT", 290)
+		if err := bot.ManagePosition(context.Background(), p.ID, 290); err != nil || len(bot.OpenPositions()) != 0 {
+			t.Fatalf("ManagePosition = %v, want the position stopped out", err)
+		}
+		// The price returns to the level of the signals
+		bot.UpdatePrice("BNBUSDT", 300)
+	}
+
+	stale := testSignal()
+	stale.ReceivedAt = time.Now()
+	p, err := bot.HandleSignal(context.Background(), testSignal())
+	if err != nil || p == nil {
+		t.Fatalf("HandleSignal = %+v, %v", p, err)
+	}
+	stopOut(p)
+
+	// A signal received before the stop-out does not confirm the setup again
+	if p, err := bot.HandleSignal(context.Background(), stale); err != nil || p != nil {
+		t.Fatalf("HandleSignal with a stale signal = %+v, %v, want it skipped", p, err)
+	}
+	p, err = bot.HandleSignal(context.Background(), testSignal())
+	if err != nil || p == nil {
+		t.Fatalf("HandleSignal with a fresh signal = %+v, %v, want a re-entry", p, err)
+	}
+
+	// The one re-entry is used up
+	stopOut(p)
+	if p, err := bot.HandleSignal(context.Background(), testSignal()); err != nil || p != nil {
+		t.Errorf("HandleSignal past the max re-entries = %+v, %v, want it skipped", p, err)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// adaptiveRefreshSmoothing is the EWMA weight given to the latest price move
+const adaptiveRefreshSmoothing = 0.3
+
+// AdaptiveInterval shortens the refresh interval when prices move fast and lengthens it when calm
+type AdaptiveInterval struct {
+	mu               sync.Mutex
+	min              time.Duration
+	max              time.Duration
+	targetVolatility float64
+	volatility       float64
+	lastPrice        float64
+}
+
+// NewAdaptiveInterval creates an adaptive interval from the configured bounds
+func (c *Config) NewAdaptiveInterval() *AdaptiveInterval {
+	return &AdaptiveInterval{
+		min:              time.Duration(c.MinRefreshInterval) * time.Second,
+		max:              time.Duration(c.MaxRefreshInterval) * time.Second,
+		targetVolatility: c.AdaptiveRefreshVolatility,
+	}
+}
+
+// Update records a new price and returns the interval to wait before the next refresh
+func (a *AdaptiveInterval) Update(price float64) time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.lastPrice > 0 {
+		move := math.Abs(price-a.lastPrice) / a.lastPrice
+		a.volatility = adaptiveRefreshSmoothing*move + (1-adaptiveRefreshSmoothing)*a.volatility
+	}
+	a.lastPrice = price
+
+	return a.interval()
+}
+
+// Interval returns the current refresh interval
+func (a *AdaptiveInterval) Interval() time.Duration {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.interval()
+}
+
+// interval maps the smoothed volatility onto the bounds; without a positive target volatility
+// there is nothing to scale against and the maximum interval is used
+func (a *AdaptiveInterval) interval() time.Duration {
+	if a.targetVolatility <= 0 {
+		return a.max
+	}
+	intensity := math.Min(a.volatility/a.targetVolatility, 1)
+	return a.max - time.Duration(float64(a.max-a.min)*intensity)
+}
+
+// RefreshSchedule picks the wait before each price refresh: RefreshInterval, or with
+// AdaptiveRefresh the shortest AdaptiveInterval across the refreshed symbols, so the fastest
+// moving symbol sets the pace
+type RefreshSchedule struct {
+	config    *Config
+	intervals map[string]*AdaptiveInterval
+}
+
+// NewRefreshSchedule creates a refresh schedule starting from the configured intervals
+func (c *Config) NewRefreshSchedule() *RefreshSchedule {
+	return &RefreshSchedule{config: c, intervals: make(map[string]*AdaptiveInterval)}
+}
+
+// Next records the prices of a refresh under config and returns the interval before the next
+// one. A config other than the previous one, such as after a reload, restarts the adaptive
+// intervals from its bounds.
+func (s *RefreshSchedule) Next(config *Config, prices map[string]float64) time.Duration {
+	if config != s.config {
+		s.config = config
+		s.intervals = make(map[string]*AdaptiveInterval)
+	}
+	if !config.AdaptiveRefresh || len(prices) == 0 {
+		return time.Duration(config.RefreshInterval) * time.Second
+	}
+
+	next := time.Duration(config.MaxRefreshInterval) * time.Second
+	for symbol, price := range prices {
+		interval, ok := s.intervals[symbol]
+		if !ok {
+			interval = config.NewAdaptiveInterval()
+			s.intervals[symbol] = interval
+		}
+		next = min(next, interval.Update(price))
+	}
+	return next
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdaptiveIntervalShortensOnVolatility(t *testing.T) {
+	c := DefaultConfig()
+	c.MinRefreshInterval = 1
+	c.MaxRefreshInterval = 30
+	c.AdaptiveRefreshVolatility = 0.005
+	interval := c.NewAdaptiveInterval()
+
+	if got := interval.Update(100); got != 30*time.Second {
+		t.Errorf("first interval = %v, want the maximum", got)
+	}
+	// A 10% move saturates the smoothed volatility well past the target
+	interval.Update(110)
+	if got := interval.Update(99); got != time.Second {
+		t.Errorf("interval after large moves = %v, want the minimum", got)
+	}
+}
+
+func TestAdaptiveIntervalZeroTargetVolatility(t *testing.T) {
+	c := DefaultConfig()
+	c.MinRefreshInterval = 1
+	c.MaxRefreshInterval = 30
+	c.AdaptiveRefreshVolatility = 0
+	interval := c.NewAdaptiveInterval()
+
+	for _, price := range []float64{100, 100, 105} {
+		if got := interval.Update(price); got != 30*time.Second {
+			t.Errorf("interval with zero target volatility = %v, want the maximum", got)
+		}
+	}
+}
+
+func TestValidateRejectsZeroAdaptiveVolatility(t *testing.T) {
+	c := DefaultConfig()
+	c.AdaptiveRefresh = true
+	c.AdaptiveRefreshVolatility = 0
+	if err := c.Validate(); err == nil {
+		t.Error("expected an error for a zero adaptive refresh volatility")
+	}
+}
+
+func TestRefreshScheduleFollowsFastestSymbol(t *testing.T) {
+	c := DefaultConfig()
+	c.RefreshInterval = 10
+	c.AdaptiveRefresh = true
+	c.MinRefreshInterval = 1
+	c.MaxRefreshInterval = 30
+	c.AdaptiveRefreshVolatility = 0.005
+	schedule := c.NewRefreshSchedule()
+
+	if got := schedule.Next(c, map[string]float64{"BNBUSDT": 300, "ETHUSDT": 2000}); got != 30*time.Second {
+		t.Errorf("first interval = %v, want the maximum", got)
+	}
+	schedule.Next(c, map[string]float64{"BNBUSDT": 330, "ETHUSDT": 2000})
+	if got := schedule.Next(c, map[string]float64{"BNBUSDT": 297, "ETHUSDT": 2000}); got != time.Second {
+		t.Errorf("interval with one volatile symbol = %v, want the minimum", got)
+	}
+
+	// A reloaded config restarts the adaptive intervals from its bounds
+	reloaded := *c
+	reloaded.MaxRefreshInterval = 20
+	if got := schedule.Next(&reloaded, map[string]float64{"BNBUSDT": 297}); got != 20*time.Second {
+		t.Errorf("interval after reload = %v, want the reloaded maximum", got)
+	}
+}
+
+func TestRefreshScheduleFixedInterval(t *testing.T) {
+	c := DefaultConfig()
+	c.RefreshInterval = 7
+	c.AdaptiveRefresh = false
+	schedule := c.NewRefreshSchedule()
+
+	for _, price := range []float64{300, 330, 297} {
+		if got := schedule.Next(c, map[string]float64{"BNBUSDT": price}); got != 7*time.Second {
+			t.Errorf("interval without adaptive refresh = %v, want RefreshInterval", got)
+		}
+	}
+}
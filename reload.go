@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"github.com/joho/godotenv"
+)
+
+// nonReloadableFields are the settings that cannot change without a restart
+var nonReloadableFields = []struct {
+	name  string
+	value func(c *Config) interface{}
+}{
+	{"Trading.TradingPair", func(c *Config) interface{} { return c.Trading.TradingPair }},
+	{"Trading.TradingPairs", func(c *Config) interface{} { return c.Trading.TradingPairs }},
+	{"Trading.APIKey", func(c *Config) interface{} { return c.Trading.APIKey }},
+	{"Trading.APISecret", func(c *Config) interface{} { return c.Trading.APISecret }},
+	{"Trading.TestnetEnabled", func(c *Config) interface{} { return c.Trading.TestnetEnabled }},
+	{"Logging.LogFilePath", func(c *Config) interface{} { return c.Logging.LogFilePath }},
+	{"Trading.UseOCOExits", func(c *Config) interface{} { return c.Trading.UseOCOExits }},
+	{"FuturesMode", func(c *Config) interface{} { return c.FuturesMode }},
+	{"MarketDataMode", func(c *Config) interface{} { return c.MarketDataMode }},
+	{"ExecutionMode", func(c *Config) interface{} { return c.ExecutionMode }},
+	{"DryRun", func(c *Config) interface{} { return c.DryRun }},
+	{"PaperTrading.Enabled", func(c *Config) interface{} { return c.PaperTrading.Enabled }},
+	{"CopyTrading.Enabled", func(c *Config) interface{} { return c.CopyTrading.Enabled }},
+	{"EntryImprovement.Enabled", func(c *Config) interface{} { return c.EntryImprovement.Enabled }},
+	{"DeadMansSwitchEnabled", func(c *Config) interface{} { return c.DeadMansSwitchEnabled }},
+	{"StateBackend", func(c *Config) interface{} { return c.StateBackend }},
+	{"StateDir", func(c *Config) interface{} { return c.StateDir }},
+	{"TradeHistoryPath", func(c *Config) interface{} { return c.TradeHistoryPath }},
+	{"MetricsEnabled", func(c *Config) interface{} { return c.MetricsEnabled }},
+	{"MetricsPort", func(c *Config) interface{} { return c.MetricsPort }},
+	{"HealthEnabled", func(c *Config) interface{} { return c.HealthEnabled }},
+	{"HealthPort", func(c *Config) interface{} { return c.HealthPort }},
+	{"ControlToken", func(c *Config) interface{} { return c.ControlToken }},
+	{"BSC.NodeURL", func(c *Config) interface{} { return c.BSC.NodeURL }},
+	{"BSC.TestnetURL", func(c *Config) interface{} { return c.BSC.TestnetURL }},
+	{"BSC.Testnet", func(c *Config) interface{} { return c.BSC.Testnet }},
+	{"BSC.MasterWalletAddress", func(c *Config) interface{} { return c.BSC.MasterWalletAddress }},
+	{"BSC.FollowerPrivateKey", func(c *Config) interface{} { return c.BSC.FollowerPrivateKey }},
+}
+
+// checkReloadable returns an error naming every non-reloadable field that differs between old and updated
+func checkReloadable(old, updated *Config) error {
+	var changed []string
+	for _, field := range nonReloadableFields {
+		if !reflect.DeepEqual(field.value(old), field.value(updated)) {
+			changed = append(changed, field.name)
+		}
+	}
+	if len(changed) > 0 {
+		return fmt.Errorf("cannot reload changes to %s without a restart", strings.Join(changed, ", "))
+	}
+	return nil
+}
+
+// LiveConfig holds the active configuration and swaps it atomically on reload
+type LiveConfig struct {
+	// Serializes reloads
+	mu      sync.Mutex
+	current atomic.Pointer[Config]
+	load    func() (*Config, error)
+	// .env values applied to the environment by the last load
+	dotEnv map[string]string
+}
+
+// NewLiveConfig creates a live configuration starting from initial and reloaded with load,
+// such as LoadConfig
+func NewLiveConfig(initial *Config, load func() (*Config, error)) *LiveConfig {
+	l := &LiveConfig{load: load}
+	l.current.Store(initial)
+	l.dotEnv, _ = godotenv.Read()
+	return l
+}
+
+// Get returns the active configuration; callers must not modify it
+func (l *LiveConfig) Get() *Config {
+	return l.current.Load()
+}
+
+// ReloadConfig reloads the configuration and swaps it in when it is valid and changes no
+// non-reloadable field. On error the active configuration and the environment it was loaded
+// from are kept.
+func (l *LiveConfig) ReloadConfig() (*Config, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rollback := l.refreshDotEnv()
+	config, err := l.load()
+	if err != nil {
+		rollback()
+		return nil, fmt.Errorf("error reloading config: %v", err)
+	}
+	if err := checkReloadable(l.Get(), config); err != nil {
+		rollback()
+		return nil, err
+	}
+
+	for _, change := range l.Get().Diff(config) {
+		log.Printf("Config reloaded: %s", change)
+	}
+	l.current.Store(config)
+	return config, nil
+}
+
+// refreshDotEnv re-applies the .env file. godotenv.Load never overrides variables that are
+// already set, so variables still holding the value from the previous .env are replaced while
+// variables set by the process environment are kept. It returns a function that restores the
+// variables it changed, for a reload that is rejected.
+func (l *LiveConfig) refreshDotEnv() (rollback func()) {
+	dotEnv, err := godotenv.Read()
+	if err != nil {
+		return func() {}
+	}
+
+	previousDotEnv := l.dotEnv
+	saved := make(map[string]*string)
+	save := func(key string) {
+		if _, ok := saved[key]; ok {
+			return
+		}
+		if value, set := os.LookupEnv(key); set {
+			saved[key] = &value
+		} else {
+			saved[key] = nil
+		}
+	}
+	for key, value := range dotEnv {
+		current, set := os.LookupEnv(key)
+		if previous, fromDotEnv := l.dotEnv[key]; !set || (fromDotEnv && current == previous) {
+			save(key)
+			os.Setenv(key, value)
+		}
+	}
+	for key, previous := range l.dotEnv {
+		if _, ok := dotEnv[key]; !ok && os.Getenv(key) == previous {
+			save(key)
+			os.Unsetenv(key)
+		}
+	}
+	l.dotEnv = dotEnv
+
+	return func() {
+		for key, value := range saved {
+			if value == nil {
+				os.Unsetenv(key)
+			} else {
+				os.Setenv(key, *value)
+			}
+		}
+		l.dotEnv = previousDotEnv
+	}
+}
+
+// Reconfigure switches the bot and the components it built from its configuration to config,
+// such as one reloaded by LiveConfig. Open positions keep the exits and tiers they were opened
+// with, and entry filters keep their history unless their settings changed.
+func (b *Bot) Reconfigure(config *Config) {
+	previous := b.config.Swap(config)
+	if executor, ok := b.executor.(*filteredExecutor); ok {
+		executor.reconfigure(config)
+	}
+	b.daily.reconfigure(config)
+	b.drawdown.reconfigure(config)
+	b.protector.reconfigure(config)
+	b.cooldowns.reconfigure(config)
+	b.reentry.reconfigure(config)
+	b.softLoss.reconfigure(config)
+	b.leaders.reconfigure(config)
+	b.capital.reconfigure(config.FixedCapital)
+	b.fees.reconfigure(config)
+	b.limiter.reconfigure(config)
+	b.throttle.reconfigure(config)
+	b.watchdog.reconfigure(config)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.notifier = config.NewNotifier()
+	for symbol, pair := range b.pairs {
+		pair.losses.reconfigure(config.ForSymbol(symbol))
+	}
+	if !reflect.DeepEqual(previous.EntryFilters, config.EntryFilters) {
+		// Filters with new periods start over from the next refreshed prices
+		b.filters = config.NewCopyFilters()
+	}
+	if b.oco != nil {
+		b.oco.reconfigure(config)
+	}
+	if b.deadman != nil {
+		b.deadman.reconfigure(config)
+	}
+	if b.improver != nil {
+		b.improver = config.NewEntryImprover(b.improver.executor, b.improver.orders)
+	}
+}
+
+// WatchSIGHUP reloads the configuration on every SIGHUP until ctx is done, reporting each
+// outcome to notify
+func (l *LiveConfig) WatchSIGHUP(ctx context.Context, notify func(*Config, error)) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			notify(l.ReloadConfig())
+		}
+	}
+}
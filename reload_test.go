@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestReloadConfigRestoresEnvironmentWhenRejected(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile(".env", []byte("RELOAD_TEST_SETTING=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// As applied by godotenv.Load at startup
+	t.Setenv("RELOAD_TEST_SETTING", "1")
+
+	var loadErr error
+	var loaded string
+	live := NewLiveConfig(DefaultConfig(), func() (*Config, error) {
+		loaded = os.Getenv("RELOAD_TEST_SETTING")
+		return DefaultConfig(), loadErr
+	})
+	if err := os.WriteFile(".env", []byte("RELOAD_TEST_SETTING=2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	loadErr = errors.New("invalid configuration")
+	if _, err := live.ReloadConfig(); err == nil {
+		t.Fatal("expected the failed load to reject the reload")
+	}
+	if loaded != "2" || os.Getenv("RELOAD_TEST_SETTING") != "1" {
+		t.Errorf("load saw %q and the environment holds %q after the rejected reload, want 2 then 1 restored", loaded, os.Getenv("RELOAD_TEST_SETTING"))
+	}
+
+	loadErr = nil
+	if _, err := live.ReloadConfig(); err != nil {
+		t.Fatalf("ReloadConfig: %v", err)
+	}
+	if got := os.Getenv("RELOAD_TEST_SETTING"); got != "2" {
+		t.Errorf("RELOAD_TEST_SETTING = %q after a successful reload, want the new .env value 2", got)
+	}
+}
+
+func TestReloadConfigRestoresEnvironmentOnNonReloadableChange(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile(".env", []byte("RELOAD_TEST_PAIR=BNBUSDT\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("RELOAD_TEST_PAIR", "BNBUSDT")
+
+	live := NewLiveConfig(DefaultConfig(), func() (*Config, error) {
+		c := DefaultConfig()
+		c.Trading.TradingPair = os.Getenv("RELOAD_TEST_PAIR")
+		return c, nil
+	})
+	if err := os.WriteFile(".env", []byte("RELOAD_TEST_PAIR=ETHUSDT\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := live.ReloadConfig(); err == nil {
+		t.Fatal("expected a changed trading pair to reject the reload")
+	}
+	if got := os.Getenv("RELOAD_TEST_PAIR"); got != "BNBUSDT" {
+		t.Errorf("RELOAD_TEST_PAIR = %q after the rejected reload, want BNBUSDT restored", got)
+	}
+}
+
+func TestBotReconfigureAppliesReloadedPositionLimit(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	limited := *bot.config.Load()
+	limited.RiskManagement.MaxConcurrentPositions = 1
+	bot.Reconfigure(&limited)
+
+	if _, err := bot.HandleSignal(context.Background(), testSignal()); err != nil {
+		t.Fatalf("HandleSignal: %v", err)
+	}
+	second := testSignal()
+	second.LeaderAddress = "0xother"
+	if p, _ := bot.HandleSignal(context.Background(), second); p != nil {
+		t.Fatal("expected the second copy to be held back at the limit of 1")
+	}
+
+	raised := limited
+	raised.RiskManagement.MaxConcurrentPositions = 2
+	bot.Reconfigure(&raised)
+	if p, err := bot.HandleSignal(context.Background(), second); err != nil || p == nil {
+		t.Fatalf("HandleSignal after raising the limit = %v, %v; want the copy opened", p, err)
+	}
+	if len(bot.OpenPositions()) != 2 {
+		t.Errorf("%d open positions, want 2 under the reloaded limit", len(bot.OpenPositions()))
+	}
+}
+
+func TestReloadConfigRejectsExecutionChanges(t *testing.T) {
+	for name, change := range map[string]func(c *Config){
+		"execution mode":    func(c *Config) { c.ExecutionMode = ExecutionObserve },
+		"dry run":           func(c *Config) { c.DryRun = true },
+		"paper trading":     func(c *Config) { c.PaperTrading.Enabled = true },
+		"OCO exits":         func(c *Config) { c.Trading.UseOCOExits = true },
+		"dead man's switch": func(c *Config) { c.DeadMansSwitchEnabled = true },
+	} {
+		t.Run(name, func(t *testing.T) {
+			live := NewLiveConfig(DefaultConfig(), func() (*Config, error) {
+				c := DefaultConfig()
+				change(c)
+				return c, nil
+			})
+			if _, err := live.ReloadConfig(); err == nil {
+				t.Fatal("expected the reload to be rejected until a restart")
+			}
+			if live.Get().ExecutionMode != ExecutionLive || live.Get().DryRun {
+				t.Error("the rejected configuration was swapped in")
+			}
+		})
+	}
+}
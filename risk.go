@@ -0,0 +1,306 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// LossTracker counts consecutive losing trades and pauses trading for PauseDuration
+// minutes once MaxConsecutiveLosses is reached
+type LossTracker struct {
+	mu                sync.Mutex
+	maxLosses         int
+	pauseDuration     time.Duration
+	consecutiveLosses int
+	resumeAt          time.Time
+	now               func() time.Time
+}
+
+// NewLossTracker creates a loss tracker from the risk management configuration
+func (c *Config) NewLossTracker() *LossTracker {
+	return &LossTracker{
+		maxLosses:     c.RiskManagement.MaxConsecutiveLosses,
+		pauseDuration: time.Duration(c.RiskManagement.PauseDuration) * time.Minute,
+		now:           time.Now,
+	}
+}
+
+// reconfigure switches the tracker to the loss limit and pause duration of c
+func (t *LossTracker) reconfigure(c *Config) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.maxLosses = c.RiskManagement.MaxConsecutiveLosses
+	t.pauseDuration = time.Duration(c.RiskManagement.PauseDuration) * time.Minute
+}
+
+// RecordTrade records a closed trade; a profit resets the consecutive loss count
+func (t *LossTracker) RecordTrade(profit float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if profit >= 0 {
+		t.consecutiveLosses = 0
+		return
+	}
+
+	t.consecutiveLosses++
+	if t.consecutiveLosses >= t.maxLosses {
+		t.resumeAt = t.now().Add(t.pauseDuration)
+		t.consecutiveLosses = 0
+	}
+}
+
+// ShouldPause reports whether trading is paused and when it resumes
+func (t *LossTracker) ShouldPause() (bool, time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.now().Before(t.resumeAt) {
+		return true, t.resumeAt
+	}
+	return false, time.Time{}
+}
+
+// ConsecutiveLosses returns the current consecutive loss count
+func (t *LossTracker) ConsecutiveLosses() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.consecutiveLosses
+}
+
+// Restore sets the consecutive loss count from persisted state
+func (t *LossTracker) Restore(consecutiveLosses int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.consecutiveLosses = consecutiveLosses
+}
+
+// sessionAnchorStateKey is the state store key of the session-start equity anchor
+const sessionAnchorStateKey = "session_anchor"
+
+// SessionAnchor is the equity snapshot taken at the first tick of a trading day
+type SessionAnchor struct {
+	PeriodStart    time.Time
+	StartingEquity float64
+}
+
+// DailyLossGuard tracks the day's starting equity, rolling over at a configured UTC hour,
+// and stops trading once the day's loss exceeds MaxDailyLossPercentage
+type DailyLossGuard struct {
+	mu             sync.Mutex
+	config         *Config
+	resetHour      int
+	store          StateStore
+	periodStart    time.Time
+	startingEquity float64
+}
+
+// NewDailyLossGuard creates a daily loss guard resetting at DailyResetHourUTC
+func (c *Config) NewDailyLossGuard() *DailyLossGuard {
+	return &DailyLossGuard{
+		config:    c,
+		resetHour: c.RiskManagement.DailyResetHourUTC,
+	}
+}
+
+// NewPersistentDailyLossGuard creates a daily loss guard whose session-start equity anchor
+// is persisted in store, so restarts within the same day keep the original anchor. A nil
+// store keeps the anchor in memory only.
+func (c *Config) NewPersistentDailyLossGuard(store StateStore) *DailyLossGuard {
+	g := c.NewDailyLossGuard()
+	g.store = store
+	return g
+}
+
+// reconfigure switches the guard to the loss limit and reset hour of c
+func (g *DailyLossGuard) reconfigure(c *Config) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.config = c
+	g.resetHour = c.RiskManagement.DailyResetHourUTC
+}
+
+// currentPeriodStart returns the most recent reset boundary at or before now
+func (g *DailyLossGuard) currentPeriodStart(now time.Time) time.Time {
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), now.Day(), g.resetHour, 0, 0, 0, time.UTC)
+	if now.Before(start) {
+		start = start.AddDate(0, 0, -1)
+	}
+	return start
+}
+
+// CanTrade reports whether trading may continue, anchoring the starting equity to the
+// first equity seen after each reset boundary
+func (g *DailyLossGuard) CanTrade(now time.Time, currentEquity float64) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if periodStart := g.currentPeriodStart(now); !periodStart.Equal(g.periodStart) {
+		g.periodStart = periodStart
+		g.startingEquity = g.anchor(periodStart, currentEquity)
+	}
+
+	return g.config.IsWithinDailyLossLimit(g.startingEquity, currentEquity)
+}
+
+// StartingEquity returns the starting equity of the current day
+func (g *DailyLossGuard) StartingEquity() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.startingEquity
+}
+
+// Anchor returns the current day's session anchor
+func (g *DailyLossGuard) Anchor() SessionAnchor {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return SessionAnchor{PeriodStart: g.periodStart, StartingEquity: g.startingEquity}
+}
+
+// anchor returns the persisted starting equity for the period, snapshotting currentEquity
+// when no anchor exists yet for it
+func (g *DailyLossGuard) anchor(periodStart time.Time, currentEquity float64) float64 {
+	if g.store == nil {
+		return currentEquity
+	}
+
+	var saved SessionAnchor
+	err := g.store.Load(sessionAnchorStateKey, &saved)
+	if err == nil && saved.PeriodStart.Equal(periodStart) {
+		return saved.StartingEquity
+	}
+	if err != nil && !errors.Is(err, ErrStateNotFound) {
+		log.Printf("Error loading session anchor: %v", err)
+	}
+
+	anchor := SessionAnchor{PeriodStart: periodStart, StartingEquity: currentEquity}
+	if err := g.store.Save(sessionAnchorStateKey, anchor); err != nil {
+		log.Printf("Error saving session anchor: %v", err)
+	}
+	return currentEquity
+}
+
+// DrawdownMonitor remembers the all-time peak equity and measures drawdown from it
+type DrawdownMonitor struct {
+	mu       sync.Mutex
+	config   *Config
+	peak     float64
+	peakTime time.Time
+	current  float64
+	now      func() time.Time
+}
+
+// NewDrawdownMonitor creates a drawdown monitor using MaxDrawdownPercentage
+func (c *Config) NewDrawdownMonitor() *DrawdownMonitor {
+	return &DrawdownMonitor{
+		config: c,
+		now:    time.Now,
+	}
+}
+
+// reconfigure switches the monitor to the drawdown limit of c
+func (m *DrawdownMonitor) reconfigure(c *Config) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config = c
+}
+
+// Record records the current equity, updating the peak when exceeded
+func (m *DrawdownMonitor) Record(equity float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.current = equity
+	if equity > m.peak {
+		m.peak = equity
+		m.peakTime = m.now()
+	}
+}
+
+// CurrentDrawdown returns the drawdown from the peak as a fraction
+func (m *DrawdownMonitor) CurrentDrawdown() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.peak <= 0 {
+		return 0
+	}
+	return (m.peak - m.current) / m.peak
+}
+
+// WithinLimit reports whether the current drawdown is within MaxDrawdownPercentage
+func (m *DrawdownMonitor) WithinLimit() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.config.IsWithinDrawdownLimit(m.peak, m.current)
+}
+
+// Peak returns the peak equity and when it was reached
+func (m *DrawdownMonitor) Peak() (float64, time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.peak, m.peakTime
+}
+
+// Restore sets the peak equity from persisted state
+func (m *DrawdownMonitor) Restore(peak float64, peakTime time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.peak = peak
+	m.peakTime = peakTime
+}
+
+// EquityProtector halts new trades when equity falls below MinimumEquityLevel and stays
+// halted until equity recovers above the level plus MinimumEquityRecoveryMargin
+type EquityProtector struct {
+	mu             sync.Mutex
+	enabled        bool
+	minimum        float64
+	recoveryMargin float64
+	halted         bool
+}
+
+// NewEquityProtector creates an equity protector from the risk management configuration
+func (c *Config) NewEquityProtector() *EquityProtector {
+	return &EquityProtector{
+		enabled:        c.RiskManagement.EquityProtectionEnabled,
+		minimum:        c.RiskManagement.MinimumEquityLevel,
+		recoveryMargin: c.RiskManagement.MinimumEquityRecoveryMargin,
+	}
+}
+
+// reconfigure switches the protector to the equity protection settings of c, keeping a halt
+// in force until equity recovers under the new settings
+func (p *EquityProtector) reconfigure(c *Config) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.enabled = c.RiskManagement.EquityProtectionEnabled
+	p.minimum = c.RiskManagement.MinimumEquityLevel
+	p.recoveryMargin = c.RiskManagement.MinimumEquityRecoveryMargin
+}
+
+// Check reports whether new trades are allowed at currentEquity and why not
+func (p *EquityProtector) Check(currentEquity float64) (bool, string) {
+	if !p.enabled {
+		return true, ""
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	recoveryLevel := p.minimum * (1 + p.recoveryMargin)
+	switch {
+	case currentEquity < p.minimum:
+		p.halted = true
+		return false, fmt.Sprintf("equity %.2f is below minimum equity level %.2f", currentEquity, p.minimum)
+	case p.halted && currentEquity <= recoveryLevel:
+		return false, fmt.Sprintf("equity %.2f has not recovered above %.2f", currentEquity, recoveryLevel)
+	default:
+		p.halted = false
+		return true, ""
+	}
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPersistentDailyLossGuardKeepsAnchorAcrossRestart(t *testing.T) {
+	store, err := NewFileStateStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStateStore: %v", err)
+	}
+	c := DefaultConfig()
+	c.RiskManagement.DailyResetHourUTC = 0
+	morning := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	first := c.NewPersistentDailyLossGuard(store)
+	first.CanTrade(morning, 1000)
+
+	// Restart mid-day after losing equity
+	restarted := c.NewPersistentDailyLossGuard(store)
+	restarted.CanTrade(morning.Add(5*time.Hour), 900)
+	if got := restarted.StartingEquity(); got != 1000 {
+		t.Errorf("starting equity after restart = %f, want the morning anchor 1000", got)
+	}
+
+	// The next day takes a new anchor
+	restarted.CanTrade(morning.Add(24*time.Hour), 950)
+	if got := restarted.StartingEquity(); got != 950 {
+		t.Errorf("starting equity next day = %f, want 950", got)
+	}
+}
+
+func TestIsWithinDailyLossLimit(t *testing.T) {
+	c := DefaultConfig()
+	c.RiskManagement.MaxDailyLossPercentage = 0.05
+	tests := []struct {
+		name     string
+		starting float64
+		current  float64
+		want     bool
+	}{
+		{"profit", 1000, 1100, true},
+		{"loss within limit", 1000, 960, true},
+		{"loss at limit", 1000, 950, true},
+		{"loss beyond limit", 1000, 940, false},
+		{"zero starting equity", 0, 100, false},
+		{"negative starting equity", -10, 100, false},
+	}
+	for _, tt := range tests {
+		if got := c.IsWithinDailyLossLimit(tt.starting, tt.current); got != tt.want {
+			t.Errorf("%s: IsWithinDailyLossLimit = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDailyLossGuardResetsAtBoundary(t *testing.T) {
+	c := DefaultConfig()
+	c.RiskManagement.MaxDailyLossPercentage = 0.05
+	c.RiskManagement.DailyResetHourUTC = 8
+	guard := c.NewDailyLossGuard()
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	if !guard.CanTrade(start, 1000) {
+		t.Fatal("guard refused trading at the day's first tick")
+	}
+	// Loss before the boundary, across midnight, still counts against the same day
+	if guard.CanTrade(start.Add(20*time.Hour), 900) {
+		t.Error("guard allowed trading after a 10% loss before the reset")
+	}
+	// After the 08:00 boundary the day restarts from the current equity
+	if !guard.CanTrade(start.Add(23*time.Hour+30*time.Minute), 900) {
+		t.Error("guard refused trading after the reset boundary")
+	}
+	if got := guard.StartingEquity(); got != 900 {
+		t.Errorf("starting equity after reset = %f, want 900", got)
+	}
+	if guard.CanTrade(start.Add(24*time.Hour), 850) {
+		t.Error("guard allowed trading after a further 5.6% loss in the new day")
+	}
+}
+
+func TestLossTrackerPausesAfterConsecutiveLosses(t *testing.T) {
+	c := DefaultConfig()
+	c.RiskManagement.MaxConsecutiveLosses = 3
+	c.RiskManagement.PauseDuration = 30
+	tracker := c.NewLossTracker()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	tracker.RecordTrade(-1)
+	tracker.RecordTrade(-1)
+	// A win resets the count
+	tracker.RecordTrade(2)
+	tracker.RecordTrade(-1)
+	tracker.RecordTrade(-1)
+	if paused, _ := tracker.ShouldPause(); paused {
+		t.Fatal("paused after two consecutive losses")
+	}
+	if tracker.ConsecutiveLosses() != 2 {
+		t.Errorf("ConsecutiveLosses() = %d, want 2", tracker.ConsecutiveLosses())
+	}
+
+	tracker.RecordTrade(-1)
+	paused, resumeAt := tracker.ShouldPause()
+	if !paused || !resumeAt.Equal(now.Add(30*time.Minute)) {
+		t.Fatalf("ShouldPause() = %v, %v, want paused until %v", paused, resumeAt, now.Add(30*time.Minute))
+	}
+	if tracker.ConsecutiveLosses() != 0 {
+		t.Errorf("ConsecutiveLosses() after pausing = %d, want 0", tracker.ConsecutiveLosses())
+	}
+
+	now = now.Add(30 * time.Minute)
+	if paused, _ := tracker.ShouldPause(); paused {
+		t.Error("still paused after the pause duration")
+	}
+}
+
+func TestRestartedDailyLossGuardEnforcesLimitFromSessionAnchor(t *testing.T) {
+	store, err := NewFileStateStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStateStore: %v", err)
+	}
+	c := DefaultConfig()
+	c.RiskManagement.MaxDailyLossPercentage = 0.05
+	c.RiskManagement.DailyResetHourUTC = 6
+	firstTick := time.Date(2024, 3, 1, 7, 15, 0, 0, time.UTC)
+
+	if !c.NewPersistentDailyLossGuard(store).CanTrade(firstTick, 1000) {
+		t.Fatal("first tick refused")
+	}
+
+	// 930 is within 5% of itself, so recomputing the anchor would let trading continue
+	restarted := c.NewPersistentDailyLossGuard(store)
+	if restarted.CanTrade(firstTick.Add(3*time.Hour), 930) {
+		t.Error("restarted guard allowed a 7% loss from the session anchor")
+	}
+	want := SessionAnchor{PeriodStart: time.Date(2024, 3, 1, 6, 0, 0, 0, time.UTC), StartingEquity: 1000}
+	if got := restarted.Anchor(); !got.PeriodStart.Equal(want.PeriodStart) || got.StartingEquity != want.StartingEquity {
+		t.Errorf("Anchor() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDrawdownMonitorTracksPeak(t *testing.T) {
+	c := DefaultConfig()
+	c.RiskManagement.MaxDrawdownPercentage = 0.1
+	monitor := c.NewDrawdownMonitor()
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	monitor.now = func() time.Time { return now }
+
+	for _, equity := range []float64{1000, 1100, 1200} {
+		monitor.Record(equity)
+		now = now.Add(time.Hour)
+	}
+	peakTime := now.Add(-time.Hour)
+	for _, equity := range []float64{1150, 1100} {
+		monitor.Record(equity)
+		now = now.Add(time.Hour)
+	}
+
+	if peak, at := monitor.Peak(); peak != 1200 || !at.Equal(peakTime) {
+		t.Errorf("Peak() = %f at %v, want 1200 at %v", peak, at, peakTime)
+	}
+	if got := monitor.CurrentDrawdown(); math.Abs(got-100.0/1200) > 1e-9 {
+		t.Errorf("CurrentDrawdown() = %f, want %f", got, 100.0/1200)
+	}
+	if !monitor.WithinLimit() {
+		t.Error("8.3% drawdown exceeds the 10% limit")
+	}
+
+	monitor.Record(1070)
+	if monitor.WithinLimit() {
+		t.Errorf("drawdown %f within the 10%% limit", monitor.CurrentDrawdown())
+	}
+}
+
+func TestEquityProtectorLatchesUntilRecoveryMargin(t *testing.T) {
+	c := DefaultConfig()
+	c.RiskManagement.EquityProtectionEnabled = true
+	c.RiskManagement.MinimumEquityLevel = 1000
+	c.RiskManagement.MinimumEquityRecoveryMargin = 0.05
+	protector := c.NewEquityProtector()
+
+	steps := []struct {
+		equity float64
+		want   bool
+	}{
+		{1000, true},
+		{999, false},
+		// Back above the minimum but not past 1050
+		{1020, false},
+		{1050, false},
+		{1051, true},
+		{1001, true},
+	}
+	for _, step := range steps {
+		if ok, reason := protector.Check(step.equity); ok != step.want {
+			t.Errorf("Check(%f) = %v (%s), want %v", step.equity, ok, reason, step.want)
+		}
+	}
+
+	c.RiskManagement.EquityProtectionEnabled = false
+	if ok, _ := c.NewEquityProtector().Check(0); !ok {
+		t.Error("disabled equity protection refused trading")
+	}
+}
+
+func TestEquityProtectionHoldsEntriesAfterLosingClose(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	config := bot.config.Load()
+	config.RiskManagement.EquityProtectionEnabled = true
+	config.RiskManagement.MinimumEquityLevel = 900
+	config.RiskManagement.MinimumEquityRecoveryMargin = 0.05
+	config.RiskManagement.MaxDailyLossPercentage = 1
+	config.RiskManagement.DrawdownMonitoringEnabled = false
+	bot.Reconfigure(config)
+	bot.TrackPosition(&Position{ID: "p", Symbol: "ETHUSDT", Side: SideLong, EntryPrice: 100, Quantity: 2, FilledQuantity: 2})
+
+	// Closing at a 150 loss takes equity from 1000 to 850
+	bot.UpdatePrice("ETHUSDT", 25)
+	if _, err := bot.ClosePosition(context.Background(), "p", math.Inf(1)); err != nil {
+		t.Fatalf("ClosePosition: %v", err)
+	}
+	if bot.AllowEntry(testSignal()) {
+		t.Fatal("AllowEntry admitted a signal below the minimum equity level")
+	}
+	bot.capital.RecordRealizedPnL(80)
+	if bot.AllowEntry(testSignal()) {
+		t.Error("AllowEntry admitted a signal before equity recovered past the margin")
+	}
+	bot.capital.RecordRealizedPnL(20)
+	if !bot.AllowEntry(testSignal()) {
+		t.Error("AllowEntry refused a signal after equity recovered past the margin")
+	}
+}
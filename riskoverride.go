@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RiskOverride replaces selected RiskManagement settings for one symbol; nil fields fall back
+// to the global value
+type RiskOverride struct {
+	MaxRiskPercentage  *float64
+	StopLossPercentage *float64
+	ATRStopMultiplier  *float64
+	SoftLossPercentage *float64
+	BreakEvenThreshold *float64
+	MaxPositionSize    *float64
+}
+
+// riskOverrideField maps a RISK_OVERRIDE_<SYMBOL>_<FIELD> suffix to its override and base fields
+type riskOverrideField struct {
+	suffix   string
+	override func(o *RiskOverride) **float64
+	base     func(r *RiskManagementConfig) *float64
+}
+
+// riskOverrideFields lists the overridable settings, named after their global env variables
+var riskOverrideFields = []riskOverrideField{
+	{"MAX_RISK_PERCENT", func(o *RiskOverride) **float64 { return &o.MaxRiskPercentage }, func(r *RiskManagementConfig) *float64 { return &r.MaxRiskPercentage }},
+	{"STOP_LOSS_PERCENT", func(o *RiskOverride) **float64 { return &o.StopLossPercentage }, func(r *RiskManagementConfig) *float64 { return &r.StopLossPercentage }},
+	{"ATR_STOP_MULTIPLIER", func(o *RiskOverride) **float64 { return &o.ATRStopMultiplier }, func(r *RiskManagementConfig) *float64 { return &r.ATRStopMultiplier }},
+	{"SOFT_LOSS_PERCENT", func(o *RiskOverride) **float64 { return &o.SoftLossPercentage }, func(r *RiskManagementConfig) *float64 { return &r.SoftLossPercentage }},
+	{"BREAK_EVEN_THRESHOLD", func(o *RiskOverride) **float64 { return &o.BreakEvenThreshold }, func(r *RiskManagementConfig) *float64 { return &r.BreakEvenThreshold }},
+	{"MAX_POSITION_SIZE", func(o *RiskOverride) **float64 { return &o.MaxPositionSize }, func(r *RiskManagementConfig) *float64 { return &r.MaxPositionSize }},
+}
+
+// String lists the overridden settings by their env suffix, so overrides print readably in
+// summaries and diffs instead of as pointers
+func (o RiskOverride) String() string {
+	var set []string
+	for _, field := range riskOverrideFields {
+		if value := *field.override(&o); value != nil {
+			set = append(set, field.suffix+"="+formatEnvFloat(*value))
+		}
+	}
+	return "{" + strings.Join(set, " ") + "}"
+}
+
+// riskOverrideEnvKey returns the env variable overriding field for symbol
+func riskOverrideEnvKey(symbol, suffix string) string {
+	return "RISK_OVERRIDE_" + symbol + "_" + suffix
+}
+
+// loadRiskOverrides reads the RISK_OVERRIDE_<SYMBOL>_<FIELD> variables of every pair,
+// rejecting values that are not numbers
+func (c *Config) loadRiskOverrides() error {
+	for _, symbol := range c.Pairs() {
+		override := c.RiskManagement.SymbolOverrides[symbol]
+		changed := false
+		for _, field := range riskOverrideFields {
+			key := riskOverrideEnvKey(symbol, field.suffix)
+			raw := getEnvString(key, "")
+			if raw == "" {
+				continue
+			}
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s %q: %v", key, raw, err)
+			}
+			*field.override(&override) = &value
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		if c.RiskManagement.SymbolOverrides == nil {
+			c.RiskManagement.SymbolOverrides = make(map[string]RiskOverride)
+		}
+		c.RiskManagement.SymbolOverrides[symbol] = override
+	}
+	return nil
+}
+
+// RiskFor returns the risk settings for symbol, with its overrides merged onto the global ones
+func (c *Config) RiskFor(symbol string) RiskManagementConfig {
+	risk := c.RiskManagement
+	risk.SymbolOverrides = nil
+	override, ok := c.RiskManagement.SymbolOverrides[symbol]
+	if !ok {
+		return risk
+	}
+	for _, field := range riskOverrideFields {
+		if value := *field.override(&override); value != nil {
+			*field.base(&risk) = *value
+		}
+	}
+	return risk
+}
+
+// ForSymbol returns a copy of the config whose risk settings are those of symbol, so the risk
+// methods apply its overrides
+func (c *Config) ForSymbol(symbol string) *Config {
+	if _, ok := c.RiskManagement.SymbolOverrides[symbol]; !ok {
+		return c
+	}
+	scoped := *c
+	scoped.RiskManagement = c.RiskFor(symbol)
+	return &scoped
+}
+
+// validateRiskLimits checks the per-trade risk settings that may be overridden per symbol
+func validateRiskLimits(r RiskManagementConfig) error {
+	if r.MaxRiskPercentage <= 0 || r.MaxRiskPercentage > 1 {
+		return fmt.Errorf("max risk percentage must be between 0 and 1, got %f", r.MaxRiskPercentage)
+	}
+	if r.StopLossPercentage < 0 || r.StopLossPercentage > 1 {
+		return fmt.Errorf("stop loss percentage must be between 0 and 1, got %f", r.StopLossPercentage)
+	}
+	if r.ATRStopMultiplier <= 0 {
+		return fmt.Errorf("ATR stop multiplier must be positive, got %f", r.ATRStopMultiplier)
+	}
+	if r.SoftLossPercentage < 0 || r.SoftLossPercentage > 1 {
+		return fmt.Errorf("soft loss percentage must be between 0 and 1, got %f", r.SoftLossPercentage)
+	}
+	if r.SoftLossPercentage > 0 && r.StopLossPercentage > 0 && r.SoftLossPercentage >= r.StopLossPercentage {
+		return fmt.Errorf("soft loss percentage %f must be below stop loss percentage %f", r.SoftLossPercentage, r.StopLossPercentage)
+	}
+	if r.BreakEvenThreshold < 0 {
+		return fmt.Errorf("break-even threshold must be non-negative, got %f", r.BreakEvenThreshold)
+	}
+	if r.MaxPositionSize <= 0 || r.MaxPositionSize > 1 {
+		return fmt.Errorf("max position size must be between 0 and 1, got %f", r.MaxPositionSize)
+	}
+	return nil
+}
+
+// validateRiskOverrides applies the global risk checks to every symbol's merged settings
+func (c *Config) validateRiskOverrides() error {
+	symbols := make([]string, 0, len(c.RiskManagement.SymbolOverrides))
+	for symbol := range c.RiskManagement.SymbolOverrides {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		risk := c.RiskFor(symbol)
+		if err := validateRiskLimits(risk); err != nil {
+			return fmt.Errorf("risk override for %s: %v", symbol, err)
+		}
+		if c.FixedCapital.RiskPercentage > risk.MaxRiskPercentage {
+			return fmt.Errorf("risk override for %s: fixed capital risk percentage %f exceeds max risk percentage %f",
+				symbol, c.FixedCapital.RiskPercentage, risk.MaxRiskPercentage)
+		}
+	}
+	return nil
+}
+
+// riskOverrideEnv returns the env variables of the configured overrides
+func (c *Config) riskOverrideEnv() map[string]string {
+	env := make(map[string]string)
+	for symbol, override := range c.RiskManagement.SymbolOverrides {
+		for _, field := range riskOverrideFields {
+			if value := *field.override(&override); value != nil {
+				env[riskOverrideEnvKey(symbol, field.suffix)] = formatEnvFloat(*value)
+			}
+		}
+	}
+	return env
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigRejectsUnparseableRiskOverride(t *testing.T) {
+	t.Setenv("TRADING_PAIR", "ETHUSDT")
+	t.Setenv("RISK_OVERRIDE_ETHUSDT_STOP_LOSS_PERCENT", "two percent")
+	_, err := LoadConfig()
+	if err == nil || !strings.Contains(err.Error(), "RISK_OVERRIDE_ETHUSDT_STOP_LOSS_PERCENT") {
+		t.Fatalf("LoadConfig error = %v, want the bad override named", err)
+	}
+}
+
+func TestLoadConfigReadsRiskOverride(t *testing.T) {
+	t.Setenv("TRADING_TESTNET_ENABLED", "true")
+	t.Setenv("TRADING_PAIR", "ETHUSDT")
+	t.Setenv("RISK_OVERRIDE_ETHUSDT_STOP_LOSS_PERCENT", "0.01")
+	c, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if got := c.RiskFor("ETHUSDT").StopLossPercentage; got != 0.01 {
+		t.Errorf("ETHUSDT stop loss = %f, want 0.01", got)
+	}
+	if got := c.RiskFor("BNBUSDT").StopLossPercentage; got != c.RiskManagement.StopLossPercentage {
+		t.Errorf("BNBUSDT stop loss = %f, want the global %f", got, c.RiskManagement.StopLossPercentage)
+	}
+}
+
+func withOverride(c *Config, symbol string, override RiskOverride) {
+	c.RiskManagement.SymbolOverrides = map[string]RiskOverride{symbol: override}
+}
+
+func TestSizePositionAppliesSymbolOverride(t *testing.T) {
+	c := DefaultConfig()
+	c.FixedCapital.MaxCapitalPerTrade = 1e9
+	tight := 0.01
+	withOverride(c, "ETHUSDT", RiskOverride{MaxPositionSize: &tight})
+
+	// 2% of 1000 risked over a 10 stop distance is 2, capped at 1% of equity / 100 = 0.1 for ETHUSDT
+	if got := c.SizePosition("ETHUSDT", 1000, 100, 90, SideLong, nil); math.Abs(got-0.1) > 1e-9 {
+		t.Errorf("ETHUSDT size = %f, want the overridden cap 0.1", got)
+	}
+	if got := c.SizePosition("BNBUSDT", 1000, 100, 90, SideLong, nil); math.Abs(got-1) > 1e-9 {
+		t.Errorf("BNBUSDT size = %f, want the global cap 1", got)
+	}
+}
+
+func TestSoftLossMonitorUsesSymbolThreshold(t *testing.T) {
+	c := DefaultConfig()
+	soft := 0.01
+	withOverride(c, "ETHUSDT", RiskOverride{SoftLossPercentage: &soft})
+	monitor := c.NewSoftLossMonitor(func(string, float64) {})
+
+	eth := &Position{ID: "eth", Symbol: "ETHUSDT", Side: SideLong, EntryPrice: 100}
+	bnb := &Position{ID: "bnb", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100}
+	if !monitor.Check(eth, 98) {
+		t.Error("expected the ETHUSDT soft loss alert")
+	}
+	if monitor.Check(bnb, 98) {
+		t.Error("BNBUSDT has no soft loss threshold and should not alert")
+	}
+}
+
+func TestATRStopPriceForUsesSymbolMultiplier(t *testing.T) {
+	c := DefaultConfig()
+	multiplier := 1.0
+	withOverride(c, "ETHUSDT", RiskOverride{ATRStopMultiplier: &multiplier})
+	if got := c.ATRStopPriceFor("ETHUSDT", 100, 2, SideLong); got != 98 {
+		t.Errorf("ETHUSDT ATR stop = %f, want 98", got)
+	}
+	if got := c.ATRStopPriceFor("BNBUSDT", 100, 2, SideLong); got != 100-2*c.RiskManagement.ATRStopMultiplier {
+		t.Errorf("BNBUSDT ATR stop = %f, want the global multiplier applied", got)
+	}
+}
+
+func TestBacktesterAppliesPairOverride(t *testing.T) {
+	c := DefaultConfig()
+	stop := 0.01
+	withOverride(c, c.Trading.TradingPair, RiskOverride{StopLossPercentage: &stop})
+	if got := NewBacktester(c, nil).config.RiskManagement.StopLossPercentage; got != 0.01 {
+		t.Errorf("backtest stop loss = %f, want the pair override 0.01", got)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+// ScaleInPlan splits an entry into a ladder of limit orders spaced away from the first price
+type ScaleInPlan struct {
+	// Number of ladder entries; 1 enters all at once
+	Entries int
+	// Distance between consecutive entries as a fraction of the first price (0.005 = 0.5%)
+	SpacingPercentage float64
+}
+
+// ScaleInEntry is one rung of an entry ladder
+type ScaleInEntry struct {
+	Price    float64
+	Quantity float64
+}
+
+// Ladder splits quantity evenly across the plan's entries, the first at price and each later
+// one SpacingPercentage further below it for longs and above it for shorts
+func (p ScaleInPlan) Ladder(price, quantity float64, side string) []ScaleInEntry {
+	entries := max(p.Entries, 1)
+	step := price * p.SpacingPercentage
+	if !isBuy(side) {
+		step = -step
+	}
+
+	ladder := make([]ScaleInEntry, entries)
+	perEntry := quantity / float64(entries)
+	for i := range ladder {
+		ladder[i] = ScaleInEntry{Price: price - float64(i)*step, Quantity: perEntry}
+	}
+	// Give float rounding to the last rung so the ladder sums to quantity exactly
+	ladder[entries-1].Quantity = quantity - perEntry*float64(entries-1)
+	return ladder
+}
+
+// ScaleInOrders builds the ladder's limit entry orders for symbol with the configured time in force
+func (c *Config) ScaleInOrders(symbol, side string, price, quantity float64) []Order {
+	ladder := c.ScaleIn.Ladder(price, quantity, side)
+	orders := make([]Order, len(ladder))
+	for i, entry := range ladder {
+		orders[i] = Order{
+			Symbol:      symbol,
+			Side:        side,
+			Quantity:    entry.Quantity,
+			Price:       entry.Price,
+			Type:        OrderTypeLimit,
+			TimeInForce: c.Trading.TimeInForce,
+		}
+	}
+	return orders
+}
+
+// VWAP accumulates the volume-weighted average price of fills
+type VWAP struct {
+	notional float64
+	quantity float64
+}
+
+// Add records quantity filled at price
+func (v *VWAP) Add(price, quantity float64) {
+	if quantity <= 0 {
+		return
+	}
+	v.notional += price * quantity
+	v.quantity += quantity
+}
+
+// Quantity returns the total quantity added
+func (v *VWAP) Quantity() float64 {
+	return v.quantity
+}
+
+// Value returns the average price, 0 before any fill
+func (v *VWAP) Value() float64 {
+	if v.quantity == 0 {
+		return 0
+	}
+	return v.notional / v.quantity
+}
+
+// NewScaledPosition opens a position from the first ladder fill, requesting the whole ladder
+// quantity. Later rung fills are added with ApplyFill, keeping EntryPrice at the running VWAP
+// that the stop and tier targets derive from.
+func NewScaledPosition(id, side string, ladder []ScaleInEntry, first Fill) *Position {
+	p := NewPositionFromFill(id, side, first)
+	total := 0.0
+	for _, entry := range ladder {
+		total += entry.Quantity
+	}
+	p.Quantity = max(total, p.FilledQuantity)
+	return p
+}
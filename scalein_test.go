@@ -0,0 +1,50 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLadderSpacingBySide(t *testing.T) {
+	plan := ScaleInPlan{Entries: 3, SpacingPercentage: 0.01}
+	long := plan.Ladder(100, 3, SideLong)
+	short := plan.Ladder(100, 3, SideShort)
+	for i, want := range []float64{100, 99, 98} {
+		if math.Abs(long[i].Price-want) > 1e-9 {
+			t.Errorf("long rung %d price = %f, want %f", i, long[i].Price, want)
+		}
+	}
+	for i, want := range []float64{100, 101, 102} {
+		if math.Abs(short[i].Price-want) > 1e-9 {
+			t.Errorf("short rung %d price = %f, want %f", i, short[i].Price, want)
+		}
+	}
+	var total float64
+	for _, rung := range long {
+		total += rung.Quantity
+	}
+	if total != 3 {
+		t.Errorf("ladder quantities sum to %f, want 3", total)
+	}
+}
+
+func TestScaleInOrdersUseConfiguredTimeInForce(t *testing.T) {
+	c := DefaultConfig()
+	c.ScaleIn = ScaleInPlan{Entries: 2, SpacingPercentage: 0.01}
+	c.Trading.TimeInForce = TimeInForceIOC
+	for _, order := range c.ScaleInOrders("BNBUSDT", SideBuy, 100, 2) {
+		if order.TimeInForce != TimeInForceIOC || order.Type != OrderTypeLimit {
+			t.Errorf("ladder order = %+v, want an IOC limit order", order)
+		}
+	}
+}
+
+func TestVWAP(t *testing.T) {
+	var vwap VWAP
+	vwap.Add(100, 1)
+	vwap.Add(110, 3)
+	vwap.Add(500, 0)
+	if vwap.Value() != 107.5 || vwap.Quantity() != 4 {
+		t.Errorf("VWAP = %f over %f, want 107.5 over 4", vwap.Value(), vwap.Quantity())
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// SizingStrategy selects how position sizes are calculated
+type SizingStrategy string
+
+const (
+	// SizingFixedRisk risks RiskPercentage of equity between entry and stop
+	SizingFixedRisk SizingStrategy = "FIXED_RISK"
+	// SizingKelly sizes by fractional Kelly from the recorded trade outcomes
+	SizingKelly SizingStrategy = "KELLY"
+)
+
+// ParseSizingStrategy parses a sizing strategy case-insensitively
+func ParseSizingStrategy(s string) (SizingStrategy, error) {
+	switch strategy := SizingStrategy(strings.ToUpper(strings.TrimSpace(s))); strategy {
+	case SizingFixedRisk, SizingKelly:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown sizing strategy %q", s)
+	}
+}
+
+// KellyPositionSize returns the capital to commit by fractional Kelly, clamped to
+// EffectiveMaxTradeValue. It returns 0 when the Kelly fraction shows no edge.
+func (c *Config) KellyPositionSize(winRate, avgWin, avgLoss, currentEquity float64) float64 {
+	if avgWin <= 0 || avgLoss <= 0 || currentEquity <= 0 {
+		return 0
+	}
+
+	payoff := avgWin / avgLoss
+	kelly := winRate - (1-winRate)/payoff
+	if kelly <= 0 {
+		return 0
+	}
+
+	size := currentEquity * kelly * c.Kelly.Fraction
+	if maxSize := c.EffectiveMaxTradeValue(currentEquity); size > maxSize {
+		return maxSize
+	}
+	return size
+}
+
+// SizePosition returns the position quantity in symbol using the configured sizing strategy
+// and the symbol's risk overrides. Kelly sizing draws its win rate and payoff from outcomes and
+// falls back to fixed risk sizing until outcomes holds both a win and a loss.
+func (c *Config) SizePosition(symbol string, currentEquity, entryPrice, stopLossPrice float64, side string, outcomes *WinRateTracker) float64 {
+	c = c.ForSymbol(symbol)
+	if c.SizingStrategy == SizingKelly && outcomes != nil && outcomes.Wins() > 0 && outcomes.Losses() > 0 {
+		if entryPrice <= 0 {
+			return 0
+		}
+		capital := c.KellyPositionSize(outcomes.WinRate(), outcomes.AverageWin(), outcomes.AverageLoss(), currentEquity)
+		return capital / entryPrice
+	}
+	return c.CalculatePositionSize(currentEquity, entryPrice, stopLossPrice, side)
+}
+
+// CalculatePositionSizeQuote sizes a position in quote currency without a stop: it commits
+// RiskPercentage of equity, clamped to EffectiveMaxTradeValue, and returns that quote amount
+// with the base quantity it buys at entryPrice
+func (c *Config) CalculatePositionSizeQuote(currentEquity, entryPrice float64) (quoteAmount, baseQuantity float64) {
+	if currentEquity <= 0 || entryPrice <= 0 {
+		return 0, 0
+	}
+	quoteAmount = math.Min(c.CalculateRiskCapital(currentEquity), c.EffectiveMaxTradeValue(currentEquity))
+	return quoteAmount, quoteAmount / entryPrice
+}
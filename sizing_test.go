@@ -0,0 +1,77 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCalculatePositionSizeLongAndShort(t *testing.T) {
+	c := DefaultConfig()
+	c.FixedCapital.MaxCapitalPerTrade = 1e9
+	c.RiskManagement.MaxPositionSize = 1
+	tests := []struct {
+		name  string
+		entry float64
+		stop  float64
+		side  string
+		want  float64
+	}{
+		// 2% of 1000 risked over a 5 stop distance
+		{"long", 100, 95, SideLong, 4},
+		{"buy", 100, 95, SideBuy, 4},
+		{"short", 100, 105, SideShort, 4},
+		{"sell", 100, 105, SideSell, 4},
+		{"long stop above entry", 100, 105, SideLong, 0},
+		{"short stop below entry", 100, 95, SideShort, 0},
+		{"empty side", 100, 105, "", 0},
+		{"unknown side", 100, 105, "hedge", 0},
+	}
+	for _, tt := range tests {
+		if got := c.CalculatePositionSize(1000, tt.entry, tt.stop, tt.side); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: CalculatePositionSize = %f, want %f", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCalculatePositionSizeClampsToMaxTradeValue(t *testing.T) {
+	c := DefaultConfig()
+	// 100 max capital per trade buys 1 at 100
+	for _, tt := range []struct {
+		side string
+		stop float64
+	}{{SideLong, 99.9}, {SideShort, 100.1}} {
+		if got := c.CalculatePositionSize(1000, 100, tt.stop, tt.side); math.Abs(got-1) > 1e-9 {
+			t.Errorf("%s: CalculatePositionSize = %f, want the clamp of 1", tt.side, got)
+		}
+	}
+}
+
+func TestSizePositionKellyFallsBackWithoutOutcomes(t *testing.T) {
+	c := DefaultConfig()
+	c.SizingStrategy = SizingKelly
+	fixed := c.CalculatePositionSize(1000, 100, 99.5, SideLong)
+
+	if got := c.SizePosition("BNBUSDT", 1000, 100, 99.5, SideLong, nil); got != fixed {
+		t.Errorf("Kelly with nil outcomes = %f, want fixed risk size %f", got, fixed)
+	}
+	outcomes := NewWinRateTracker(0)
+	outcomes.RecordTrade(10)
+	if got := c.SizePosition("BNBUSDT", 1000, 100, 99.5, SideLong, outcomes); got != fixed {
+		t.Errorf("Kelly without a loss = %f, want fixed risk size %f", got, fixed)
+	}
+}
+
+func TestSizePositionKellyUsesOutcomes(t *testing.T) {
+	c := DefaultConfig()
+	c.SizingStrategy = SizingKelly
+	c.FixedCapital.MaxCapitalPerTrade = 1e9
+	c.RiskManagement.MaxPositionSize = 1
+	outcomes := NewWinRateTracker(0)
+	for _, profit := range []float64{20, 20, 20, -10} {
+		outcomes.RecordTrade(profit)
+	}
+	// win rate 0.75, payoff 2: Kelly 0.625, half Kelly of 1000 is 312.5, buying 3.125 at 100
+	if got := c.SizePosition("BNBUSDT", 1000, 100, 90, SideLong, outcomes); math.Abs(got-3.125) > 1e-9 {
+		t.Errorf("Kelly size = %f, want 3.125", got)
+	}
+}
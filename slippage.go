@@ -0,0 +1,23 @@
+package main
+
+// EstimateFillPrice returns the worst acceptable fill price for a quote, adjusted upward for
+// buys and downward for sells by SlippageTolerance
+func (c *Config) EstimateFillPrice(quotedPrice float64, side string) float64 {
+	if isBuy(side) {
+		return quotedPrice * (1 + c.Trading.SlippageTolerance)
+	}
+	return quotedPrice * (1 - c.Trading.SlippageTolerance)
+}
+
+// IsSlippageAcceptable reports whether an actual fill price is within SlippageTolerance of the
+// quote. Without a quote there is nothing to measure slippage against, so any price is accepted.
+func (c *Config) IsSlippageAcceptable(quoted, actual float64, side string) bool {
+	if quoted <= 0 {
+		return true
+	}
+	worst := c.EstimateFillPrice(quoted, side)
+	if isBuy(side) {
+		return actual <= worst
+	}
+	return actual >= worst
+}
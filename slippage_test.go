@@ -0,0 +1,51 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEstimateFillPrice(t *testing.T) {
+	c := DefaultConfig()
+	c.Trading.SlippageTolerance = 0.01
+	tests := []struct {
+		quoted float64
+		side   string
+		want   float64
+	}{
+		{100, SideBuy, 101},
+		{100, SideSell, 99},
+		{0, SideBuy, 0},
+		{0, SideSell, 0},
+	}
+	for _, tt := range tests {
+		if got := c.EstimateFillPrice(tt.quoted, tt.side); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("EstimateFillPrice(%f, %s) = %f, want %f", tt.quoted, tt.side, got, tt.want)
+		}
+	}
+}
+
+func TestIsSlippageAcceptable(t *testing.T) {
+	c := DefaultConfig()
+	c.Trading.SlippageTolerance = 0.01
+	tests := []struct {
+		name           string
+		quoted, actual float64
+		side           string
+		want           bool
+	}{
+		{"buy below the quote", 100, 99, SideBuy, true},
+		{"buy at the tolerance", 100, 101, SideBuy, true},
+		{"buy past the tolerance", 100, 101.5, SideBuy, false},
+		{"sell above the quote", 100, 101, SideSell, true},
+		{"sell at the tolerance", 100, 99, SideSell, true},
+		{"sell past the tolerance", 100, 98.5, SideSell, false},
+		{"buy without a quote", 0, 101, SideBuy, true},
+		{"sell without a quote", 0, 99, SideSell, true},
+	}
+	for _, tt := range tests {
+		if got := c.IsSlippageAcceptable(tt.quoted, tt.actual, tt.side); got != tt.want {
+			t.Errorf("%s: IsSlippageAcceptable = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"log"
+	"sync"
+)
+
+// unrealizedLossFraction returns the unrealized loss of a position as a fraction of entry; negative when in profit
+func unrealizedLossFraction(entryPrice, currentPrice float64, side string) float64 {
+	if entryPrice <= 0 {
+		return 0
+	}
+	if isLong(side) {
+		return (entryPrice - currentPrice) / entryPrice
+	}
+	return (currentPrice - entryPrice) / entryPrice
+}
+
+// SoftLossAlertFunc is called once when a position crosses the soft loss threshold
+type SoftLossAlertFunc func(positionID string, lossFraction float64)
+
+// SoftLossMonitor emits a single alert per position when its unrealized loss crosses
+// the soft threshold. It never closes positions; the hard stop still applies.
+type SoftLossMonitor struct {
+	mu      sync.Mutex
+	config  *Config
+	alert   SoftLossAlertFunc
+	alerted map[string]bool
+}
+
+// NewSoftLossMonitor creates a soft loss monitor; a nil alert logs the crossing
+func (c *Config) NewSoftLossMonitor(alert SoftLossAlertFunc) *SoftLossMonitor {
+	if alert == nil {
+		alert = func(positionID string, lossFraction float64) {
+			log.Printf("⚠️  Position %s unrealized loss %.2f%% crossed soft loss threshold, review required", positionID, lossFraction*100)
+		}
+	}
+	return &SoftLossMonitor{
+		config:  c,
+		alert:   alert,
+		alerted: make(map[string]bool),
+	}
+}
+
+// reconfigure switches the monitor to the soft loss thresholds of c
+func (m *SoftLossMonitor) reconfigure(c *Config) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.config = c
+}
+
+// Check evaluates a position's current price against its symbol's soft loss threshold and
+// reports whether an alert fired
+func (m *SoftLossMonitor) Check(p *Position, currentPrice float64) bool {
+	m.mu.Lock()
+	threshold := m.config.RiskFor(p.Symbol).SoftLossPercentage
+	if threshold <= 0 {
+		m.mu.Unlock()
+		return false
+	}
+
+	loss := unrealizedLossFraction(p.EntryPrice, currentPrice, p.Side)
+	if loss < threshold || m.alerted[p.ID] {
+		m.mu.Unlock()
+		return false
+	}
+	m.alerted[p.ID] = true
+	m.mu.Unlock()
+
+	m.alert(p.ID, loss)
+	return true
+}
+
+// Alerted reports whether the position crossed its soft loss threshold and is held for review
+func (m *SoftLossMonitor) Alerted(positionID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.alerted[positionID]
+}
+
+// Reset clears the alert state of a closed position
+func (m *SoftLossMonitor) Reset(positionID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.alerted, positionID)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSoftLossMonitorAlertsOncePerPosition(t *testing.T) {
+	c := DefaultConfig()
+	c.RiskManagement.SoftLossPercentage = 0.01
+	var alerts []string
+	monitor := c.NewSoftLossMonitor(func(positionID string, lossFraction float64) {
+		alerts = append(alerts, positionID)
+	})
+
+	long := &Position{ID: "long", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100}
+	if monitor.Check(long, 99.5) {
+		t.Error("alert below the soft threshold")
+	}
+	if !monitor.Check(long, 98.9) {
+		t.Error("no alert past the soft threshold")
+	}
+	if monitor.Check(long, 98) {
+		t.Error("second alert for the same position")
+	}
+
+	short := &Position{ID: "short", Symbol: "BNBUSDT", Side: SideShort, EntryPrice: 100}
+	if monitor.Check(short, 99) {
+		t.Error("alert for a short in profit")
+	}
+	if !monitor.Check(short, 101.5) {
+		t.Error("no alert for a short past the soft threshold")
+	}
+
+	monitor.Reset("long")
+	if !monitor.Check(long, 98) {
+		t.Error("no alert after Reset")
+	}
+	if len(alerts) != 3 {
+		t.Errorf("alerts = %v, want 3", alerts)
+	}
+}
+
+func TestSoftLossMonitorUsesSymbolOverride(t *testing.T) {
+	c := DefaultConfig()
+	soft := 0.02
+	withOverride(c, "ETHUSDT", RiskOverride{SoftLossPercentage: &soft})
+	monitor := c.NewSoftLossMonitor(func(string, float64) {})
+
+	if monitor.Check(&Position{ID: "bnb", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100}, 90) {
+		t.Error("alert with the soft loss disabled")
+	}
+	eth := &Position{ID: "eth", Symbol: "ETHUSDT", Side: SideLong, EntryPrice: 100}
+	if monitor.Check(eth, 98.5) {
+		t.Error("alert below the overridden threshold")
+	}
+	if !monitor.Check(eth, 97.9) {
+		t.Error("no alert past the overridden threshold")
+	}
+}
+
+func TestAllowEntryHoldsSymbolPastSoftLoss(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	bot.config.Load().RiskManagement.SoftLossPercentage = 0.01
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 300, Quantity: 1, FilledQuantity: 1})
+	signal, other := testSignal(), testSignal()
+	signal.Symbol, other.Symbol = "BNBUSDT", "ETHUSDT"
+
+	if err := bot.ManagePosition(context.Background(), "p", 298); err != nil {
+		t.Fatalf("ManagePosition: %v", err)
+	}
+	if !bot.AllowEntry(signal) {
+		t.Fatal("AllowEntry refused a signal before the soft loss threshold")
+	}
+
+	// 2% down is past the 1% soft threshold but above the 3% stop
+	if err := bot.ManagePosition(context.Background(), "p", 294); err != nil {
+		t.Fatalf("ManagePosition: %v", err)
+	}
+	if len(bot.OpenPositions()) != 1 {
+		t.Fatal("the soft loss threshold closed the position")
+	}
+	if bot.AllowEntry(signal) {
+		t.Error("AllowEntry admitted a signal in a symbol held for review")
+	}
+	if !bot.AllowEntry(other) {
+		t.Error("AllowEntry refused a signal in another symbol")
+	}
+
+	bot.UntrackPosition("p")
+	if !bot.AllowEntry(signal) {
+		t.Error("AllowEntry still holds the symbol after the position closed")
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrStateNotFound is returned when no state has been saved under a key
+var ErrStateNotFound = errors.New("state not found")
+
+// StateStore persists named pieces of bot state
+type StateStore interface {
+	// Save stores value under key, replacing any previous value
+	Save(key string, value interface{}) error
+	// Load decodes the value stored under key into value, returning ErrStateNotFound if absent
+	Load(key string, value interface{}) error
+}
+
+// State backends
+const (
+	StateBackendFile = "file"
+)
+
+// NewStateStore creates the state store selected by StateBackend
+func (c *Config) NewStateStore() (StateStore, error) {
+	switch c.StateBackend {
+	case StateBackendFile:
+		return NewFileStateStore(c.StateDir)
+	default:
+		return nil, fmt.Errorf("unknown state backend %q", c.StateBackend)
+	}
+}
+
+// FileStateStore stores each key as a JSON file in a directory
+type FileStateStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewFileStateStore creates a file state store, creating dir if needed
+func NewFileStateStore(dir string) (*FileStateStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("error creating state directory %s: %v", dir, err)
+	}
+	return &FileStateStore{dir: dir}, nil
+}
+
+func (s *FileStateStore) path(key string) string {
+	return filepath.Join(s.dir, key+".json")
+}
+
+// Save writes value as JSON, replacing the file atomically so a crash never leaves partial state
+func (s *FileStateStore) Save(key string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding state %s: %v", key, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tmp := s.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("error writing state %s: %v", key, err)
+	}
+	if err := os.Rename(tmp, s.path(key)); err != nil {
+		return fmt.Errorf("error replacing state %s: %v", key, err)
+	}
+	return nil
+}
+
+// Load reads the JSON file for key into value
+func (s *FileStateStore) Load(key string, value interface{}) error {
+	s.mu.Lock()
+	data, err := os.ReadFile(s.path(key))
+	s.mu.Unlock()
+
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrStateNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("error reading state %s: %v", key, err)
+	}
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("error decoding state %s: %v", key, err)
+	}
+	return nil
+}
+
+// validateStateBackend checks the state backend name
+func validateStateBackend(backend string) error {
+	switch strings.ToLower(backend) {
+	case StateBackendFile:
+		return nil
+	default:
+		return fmt.Errorf("unknown state backend %q", backend)
+	}
+}
+
+// botStateKey is the state store key of the bot state
+const botStateKey = "bot_state"
+
+// BotState is the bot state persisted across restarts. The daily starting equity is persisted
+// by the daily loss guard under its own key as soon as each day's anchor is taken.
+type BotState struct {
+	OpenPositions []*Position
+	// Consecutive losing trades per trading pair
+	PairConsecutiveLosses map[string]int
+	PeakEquity            float64
+	PeakEquityTime        time.Time
+	// Realized PnL booked against the capital base
+	RealizedPnL float64
+	// Quote notional traded per UTC day over the trailing 30 days
+	DailyVolume map[string]float64
+}
+
+// BotStateStore persists the bot state
+type BotStateStore interface {
+	// Save stores the bot state, replacing any previous state
+	Save(state BotState) error
+	// Load returns the saved bot state, or ErrStateNotFound if none was saved
+	Load() (BotState, error)
+}
+
+// keyedBotStateStore stores the bot state under a single key of a StateStore; backed by a
+// FileStateStore it keeps the state in one JSON file
+type keyedBotStateStore struct {
+	store StateStore
+}
+
+// NewBotStateStore creates a bot state store on top of store
+func NewBotStateStore(store StateStore) BotStateStore {
+	return &keyedBotStateStore{store: store}
+}
+
+// Save stores the bot state
+func (s *keyedBotStateStore) Save(state BotState) error {
+	return s.store.Save(botStateKey, state)
+}
+
+// Load returns the saved bot state
+func (s *keyedBotStateStore) Load() (BotState, error) {
+	var state BotState
+	if err := s.store.Load(botStateKey, &state); err != nil {
+		return BotState{}, err
+	}
+	return state, nil
+}
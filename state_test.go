@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFileStateStoreRoundTripsBotState(t *testing.T) {
+	store, err := NewFileStateStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStateStore: %v", err)
+	}
+	bots := NewBotStateStore(store)
+
+	if _, err := bots.Load(); !errors.Is(err, ErrStateNotFound) {
+		t.Fatalf("Load before Save = %v, want ErrStateNotFound", err)
+	}
+
+	openedAt := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)
+	want := BotState{
+		OpenPositions: []*Position{{
+			ID: "p1", Symbol: "BNBUSDT", Side: SideLong, RequestedPrice: 300, EntryPrice: 301,
+			Quantity: 2, FilledQuantity: 1.5, EntryOrderID: "42", OpenedAt: openedAt,
+			TierState: TierState{Tiers: []TierProfit{{ProfitPercentage: 1, ClosePercentage: 1, Enabled: true}}, Executed: []bool{false}},
+		}},
+		PairConsecutiveLosses: map[string]int{"BNBUSDT": 2},
+		PeakEquity:            1250,
+		PeakEquityTime:        openedAt,
+		RealizedPnL:           250,
+		DailyVolume:           map[string]float64{"2024-05-01": 903},
+	}
+	if err := bots.Save(want); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	got, err := bots.Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFileStateStoreReplacesAtomically(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStateStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStateStore: %v", err)
+	}
+	if err := store.Save("counter", 1); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if err := store.Save("counter", 2); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	var got int
+	if err := store.Load("counter", &got); err != nil || got != 2 {
+		t.Errorf("Load = %d, %v, want 2", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "counter.json.tmp")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("temporary file left behind: %v", err)
+	}
+}
+
+func TestFileStateStoreRejectsCorruptState(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStateStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStateStore: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, botStateKey+".json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewBotStateStore(store).Load(); err == nil || errors.Is(err, ErrStateNotFound) {
+		t.Errorf("Load of corrupt state = %v, want a decode error", err)
+	}
+}
+
+func TestNewStateStoreRejectsUnknownBackend(t *testing.T) {
+	c := DefaultConfig()
+	c.StateBackend = "redis"
+	if _, err := c.NewStateStore(); err == nil {
+		t.Error("NewStateStore accepted an unknown backend")
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import "math"
+
+// statsDeviationEpsilon absorbs floating point error in the deviation of returns that do not vary
+const statsDeviationEpsilon = 1e-12
+
+// mean returns the average of values, 0 when empty
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var total float64
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
+}
+
+// Sharpe returns the mean excess return over riskFreeRate divided by the sample standard
+// deviation of returns, all per period. It returns 0 with fewer than two returns or no variance.
+func Sharpe(returns []float64, riskFreeRate float64) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+	average := mean(returns)
+	var variance float64
+	for _, r := range returns {
+		variance += (r - average) * (r - average)
+	}
+	stdDev := math.Sqrt(variance / float64(len(returns)-1))
+	if stdDev < statsDeviationEpsilon {
+		return 0
+	}
+	return (average - riskFreeRate) / stdDev
+}
+
+// Sortino returns the mean return in excess of target divided by the downside deviation
+// below target. It returns 0 with fewer than two returns or when no return falls below target.
+func Sortino(returns []float64, target float64) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+	var downside float64
+	for _, r := range returns {
+		if r < target {
+			downside += (r - target) * (r - target)
+		}
+	}
+	downsideDeviation := math.Sqrt(downside / float64(len(returns)))
+	if downsideDeviation < statsDeviationEpsilon {
+		return 0
+	}
+	return (mean(returns) - target) / downsideDeviation
+}
+
+// MaxDrawdown returns the largest peak-to-trough decline of an equity curve as a fraction,
+// 0 for an empty or single-point curve
+func MaxDrawdown(equityCurve []float64) float64 {
+	var peak, worst float64
+	for _, equity := range equityCurve {
+		if equity > peak {
+			peak = equity
+		}
+		if peak > 0 {
+			worst = math.Max(worst, (peak-equity)/peak)
+		}
+	}
+	return worst
+}
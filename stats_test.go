@@ -0,0 +1,66 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSharpeGuardsDegenerateReturns(t *testing.T) {
+	tests := []struct {
+		name    string
+		returns []float64
+		want    float64
+	}{
+		{"empty", nil, 0},
+		{"single return", []float64{0.05}, 0},
+		{"constant returns", []float64{0.1, 0.1, 0.1}, 0},
+		{"varying returns", []float64{0.1, -0.1, 0.3}, 0.1 / 0.2},
+	}
+	for _, tt := range tests {
+		got := Sharpe(tt.returns, 0)
+		if math.IsNaN(got) || math.IsInf(got, 0) || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: Sharpe = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSortinoGuardsDegenerateReturns(t *testing.T) {
+	tests := []struct {
+		name    string
+		returns []float64
+		want    float64
+	}{
+		{"empty", nil, 0},
+		{"single return", []float64{-0.05}, 0},
+		{"no downside", []float64{0.1, 0.1, 0.1}, 0},
+		{"constant downside", []float64{-0.1, -0.1}, -1},
+		// Mean 0.1 over a downside deviation of sqrt(0.01/2)
+		{"mixed", []float64{0.3, -0.1}, 0.1 / math.Sqrt(0.005)},
+	}
+	for _, tt := range tests {
+		got := Sortino(tt.returns, 0)
+		if math.IsNaN(got) || math.IsInf(got, 0) || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: Sortino = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMaxDrawdownGuardsDegenerateCurves(t *testing.T) {
+	tests := []struct {
+		name  string
+		curve []float64
+		want  float64
+	}{
+		{"empty", nil, 0},
+		{"single point", []float64{1000}, 0},
+		{"flat", []float64{1000, 1000, 1000}, 0},
+		{"rising", []float64{1000, 1100, 1200}, 0},
+		{"peak to trough", []float64{1000, 1200, 900, 1100}, 0.25},
+	}
+	for _, tt := range tests {
+		got := MaxDrawdown(tt.curve)
+		if math.IsNaN(got) || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: MaxDrawdown = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+// BreakEvenTriggered reports whether a position's profit has reached BreakEvenThreshold percent
+func (c *Config) BreakEvenTriggered(entryPrice, currentPrice float64, side string) bool {
+	if !c.RiskManagement.BreakEvenStopEnabled || entryPrice <= 0 {
+		return false
+	}
+	profitPercentage := -unrealizedLossFraction(entryPrice, currentPrice, side) * 100
+	return profitPercentage >= c.RiskManagement.BreakEvenThreshold
+}
+
+// CalculateBreakEvenStop returns the stop price that covers the entry price plus entry and
+// exit fees, to be applied once BreakEvenTriggered. It returns 0 if break-even is disabled.
+func (c *Config) CalculateBreakEvenStop(entryPrice float64, side string) float64 {
+	if !c.RiskManagement.BreakEvenStopEnabled {
+		return 0
+	}
+	if isLong(side) {
+		return entryPrice * (1 + c.entryFee()) / (1 - c.exitFee())
+	}
+	return entryPrice * (1 - c.entryFee()) / (1 + c.exitFee())
+}
+
+// TrailingStop ratchets a stop price behind the best price reached, never loosening it
+type TrailingStop struct {
+	trailPercent float64
+	long         bool
+	bestPrice    float64
+	stopPrice    float64
+}
+
+// NewTrailingStop creates a trailing stop trailing TrailingStopPercentage (0.5 = 0.5%) behind
+// the best price
+func (c *Config) NewTrailingStop(entryPrice float64, side string) *TrailingStop {
+	t := &TrailingStop{
+		trailPercent: c.MultiTier.TrailingStopPercentage,
+		long:         isBuy(side),
+		bestPrice:    entryPrice,
+	}
+	t.stopPrice = t.stopFor(entryPrice)
+	return t
+}
+
+func (t *TrailingStop) stopFor(price float64) float64 {
+	if t.long {
+		return price * (1 - t.trailPercent/100)
+	}
+	return price * (1 + t.trailPercent/100)
+}
+
+// Update ratchets the stop with the current price and reports whether the stop was crossed
+func (t *TrailingStop) Update(currentPrice float64) (float64, bool) {
+	if t.long {
+		if currentPrice > t.bestPrice {
+			t.bestPrice = currentPrice
+			t.stopPrice = t.stopFor(currentPrice)
+		}
+		return t.stopPrice, currentPrice <= t.stopPrice
+	}
+
+	if currentPrice < t.bestPrice {
+		t.bestPrice = currentPrice
+		t.stopPrice = t.stopFor(currentPrice)
+	}
+	return t.stopPrice, currentPrice >= t.stopPrice
+}
+
+// StopPrice returns the current stop price
+func (t *TrailingStop) StopPrice() float64 {
+	return t.stopPrice
+}
+
+// ATRStopPriceFor returns the ATR stop of an entry in symbol at the symbol's ATRStopMultiplier
+func (c *Config) ATRStopPriceFor(symbol string, entryPrice, atr float64, side string) float64 {
+	return c.ATRStopPrice(entryPrice, atr, c.RiskFor(symbol).ATRStopMultiplier, side)
+}
+
+// ATRStopPrice returns a stop multiplier ATRs below the entry for longs and buys and above it
+// for shorts and sells, or 0 for an unknown side
+func (c *Config) ATRStopPrice(entryPrice, atr float64, multiplier float64, side string) float64 {
+	if !isKnownSide(side) {
+		return 0
+	}
+	if isBuy(side) {
+		return entryPrice - atr*multiplier
+	}
+	return entryPrice + atr*multiplier
+}
@@ -0,0 +1,56 @@
+package main
+
+import "testing"
+
+func TestATRStopPriceSides(t *testing.T) {
+	c := DefaultConfig()
+	tests := []struct {
+		side string
+		want float64
+	}{
+		{SideLong, 96},
+		{SideBuy, 96},
+		{SideShort, 104},
+		{SideSell, 104},
+		{"", 0},
+		{"lnog", 0},
+	}
+	for _, tt := range tests {
+		if got := c.ATRStopPrice(100, 2, 2, tt.side); got != tt.want {
+			t.Errorf("ATRStopPrice(%q) = %f, want %f", tt.side, got, tt.want)
+		}
+	}
+}
+
+func TestTrailingStopRatchets(t *testing.T) {
+	c := DefaultConfig()
+	c.MultiTier.TrailingStopPercentage = 1
+	for _, side := range []string{SideLong, SideBuy} {
+		stop := c.NewTrailingStop(100, side)
+		if stop.StopPrice() != 99 {
+			t.Fatalf("%s: initial stop = %f, want 99", side, stop.StopPrice())
+		}
+		if price, hit := stop.Update(110); price != 108.9 || hit {
+			t.Errorf("%s: Update(110) = %f, %v, want 108.9, false", side, price, hit)
+		}
+		// A pullback never loosens the stop
+		if price, hit := stop.Update(109); price != 108.9 || hit {
+			t.Errorf("%s: Update(109) = %f, %v, want 108.9, false", side, price, hit)
+		}
+		if _, hit := stop.Update(108.9); !hit {
+			t.Errorf("%s: Update(108.9) did not hit the stop", side)
+		}
+	}
+}
+
+func TestTrailingStopShort(t *testing.T) {
+	c := DefaultConfig()
+	c.MultiTier.TrailingStopPercentage = 2
+	stop := c.NewTrailingStop(100, SideShort)
+	if price, hit := stop.Update(90); price != 91.8 || hit {
+		t.Errorf("Update(90) = %f, %v, want 91.8, false", price, hit)
+	}
+	if price, hit := stop.Update(95); price != 91.8 || !hit {
+		t.Errorf("Update(95) = %f, %v, want 91.8, true", price, hit)
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SymbolFilter holds a symbol's exchange step size for quantities and tick size for prices;
+// a zero size disables rounding for that value
+type SymbolFilter struct {
+	StepSize float64
+	TickSize float64
+}
+
+// stepDecimals returns the number of decimals in step, used to strip float noise after rounding
+func stepDecimals(step float64) int {
+	s := strconv.FormatFloat(step, 'f', -1, 64)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
+
+// roundToStep rounds value to a multiple of step using round
+func roundToStep(value, step float64, round func(float64) float64) float64 {
+	if step <= 0 {
+		return value
+	}
+	// The epsilon keeps exact multiples from dropping a step to float error
+	steps := round(value/step + 1e-9)
+	scale := math.Pow10(stepDecimals(step))
+	return math.Round(steps*step*scale) / scale
+}
+
+// RoundingMode selects how sizes are rounded to the step size
+type RoundingMode string
+
+const (
+	// RoundingDown never exceeds the computed size, so risk limits hold
+	RoundingDown RoundingMode = "down"
+	// RoundingNearest rounds to the closest step
+	RoundingNearest RoundingMode = "nearest"
+	// RoundingUp never falls short of the computed size
+	RoundingUp RoundingMode = "up"
+)
+
+// ParseRoundingMode parses a rounding mode case-insensitively
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	switch mode := RoundingMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case RoundingDown, RoundingNearest, RoundingUp:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown rounding mode %q", s)
+	}
+}
+
+// RoundQuantity rounds q to a multiple of step using mode; a zero step leaves q unchanged
+func RoundQuantity(q float64, mode RoundingMode, step float64) float64 {
+	switch mode {
+	case RoundingNearest:
+		return roundToStep(q, step, math.Round)
+	case RoundingUp:
+		// Offset the epsilon roundToStep adds so exact multiples do not gain a step
+		return roundToStep(q-2e-9*step, step, math.Ceil)
+	default:
+		return roundToStep(q, step, math.Floor)
+	}
+}
+
+// RoundQuantity rounds a quantity down to the step size so it never exceeds the computed size
+func (f SymbolFilter) RoundQuantity(q float64) float64 {
+	return RoundQuantity(q, RoundingDown, f.StepSize)
+}
+
+// RoundPrice rounds a price to the nearest tick
+func (f SymbolFilter) RoundPrice(p float64) float64 {
+	if f.TickSize <= 0 {
+		return p
+	}
+	return roundToStep(p, f.TickSize, math.Round)
+}
+
+// SymbolFilter returns the configured exchange filter for symbol
+func (c *Config) SymbolFilter(symbol string) SymbolFilter {
+	return c.Trading.SymbolFilters[symbol]
+}
+
+// orderAccount supplies the market prices and equity orders are validated against
+type orderAccount interface {
+	LastPrice(symbol string) (float64, bool)
+	Equity() float64
+}
+
+// filteredExecutor validates orders and rounds them to their symbol filter before submitting
+// them
+type filteredExecutor struct {
+	mu     sync.Mutex
+	next   OrderExecutor
+	config *Config
+	// Account orders are validated against, set by the bot submitting through the executor;
+	// without one the slippage and equity checks are skipped
+	account orderAccount
+}
+
+// reconfigure switches the executor to the symbol filters and rounding mode of c
+func (e *filteredExecutor) reconfigure(c *Config) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.config = c
+}
+
+// Submit rounds the order quantity and prices, rejecting orders that round to zero and
+// stop-limit orders without a stop price. Entries are checked by ValidateOrder against the
+// account's last price and equity first; exits are not, so a position can always be closed.
+// Entry quantities use the configured RoundingMode; reduce-only exits always round down so they
+// stay within the open quantity.
+func (e *filteredExecutor) Submit(ctx context.Context, order Order) (Fill, error) {
+	if order.Type == OrderTypeStopLimit && order.StopPrice <= 0 {
+		return Fill{}, fmt.Errorf("%s order for %s requires a stop price", order.Type, order.Symbol)
+	}
+	e.mu.Lock()
+	config := e.config
+	e.mu.Unlock()
+	if !order.Exit && !order.ReduceOnly {
+		marketPrice, equity := 0.0, math.Inf(1)
+		if e.account != nil {
+			marketPrice, _ = e.account.LastPrice(order.Symbol)
+			equity = e.account.Equity()
+		}
+		if err := config.ValidateOrder(order, marketPrice, equity); err != nil {
+			return Fill{}, err
+		}
+	}
+	filter := config.SymbolFilter(order.Symbol)
+	rounded := order
+	if order.ReduceOnly {
+		rounded.Quantity = filter.RoundQuantity(order.Quantity)
+	} else {
+		rounded.Quantity = RoundQuantity(order.Quantity, config.Trading.RoundingMode, filter.StepSize)
+	}
+	rounded.Price = filter.RoundPrice(order.Price)
+	rounded.StopPrice = filter.RoundPrice(order.StopPrice)
+	if rounded.Quantity <= 0 {
+		return Fill{}, fmt.Errorf("order quantity %f for %s rounds to zero at step size %f", order.Quantity, order.Symbol, filter.StepSize)
+	}
+	return e.next.Submit(ctx, rounded)
+}
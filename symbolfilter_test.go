@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFilteredExecutorRequiresStopPriceForStopLimit(t *testing.T) {
+	c := DefaultConfig()
+	next := &fakeExecutor{fillRatio: 1}
+	executor := &filteredExecutor{next: next, config: c}
+
+	order := Order{Symbol: "BNBUSDT", Side: SideBuy, Quantity: 1, Price: 100, Type: OrderTypeStopLimit}
+	if _, err := executor.Submit(context.Background(), order); err == nil {
+		t.Error("expected a stop-limit order without a stop price to be rejected")
+	}
+	if len(next.orders) != 0 {
+		t.Errorf("rejected order reached the exchange: %+v", next.orders)
+	}
+
+	order.StopPrice = 101
+	if _, err := executor.Submit(context.Background(), order); err != nil {
+		t.Fatalf("Submit: %v", err)
+	}
+	if next.orders[0].StopPrice != 101 {
+		t.Errorf("stop price = %f, want 101", next.orders[0].StopPrice)
+	}
+}
+
+func TestFilteredExecutorRejectsInvalidEntries(t *testing.T) {
+	c := DefaultConfig()
+	c.FixedCapital.MaxCapitalPerTrade = 500
+	next := &fakeExecutor{fillRatio: 1}
+	executor := &filteredExecutor{next: next, config: c}
+
+	for _, order := range []Order{
+		{Symbol: "BNBUSDT", Side: SideBuy, Quantity: c.Trading.MinOrderQuantity / 2, Price: 100, Type: OrderTypeMarket},
+		{Symbol: "BNBUSDT", Side: SideBuy, Quantity: 10, Price: 100, Type: OrderTypeMarket},
+	} {
+		var rejected *OrderRejectedError
+		if _, err := executor.Submit(context.Background(), order); !errors.As(err, &rejected) {
+			t.Errorf("Submit(%+v) = %v, want the order rejected", order, err)
+		}
+	}
+	if len(next.orders) != 0 {
+		t.Errorf("invalid orders reached the wrapped executor: %+v", next.orders)
+	}
+
+	// Exits are never held to the entry limits
+	exit := Order{Symbol: "BNBUSDT", Side: SideSell, Quantity: 10, Price: 100, Type: OrderTypeMarket, Exit: true}
+	if _, err := executor.Submit(context.Background(), exit); err != nil {
+		t.Fatalf("Submit exit: %v", err)
+	}
+}
+
+func TestBotEntriesAreValidatedAgainstEquity(t *testing.T) {
+	c := DefaultConfig()
+	c.Logging.LogConfigOnStart = false
+	c.FixedCapital.TotalCapital = 100
+	next := &fakeExecutor{fillRatio: 1}
+	executor, err := c.NewExecutor(next)
+	if err != nil {
+		t.Fatalf("NewExecutor: %v", err)
+	}
+	bot, err := NewBot(c, executor, nil, nil)
+	if err != nil {
+		t.Fatalf("NewBot: %v", err)
+	}
+
+	order := Order{Symbol: "BNBUSDT", Side: SideBuy, Quantity: 1, Price: 300, Type: OrderTypeMarket}
+	var rejected *OrderRejectedError
+	if _, err := bot.submit(context.Background(), order); !errors.As(err, &rejected) || rejected.Reason != RejectInsufficientEquity {
+		t.Fatalf("submit = %v, want an insufficient equity rejection", err)
+	}
+	if len(next.orders) != 0 {
+		t.Errorf("rejected entry reached the exchange: %+v", next.orders)
+	}
+}
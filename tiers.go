@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+)
+
+// tierPercentageEpsilon absorbs floating point error when summing close percentages
+const tierPercentageEpsilon = 1e-9
+
+// CalculateTierQuantities returns the quantity to close at each tier, indexed like Tiers.
+// Disabled tiers get zero, and the remainder is assigned to the last enabled tier so the
+// quantities sum exactly to totalQuantity.
+func (c *MultiTierConfig) CalculateTierQuantities(totalQuantity float64) ([]float64, error) {
+	lastEnabled := -1
+	var totalPercentage float64
+	for i, tier := range c.Tiers {
+		if !tier.Enabled {
+			continue
+		}
+		totalPercentage += tier.ClosePercentage
+		lastEnabled = i
+	}
+	if lastEnabled < 0 {
+		return nil, fmt.Errorf("no enabled profit tiers")
+	}
+	if totalPercentage > 1+tierPercentageEpsilon {
+		return nil, fmt.Errorf("enabled tier close percentages sum to %f, exceeding 1.0", totalPercentage)
+	}
+
+	quantities := make([]float64, len(c.Tiers))
+	var allocated float64
+	for i, tier := range c.Tiers {
+		if !tier.Enabled || i == lastEnabled {
+			continue
+		}
+		quantities[i] = totalQuantity * tier.ClosePercentage
+		allocated += quantities[i]
+	}
+	quantities[lastEnabled] = totalQuantity - allocated
+
+	return quantities, nil
+}
+
+// SortTiers sorts the enabled tiers ascending by ProfitPercentage in place.
+// Disabled and absolute-price tiers keep their positions; enabled percentage tiers are
+// reordered among the remaining slots.
+// LoadConfig calls this before Validate when AutoSort is set, so the ascending check passes.
+func (c *MultiTierConfig) SortTiers() {
+	var slots []int
+	var enabled []TierProfit
+	for i, tier := range c.Tiers {
+		if tier.Enabled && tier.TargetPrice == 0 {
+			slots = append(slots, i)
+			enabled = append(enabled, tier)
+		}
+	}
+
+	sort.SliceStable(enabled, func(i, j int) bool {
+		return enabled[i].ProfitPercentage < enabled[j].ProfitPercentage
+	})
+
+	for i, slot := range slots {
+		c.Tiers[slot] = enabled[i]
+	}
+}
+
+// TargetFor returns the tier's target price for a position entered at entryPrice
+func (t TierProfit) TargetFor(entryPrice float64, side string) float64 {
+	if t.TargetPrice > 0 {
+		return t.TargetPrice
+	}
+	if isLong(side) {
+		return entryPrice * (1 + t.ProfitPercentage/100)
+	}
+	return entryPrice * (1 - t.ProfitPercentage/100)
+}
+
+// TierTargetPrices returns the target price of each tier, indexed like Tiers; disabled tiers get zero
+func (c *MultiTierConfig) TierTargetPrices(entryPrice float64, side string) []float64 {
+	targets := make([]float64, len(c.Tiers))
+	for i, tier := range c.Tiers {
+		if tier.Enabled {
+			targets[i] = tier.TargetFor(entryPrice, side)
+		}
+	}
+	return targets
+}
+
+// ValidateTierTargets checks that every enabled tier target is on the profitable side of
+// entryPrice and that targets move strictly away from entry in tier order
+func (c *MultiTierConfig) ValidateTierTargets(entryPrice float64, side string) error {
+	long := isLong(side)
+	previous := entryPrice
+	previousIndex := -1
+	for i, target := range c.TierTargetPrices(entryPrice, side) {
+		if !c.Tiers[i].Enabled {
+			continue
+		}
+		if (long && target <= entryPrice) || (!long && target >= entryPrice) {
+			return fmt.Errorf("tier %d target %f is on the wrong side of entry %f for a %s position", i, target, entryPrice, side)
+		}
+		if previousIndex >= 0 && ((long && target <= previous) || (!long && target >= previous)) {
+			return fmt.Errorf("tier %d target %f is not beyond tier %d target %f", i, target, previousIndex, previous)
+		}
+		previous = target
+		previousIndex = i
+	}
+	return nil
+}
+
+// TierState records a position's profit tiers and which of them have closed, so each tier
+// fires at most once, including across restarts
+type TierState struct {
+	// Enabled tiers when the position opened, absolute targets converted to profit percentages
+	// from the entry, so a config change cannot renumber them
+	Tiers []TierProfit
+	// Executed[i] reports whether Tiers[i] has closed its share
+	Executed []bool
+}
+
+// NewTierState snapshots the enabled tiers for a position entered at entryPrice, ascending by
+// profit percentage once absolute targets are converted, so NextTier can stop at the first
+// tier not yet reached. It returns an error when an absolute target is not on the profitable
+// side of entryPrice for side.
+func (c *MultiTierConfig) NewTierState(entryPrice float64, side string) (TierState, error) {
+	var state TierState
+	for i, tier := range c.Tiers {
+		if !tier.Enabled {
+			continue
+		}
+		if tier.TargetPrice > 0 && entryPrice > 0 {
+			tier.ProfitPercentage = -unrealizedLossFraction(entryPrice, tier.TargetPrice, side) * 100
+			if tier.ProfitPercentage <= 0 {
+				return TierState{}, fmt.Errorf("tier %d target %f is on the wrong side of entry %f for a %s position", i, tier.TargetPrice, entryPrice, side)
+			}
+			tier.TargetPrice = 0
+		}
+		state.Tiers = append(state.Tiers, tier)
+	}
+	sort.SliceStable(state.Tiers, func(i, j int) bool {
+		return state.Tiers[i].ProfitPercentage < state.Tiers[j].ProfitPercentage
+	})
+	state.Executed = make([]bool, len(state.Tiers))
+	return state, nil
+}
+
+// NextTier returns the first tier not yet executed whose profit percentage currentProfitPct has
+// reached, with its index, or false when none is due
+func (s *TierState) NextTier(currentProfitPct float64) (*TierProfit, int, bool) {
+	for i := range s.Tiers {
+		if s.Executed[i] {
+			continue
+		}
+		if currentProfitPct >= s.Tiers[i].ProfitPercentage {
+			return &s.Tiers[i], i, true
+		}
+		// Tiers are ascending, so later ones cannot be due either
+		break
+	}
+	return nil, 0, false
+}
+
+// MarkExecuted records that tier i has closed its share
+func (s *TierState) MarkExecuted(i int) {
+	s.Executed[i] = true
+}
+
+// CloseQuantity returns the share of openQuantity tier i closes: its close percentage relative
+// to the tiers still pending, so the last pending tier closes everything left
+func (s *TierState) CloseQuantity(i int, openQuantity float64) float64 {
+	var pending float64
+	for j, tier := range s.Tiers {
+		if !s.Executed[j] {
+			pending += tier.ClosePercentage
+		}
+	}
+	if pending <= tierPercentageEpsilon {
+		return 0
+	}
+	lastPending := true
+	for j := i + 1; j < len(s.Tiers); j++ {
+		if !s.Executed[j] {
+			lastPending = false
+			break
+		}
+	}
+	if lastPending {
+		return openQuantity
+	}
+	return openQuantity * s.Tiers[i].ClosePercentage / pending
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestCalculateTierQuantities(t *testing.T) {
+	third := 1.0 / 3
+	tests := []struct {
+		name    string
+		tiers   []TierProfit
+		total   float64
+		want    []float64
+		wantErr bool
+	}{
+		{
+			name:  "even split",
+			tiers: []TierProfit{{ClosePercentage: 0.5, Enabled: true}, {ClosePercentage: 0.5, Enabled: true}},
+			total: 4,
+			want:  []float64{2, 2},
+		},
+		{
+			// Thirds do not divide 1 exactly; the last tier absorbs the rounding
+			name:  "rounding",
+			tiers: []TierProfit{{ClosePercentage: third, Enabled: true}, {ClosePercentage: third, Enabled: true}, {ClosePercentage: third, Enabled: true}},
+			total: 1,
+			want:  []float64{third, third, 1 - 2*third},
+		},
+		{
+			name:  "remainder to the last tier",
+			tiers: []TierProfit{{ClosePercentage: 0.25, Enabled: true}, {ClosePercentage: 0.25, Enabled: true}},
+			total: 8,
+			want:  []float64{2, 6},
+		},
+		{
+			name:  "disabled tiers get nothing",
+			tiers: []TierProfit{{ClosePercentage: 0.5, Enabled: true}, {ClosePercentage: 0.3, Enabled: true}, {ClosePercentage: 0.2}},
+			total: 10,
+			want:  []float64{5, 5, 0},
+		},
+		{
+			name:    "no enabled tiers",
+			tiers:   []TierProfit{{ClosePercentage: 1}},
+			total:   1,
+			wantErr: true,
+		},
+		{
+			name:    "percentages past 1.0",
+			tiers:   []TierProfit{{ClosePercentage: 0.6, Enabled: true}, {ClosePercentage: 0.6, Enabled: true}},
+			total:   1,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		c := MultiTierConfig{Tiers: tt.tiers}
+		got, err := c.CalculateTierQuantities(tt.total)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: CalculateTierQuantities = %v, want an error", tt.name, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: CalculateTierQuantities: %v", tt.name, err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: CalculateTierQuantities = %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		var sum float64
+		for i := range got {
+			sum += got[i]
+			if math.Abs(got[i]-tt.want[i]) > 1e-12 {
+				t.Errorf("%s: quantity %d = %v, want %v", tt.name, i, got[i], tt.want[i])
+			}
+		}
+		if sum != tt.total {
+			t.Errorf("%s: quantities sum to %v, want exactly %v", tt.name, sum, tt.total)
+		}
+	}
+}
+
+func TestNewTierStateSortsConvertedAbsoluteTiers(t *testing.T) {
+	c := MultiTierConfig{Tiers: []TierProfit{
+		{ProfitPercentage: 2, ClosePercentage: 0.5, Enabled: true},
+		// 1% above a 100 entry
+		{TargetPrice: 101, ClosePercentage: 0.5, Enabled: true},
+	}}
+	state, err := c.NewTierState(100, SideLong)
+	if err != nil {
+		t.Fatalf("NewTierState: %v", err)
+	}
+	if len(state.Tiers) != 2 || state.Tiers[0].ProfitPercentage > state.Tiers[1].ProfitPercentage {
+		t.Fatalf("tiers not ascending: %+v", state.Tiers)
+	}
+	tier, index, due := state.NextTier(1.5)
+	if !due || index != 0 || tier.ClosePercentage != 0.5 {
+		t.Fatalf("NextTier(1.5) = %v, %d, %v; want the converted 1%% tier due", tier, index, due)
+	}
+}
+
+func TestNewTierStateRejectsTargetsOnTheLosingSide(t *testing.T) {
+	c := MultiTierConfig{Tiers: []TierProfit{
+		{ProfitPercentage: 1, ClosePercentage: 0.5, Enabled: true},
+		{TargetPrice: 105, ClosePercentage: 0.5, Enabled: true},
+	}}
+	if _, err := c.NewTierState(100, SideLong); err != nil {
+		t.Errorf("NewTierState for a long below its target: %v", err)
+	}
+	if _, err := c.NewTierState(100, SideShort); err == nil {
+		t.Error("NewTierState accepted a target above a short's entry")
+	}
+
+	c.Tiers[1].TargetPrice = 95
+	if _, err := c.NewTierState(100, SideShort); err != nil {
+		t.Errorf("NewTierState for a short above its target: %v", err)
+	}
+	if _, err := c.NewTierState(100, SideLong); err == nil {
+		t.Error("NewTierState accepted a target below a long's entry")
+	}
+	// A target at the entry is no profit for either side
+	c.Tiers[1].TargetPrice = 100
+	for _, side := range []string{SideLong, SideShort} {
+		if _, err := c.NewTierState(100, side); err == nil {
+			t.Errorf("NewTierState accepted a %s target at the entry", side)
+		}
+	}
+}
+
+func TestTierStateCloseQuantity(t *testing.T) {
+	state := TierState{
+		Tiers:    []TierProfit{{ProfitPercentage: 1, ClosePercentage: 0.25}, {ProfitPercentage: 2, ClosePercentage: 0.75}},
+		Executed: []bool{false, false},
+	}
+	if got := state.CloseQuantity(0, 8); got != 2 {
+		t.Errorf("first tier CloseQuantity = %f, want 2", got)
+	}
+	state.MarkExecuted(0)
+	if got := state.CloseQuantity(1, 6); got != 6 {
+		t.Errorf("last tier CloseQuantity = %f, want everything left", got)
+	}
+}
+
+func TestSortTiersKeepsDisabledAndAbsoluteSlots(t *testing.T) {
+	c := MultiTierConfig{Tiers: []TierProfit{
+		{ProfitPercentage: 3, ClosePercentage: 0.3, Enabled: true},
+		{ProfitPercentage: 0.5, ClosePercentage: 0.2, Enabled: false},
+		{TargetPrice: 150, ClosePercentage: 0.2, Enabled: true},
+		{ProfitPercentage: 1, ClosePercentage: 0.5, Enabled: true},
+	}}
+	c.SortTiers()
+
+	want := []TierProfit{
+		{ProfitPercentage: 1, ClosePercentage: 0.5, Enabled: true},
+		{ProfitPercentage: 0.5, ClosePercentage: 0.2, Enabled: false},
+		{TargetPrice: 150, ClosePercentage: 0.2, Enabled: true},
+		{ProfitPercentage: 3, ClosePercentage: 0.3, Enabled: true},
+	}
+	for i := range want {
+		if c.Tiers[i] != want[i] {
+			t.Errorf("tier %d = %+v, want %+v", i, c.Tiers[i], want[i])
+		}
+	}
+}
+
+func TestSortTiersLetsUnorderedTiersValidate(t *testing.T) {
+	c := testnetConfig()
+	tiers := c.MultiTier.Tiers
+	tiers[0], tiers[len(tiers)-1] = tiers[len(tiers)-1], tiers[0]
+	if err := c.Validate(); err == nil {
+		t.Fatal("Validate accepted descending tiers")
+	}
+	c.MultiTier.SortTiers()
+	if err := c.Validate(); err != nil {
+		t.Errorf("Validate after SortTiers = %v", err)
+	}
+}
+
+func TestTierTargetPricesMixesAbsoluteAndPercentage(t *testing.T) {
+	c := MultiTierConfig{Tiers: []TierProfit{
+		{ProfitPercentage: 1, ClosePercentage: 0.5, Enabled: true},
+		{TargetPrice: 105, ClosePercentage: 0.5, Enabled: true},
+		{ProfitPercentage: 10, ClosePercentage: 1, Enabled: false},
+	}}
+	targets := c.TierTargetPrices(100, SideLong)
+	if targets[0] != 101 || targets[1] != 105 || targets[2] != 0 {
+		t.Errorf("long targets = %v, want [101 105 0]", targets)
+	}
+	if err := c.ValidateTierTargets(100, SideLong); err != nil {
+		t.Errorf("ValidateTierTargets(long) = %v", err)
+	}
+	// 105 is on the losing side of a short entered at 100
+	if err := c.ValidateTierTargets(100, SideShort); err == nil {
+		t.Error("ValidateTierTargets(short) accepted a target above entry")
+	}
+	// The absolute target sits below the 1% tier of an entry at 104
+	if err := c.ValidateTierTargets(104, SideLong); err == nil {
+		t.Error("ValidateTierTargets accepted targets out of order")
+	}
+}
+
+func TestHandleSignalSkipsCopyWithTargetsOnTheLosingSide(t *testing.T) {
+	executor := &fakeExecutor{fillRatio: 1}
+	bot := newTestBot(t, executor)
+	config := bot.config.Load()
+	config.MultiTier.Enabled = true
+	config.MultiTier.Tiers = []TierProfit{{TargetPrice: 290, ClosePercentage: 1, Enabled: true}}
+
+	if p, err := bot.HandleSignal(context.Background(), testSignal()); err != nil || p != nil {
+		t.Fatalf("HandleSignal = %+v, %v, want a buy at 300 with a 290 target skipped", p, err)
+	}
+	if len(executor.orders) != 0 {
+		t.Errorf("submitted %+v, want no entry", executor.orders)
+	}
+}
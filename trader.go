@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// traderShutdownTimeout bounds how long the trader may take to persist state on shutdown
+const traderShutdownTimeout = 30 * time.Second
+
+// runTrader runs the exchange copy trader until ctx is done. Leader signals arrive on the
+// control server's POST /signal; every refresh interval the prices of the traded symbols are
+// fetched, from the market data stream in WebSocket mode with REST as the fallback, and the
+// open positions managed. A SIGHUP reloads the configuration into the bot.
+func runTrader(ctx context.Context, live *LiveConfig) error {
+	config := live.Get()
+	logger, err := NewLogger(config.Logging)
+	if err != nil {
+		return err
+	}
+	store, err := config.NewStateStore()
+	if err != nil {
+		return err
+	}
+
+	client := config.NewBinanceClient()
+	var liveExecutor OrderExecutor
+	var orders OrderManager
+	if config.TradesLive() {
+		if err := client.Clock().Sync(ctx); err != nil {
+			return fmt.Errorf("error syncing with the exchange clock: %v", err)
+		}
+		go client.Clock().Run(ctx)
+		liveExecutor, orders = client, client
+	}
+	executor, err := config.NewExecutor(liveExecutor)
+	if err != nil {
+		return err
+	}
+	bot, err := NewBot(config, executor, logger, store)
+	if err != nil {
+		return err
+	}
+	trades, err := config.NewTradeStore()
+	if err != nil {
+		return err
+	}
+	if trades != nil {
+		defer trades.Close()
+		bot.EnableTradeHistory(trades)
+	}
+	if config.EntryImprovement.Enabled && orders != nil {
+		bot.EnableEntryImprovement(orders)
+	}
+	if config.Trading.UseOCOExits && orders != nil {
+		if err := bot.EnableOCOExits(ctx, config.NewOCOExits(client)); err != nil {
+			logger.Error("Error placing OCO exits of open positions: %v", err)
+		}
+	}
+	if config.DeadMansSwitchEnabled && orders != nil {
+		if err := bot.EnableDeadMansSwitch(ctx, config.NewDeadMansSwitch(client, client)); err != nil {
+			logger.Error("Error protecting open positions: %v", err)
+		}
+	}
+
+	if config.MetricsEnabled {
+		metrics := NewMetrics()
+		bot.EnableMetrics(metrics)
+		go func() {
+			if err := metrics.Serve(ctx, config.MetricsPort); err != nil {
+				logger.Error("%v", err)
+			}
+		}()
+	}
+	prices := PriceFunc(client.Price)
+	var stream *MarketDataStream
+	if config.MarketDataMode == MarketDataWebSocket {
+		stream, err = config.NewMarketDataStream(client.Price)
+		if err != nil {
+			return err
+		}
+		go stream.Run(ctx)
+		go stream.Track()
+		prices = stream.Price
+	}
+	if config.TradesLive() {
+		go config.NewReconciler(client.TotalEquity, bot, config.NewNotifier()).Run(ctx)
+	}
+
+	if config.HealthEnabled {
+		health := NewHealthServer()
+		health.AddCheck("config", ConfigHealthCheck(live))
+		health.AddCheck("exchange", ExchangeHealthCheck(client.ServerTime))
+		if stream != nil {
+			health.AddCheck("market data", stream.HealthCheck)
+		}
+		health.EnableControl(bot, config.ControlToken)
+		health.EnableLiveness(bot)
+		go func() {
+			if err := health.Serve(ctx, config.HealthPort); err != nil {
+				logger.Error("%v", err)
+			}
+		}()
+	}
+
+	go live.WatchSIGHUP(ctx, func(reloaded *Config, err error) {
+		if err != nil {
+			logger.Error("Configuration not reloaded: %v", err)
+			return
+		}
+		bot.Reconfigure(reloaded)
+		logger.Info("🔄 Configuration reloaded")
+	})
+
+	logger.Info("🚀 Copy trader started on %s in %s mode trading %v", client.baseURL, config.ExecutionMode, config.Pairs())
+	schedule := config.NewRefreshSchedule()
+	interval := time.Duration(config.RefreshInterval) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), traderShutdownTimeout)
+			defer cancel()
+			_, err := bot.Shutdown(shutdownCtx, false)
+			return err
+		case now := <-ticker.C:
+			bot.CheckLiveness(ctx, now)
+			if err := bot.RefreshPrices(ctx, bot.TradedSymbols(), prices); err != nil {
+				logger.Warn("%v", err)
+			}
+			if err := bot.Manage(ctx, now, orders); err != nil {
+				logger.Error("Error managing positions: %v", err)
+			}
+			bot.ReportMetrics()
+
+			refreshed := make(map[string]float64)
+			for _, symbol := range bot.TradedSymbols() {
+				if price, ok := bot.LastPrice(symbol); ok {
+					refreshed[symbol] = price
+				}
+			}
+			if next := schedule.Next(live.Get(), refreshed); next != interval {
+				interval = next
+				ticker.Reset(interval)
+			}
+		}
+	}
+}
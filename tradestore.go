@@ -0,0 +1,165 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// TradeRecord is a closed trade kept in the trade history
+type TradeRecord struct {
+	ID         int64
+	Symbol     string
+	Side       string
+	EntryPrice float64
+	ExitPrice  float64
+	Quantity   float64
+	Fees       float64
+	NetProfit  float64
+	OpenedAt   time.Time
+	ClosedAt   time.Time
+	// Leader the trade was copied from, empty for the bot's own trades
+	LeaderAddress string
+}
+
+// TradeStats aggregates the trade history
+type TradeStats struct {
+	Trades    int
+	Wins      int
+	Losses    int
+	NetProfit float64
+	Fees      float64
+}
+
+// WinRate returns the fraction of trades that were profitable, 0 without trades
+func (s TradeStats) WinRate() float64 {
+	if s.Trades == 0 {
+		return 0
+	}
+	return float64(s.Wins) / float64(s.Trades)
+}
+
+// TradeStore persists the history of closed trades
+type TradeStore interface {
+	// RecordTrade stores a closed trade and returns its ID
+	RecordTrade(trade TradeRecord) (int64, error)
+	// TradesSince returns the trades closed at or after t, oldest first
+	TradesSince(t time.Time) ([]TradeRecord, error)
+	// AggregateStats summarizes every stored trade
+	AggregateStats() (TradeStats, error)
+	Close() error
+}
+
+// NewTradeStore opens the trade history at TradeHistoryPath, nil when trade history is disabled
+func (c *Config) NewTradeStore() (TradeStore, error) {
+	if c.TradeHistoryPath == "" {
+		return nil, nil
+	}
+	return NewSQLiteTradeStore(c.TradeHistoryPath)
+}
+
+const createTradesTable = `CREATE TABLE IF NOT EXISTS trades (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	symbol TEXT NOT NULL,
+	side TEXT NOT NULL,
+	entry_price REAL NOT NULL,
+	exit_price REAL NOT NULL,
+	quantity REAL NOT NULL,
+	fees REAL NOT NULL,
+	net_profit REAL NOT NULL,
+	opened_at INTEGER NOT NULL,
+	closed_at INTEGER NOT NULL,
+	leader_address TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS trades_closed_at ON trades (closed_at);`
+
+// SQLiteTradeStore stores the trade history in a SQLite database
+type SQLiteTradeStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteTradeStore opens or creates the SQLite trade history at path
+func NewSQLiteTradeStore(path string) (*SQLiteTradeStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("error creating trade history directory: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("error opening trade history %s: %v", path, err)
+	}
+	// SQLite allows a single writer
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(createTradesTable); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating trade history schema: %v", err)
+	}
+	return &SQLiteTradeStore{db: db}, nil
+}
+
+// RecordTrade stores a closed trade and returns its ID
+func (s *SQLiteTradeStore) RecordTrade(trade TradeRecord) (int64, error) {
+	result, err := s.db.Exec(
+		`INSERT INTO trades (symbol, side, entry_price, exit_price, quantity, fees, net_profit, opened_at, closed_at, leader_address)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		trade.Symbol, trade.Side, trade.EntryPrice, trade.ExitPrice, trade.Quantity, trade.Fees, trade.NetProfit,
+		trade.OpenedAt.UnixMilli(), trade.ClosedAt.UnixMilli(), trade.LeaderAddress)
+	if err != nil {
+		return 0, fmt.Errorf("error recording trade: %v", err)
+	}
+	return result.LastInsertId()
+}
+
+// TradesSince returns the trades closed at or after t, oldest first
+func (s *SQLiteTradeStore) TradesSince(t time.Time) ([]TradeRecord, error) {
+	rows, err := s.db.Query(
+		`SELECT id, symbol, side, entry_price, exit_price, quantity, fees, net_profit, opened_at, closed_at, leader_address
+		FROM trades WHERE closed_at >= ? ORDER BY closed_at, id`, t.UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("error querying trades: %v", err)
+	}
+	defer rows.Close()
+
+	var trades []TradeRecord
+	for rows.Next() {
+		var trade TradeRecord
+		var openedAt, closedAt int64
+		if err := rows.Scan(&trade.ID, &trade.Symbol, &trade.Side, &trade.EntryPrice, &trade.ExitPrice, &trade.Quantity,
+			&trade.Fees, &trade.NetProfit, &openedAt, &closedAt, &trade.LeaderAddress); err != nil {
+			return nil, fmt.Errorf("error reading trade: %v", err)
+		}
+		trade.OpenedAt = time.UnixMilli(openedAt).UTC()
+		trade.ClosedAt = time.UnixMilli(closedAt).UTC()
+		trades = append(trades, trade)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading trades: %v", err)
+	}
+	return trades, nil
+}
+
+// AggregateStats summarizes every stored trade
+func (s *SQLiteTradeStore) AggregateStats() (TradeStats, error) {
+	var stats TradeStats
+	err := s.db.QueryRow(
+		`SELECT COUNT(*),
+			COALESCE(SUM(CASE WHEN net_profit > 0 THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN net_profit < 0 THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(net_profit), 0),
+			COALESCE(SUM(fees), 0)
+		FROM trades`).Scan(&stats.Trades, &stats.Wins, &stats.Losses, &stats.NetProfit, &stats.Fees)
+	if err != nil {
+		return TradeStats{}, fmt.Errorf("error aggregating trades: %v", err)
+	}
+	return stats, nil
+}
+
+// Close closes the database
+func (s *SQLiteTradeStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newTestTradeStore opens a trade history in a temporary directory
+func newTestTradeStore(t *testing.T) *SQLiteTradeStore {
+	t.Helper()
+	store, err := NewSQLiteTradeStore(filepath.Join(t.TempDir(), "history", "trades.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteTradeStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestTradeStoreTradesSince(t *testing.T) {
+	store := newTestTradeStore(t)
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, profit := range []float64{5, -2, 7} {
+		closedAt := start.Add(time.Duration(i) * time.Hour)
+		trade := TradeRecord{Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 300, ExitPrice: 305, Quantity: 1, Fees: 0.6, NetProfit: profit, OpenedAt: closedAt.Add(-time.Hour), ClosedAt: closedAt, LeaderAddress: "0xleader"}
+		if _, err := store.RecordTrade(trade); err != nil {
+			t.Fatalf("RecordTrade: %v", err)
+		}
+	}
+
+	trades, err := store.TradesSince(start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("TradesSince: %v", err)
+	}
+	if len(trades) != 2 || trades[0].NetProfit != -2 || trades[1].NetProfit != 7 {
+		t.Fatalf("TradesSince = %+v, want the last two trades oldest first", trades)
+	}
+	if !trades[0].ClosedAt.Equal(start.Add(time.Hour)) || trades[0].LeaderAddress != "0xleader" || trades[0].Side != SideLong {
+		t.Errorf("trade = %+v, want its fields round-tripped", trades[0])
+	}
+	if later, _ := store.TradesSince(start.Add(3 * time.Hour)); len(later) != 0 {
+		t.Errorf("TradesSince after the last close = %+v, want none", later)
+	}
+}
+
+func TestTradeStoreAggregateStats(t *testing.T) {
+	store := newTestTradeStore(t)
+	if stats, err := store.AggregateStats(); err != nil || stats != (TradeStats{}) || stats.WinRate() != 0 {
+		t.Fatalf("AggregateStats of an empty history = %+v, %v; want zeros", stats, err)
+	}
+
+	now := time.Now()
+	for _, profit := range []float64{10, -4, 6, 0} {
+		if _, err := store.RecordTrade(TradeRecord{Symbol: "BNBUSDT", Side: SideLong, Fees: 0.5, NetProfit: profit, OpenedAt: now, ClosedAt: now}); err != nil {
+			t.Fatalf("RecordTrade: %v", err)
+		}
+	}
+	stats, err := store.AggregateStats()
+	if err != nil {
+		t.Fatalf("AggregateStats: %v", err)
+	}
+	want := TradeStats{Trades: 4, Wins: 2, Losses: 1, NetProfit: 12, Fees: 2}
+	if stats != want || stats.WinRate() != 0.5 {
+		t.Errorf("AggregateStats = %+v (win rate %f), want %+v and 0.5", stats, stats.WinRate(), want)
+	}
+}
+
+func TestBotRecordsClosedTradesInHistory(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	store := newTestTradeStore(t)
+	bot.EnableTradeHistory(store)
+	opened := time.Now().Add(-time.Hour)
+	bot.TrackPosition(&Position{ID: "p", Symbol: "BNBUSDT", Side: SideLong, EntryPrice: 100, Quantity: 2, FilledQuantity: 2, OpenedAt: opened, LeaderAddress: "0xa"})
+	bot.UpdatePrice("BNBUSDT", 110)
+
+	if _, err := bot.ClosePosition(t.Context(), "p", 2); err != nil {
+		t.Fatalf("ClosePosition: %v", err)
+	}
+	trades, err := store.TradesSince(opened)
+	if err != nil {
+		t.Fatalf("TradesSince: %v", err)
+	}
+	if len(trades) != 1 {
+		t.Fatalf("%d trades recorded, want 1", len(trades))
+	}
+	trade := trades[0]
+	if trade.Quantity != 2 || trade.ExitPrice != 110 || trade.LeaderAddress != "0xa" || trade.Fees <= 0 || trade.NetProfit != 20-trade.Fees {
+		t.Errorf("recorded trade = %+v, want 2 closed at 110 netting 20 less its fees", trade)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// validateCommand is the subcommand that checks the configuration without trading
+const validateCommand = "validate"
+
+// runValidate loads and validates the configuration, from the file in args when given and
+// else from the environment, and prints the effective values with secrets redacted. It
+// returns the process exit code.
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 1 {
+		fmt.Fprintf(stderr, "usage: %s [config file]\n", validateCommand)
+		return 2
+	}
+
+	var config *Config
+	var err error
+	if len(args) == 1 {
+		config, err = LoadConfigFromFile(args[0])
+	} else {
+		config, err = LoadConfig()
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "❌ Invalid configuration: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintln(stdout, "✅ Configuration is valid")
+	for _, warning := range config.Warnings() {
+		fmt.Fprintf(stdout, "⚠️  %s\n", warning)
+	}
+	fmt.Fprint(stdout, config.Summary())
+	return 0
+}
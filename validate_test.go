@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunValidateAcceptsValidFile(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{"Trading": {"TradingPair": "ETHUSDT", "TestnetEnabled": true, "APISecret": "hunter2"}}`)
+	var stdout, stderr bytes.Buffer
+
+	if code := runValidate([]string{path}, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d, want 0; stderr %q", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Configuration is valid") {
+		t.Errorf("stdout = %q, want the valid configuration reported", stdout.String())
+	}
+	if strings.Contains(stdout.String(), "hunter2") {
+		t.Error("summary printed the API secret")
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("stderr = %q, want nothing", stderr.String())
+	}
+}
+
+func TestRunValidateRejectsInvalidConfiguration(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{"Trading": {"TradingPair": "ETHUSDT", "TestnetEnabled": true}, "RiskManagement": {"StopLossPercentage": -1}}`)
+	var stdout, stderr bytes.Buffer
+
+	if code := runValidate([]string{path}, &stdout, &stderr); code != 1 {
+		t.Fatalf("exit code = %d, want 1", code)
+	}
+	if !strings.Contains(stderr.String(), "Invalid configuration") {
+		t.Errorf("stderr = %q, want the invalid configuration reported", stderr.String())
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("stdout = %q, want nothing", stdout.String())
+	}
+}
+
+func TestRunValidateRejectsExtraArguments(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := runValidate([]string{"a.json", "b.json"}, &stdout, &stderr); code != 2 {
+		t.Fatalf("exit code = %d, want 2", code)
+	}
+	if !strings.Contains(stderr.String(), "usage") {
+		t.Errorf("stderr = %q, want the usage", stderr.String())
+	}
+}
+
+func TestRunValidateReadsEnvironment(t *testing.T) {
+	t.Setenv("TRADING_TESTNET_ENABLED", "true")
+	t.Setenv("TRADING_PAIR", "ETHUSDT")
+	var stdout, stderr bytes.Buffer
+	if code := runValidate(nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("exit code = %d, want 0; stderr %q", code, stderr.String())
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// volumeDays is the number of trailing days fee tiers measure trading volume over
+const volumeDays = 30
+
+// volumeDayLayout keys the daily volume buckets by UTC date
+const volumeDayLayout = "2006-01-02"
+
+// VolumeTracker sums the quote notional the bot trades over the trailing 30 days in daily
+// buckets, selecting the fee tier of later trades
+type VolumeTracker struct {
+	mu   sync.Mutex
+	days map[string]float64
+	now  func() time.Time
+}
+
+// NewVolumeTracker creates an empty volume tracker
+func NewVolumeTracker() *VolumeTracker {
+	return &VolumeTracker{
+		days: make(map[string]float64),
+		now:  time.Now,
+	}
+}
+
+// Record adds the notional of a fill traded now
+func (t *VolumeTracker) Record(notional float64) {
+	if notional <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.days[t.now().UTC().Format(volumeDayLayout)] += notional
+}
+
+// Volume30d returns the notional traded over the last 30 days, today included, dropping older days
+func (t *VolumeTracker) Volume30d() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	oldest := t.now().UTC().AddDate(0, 0, -(volumeDays - 1)).Format(volumeDayLayout)
+	var total float64
+	for day, notional := range t.days {
+		if day < oldest {
+			delete(t.days, day)
+			continue
+		}
+		total += notional
+	}
+	return total
+}
+
+// Days returns a copy of the daily volume buckets for persistence
+func (t *VolumeTracker) Days() map[string]float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	days := make(map[string]float64, len(t.days))
+	for day, notional := range t.days {
+		days[day] = notional
+	}
+	return days
+}
+
+// Restore replaces the daily volume buckets with ones saved by Days
+func (t *VolumeTracker) Restore(days map[string]float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.days = make(map[string]float64, len(days))
+	for day, notional := range days {
+		t.days[day] = notional
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVolumeTrackerSumsTrailingThirtyDays(t *testing.T) {
+	tracker := NewVolumeTracker()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	tracker.Record(100)
+	now = now.AddDate(0, 0, 29)
+	tracker.Record(50)
+	tracker.Record(-10)
+	if got := tracker.Volume30d(); got != 150 {
+		t.Errorf("Volume30d on day 30 = %f, want 150", got)
+	}
+
+	now = now.AddDate(0, 0, 1)
+	if got := tracker.Volume30d(); got != 50 {
+		t.Errorf("Volume30d on day 31 = %f, want 50", got)
+	}
+	if got := len(tracker.Days()); got != 1 {
+		t.Errorf("kept %d days, want 1", got)
+	}
+}
+
+func TestVolumeTrackerRestore(t *testing.T) {
+	tracker := NewVolumeTracker()
+	tracker.now = func() time.Time { return time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC) }
+	tracker.Restore(map[string]float64{"2024-03-09": 200, "2024-01-01": 999})
+	if got := tracker.Volume30d(); got != 200 {
+		t.Errorf("restored Volume30d = %f, want 200", got)
+	}
+}
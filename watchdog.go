@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// LivenessWatchdog flags the bot as degraded when no order has been attempted for longer
+// than the configured timeout while trading is active
+type LivenessWatchdog struct {
+	mu           sync.Mutex
+	timeout      time.Duration
+	notifier     *Notifier
+	lastActivity time.Time
+	degraded     bool
+}
+
+// NewLivenessWatchdog creates a watchdog using LivenessTimeout that sends its WARN alerts
+// through notifier; a nil notifier only logs them
+func (c *Config) NewLivenessWatchdog(now time.Time, notifier *Notifier) *LivenessWatchdog {
+	return &LivenessWatchdog{
+		timeout:      time.Duration(c.LivenessTimeout) * time.Minute,
+		notifier:     notifier,
+		lastActivity: now,
+	}
+}
+
+// reconfigure switches the watchdog to the LivenessTimeout of c
+func (w *LivenessWatchdog) reconfigure(c *Config) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.timeout = time.Duration(c.LivenessTimeout) * time.Minute
+}
+
+// RecordActivity records an order attempt and clears the degraded status
+func (w *LivenessWatchdog) RecordActivity(now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.lastActivity = now
+	w.degraded = false
+}
+
+// Check evaluates liveness at now; active reports whether trading is enabled and in-window.
+// A WARN notification is sent once each time the bot becomes degraded.
+func (w *LivenessWatchdog) Check(ctx context.Context, now time.Time, active bool) bool {
+	w.mu.Lock()
+	if w.timeout <= 0 || !active {
+		// Idle time outside the active window does not count
+		w.lastActivity = now
+		w.mu.Unlock()
+		return false
+	}
+
+	idle := now.Sub(w.lastActivity)
+	if idle <= w.timeout || w.degraded {
+		degraded := w.degraded
+		w.mu.Unlock()
+		return degraded
+	}
+	w.degraded = true
+	w.mu.Unlock()
+
+	message := fmt.Sprintf("⚠️  WARN: no orders attempted for %s, bot may be degraded", idle)
+	log.Print(message)
+	if w.notifier != nil {
+		if err := w.notifier.Send(ctx, message); err != nil {
+			log.Printf("Error sending liveness alert: %v", err)
+		}
+	}
+	return true
+}
+
+// Degraded reports whether the watchdog currently flags the bot as degraded
+func (w *LivenessWatchdog) Degraded() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.degraded
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingWebhook collects the generic webhook messages posted to it
+type recordingWebhook struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (r *recordingWebhook) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var payload webhookPayload
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.mu.Lock()
+	r.messages = append(r.messages, payload.Message)
+	r.mu.Unlock()
+}
+
+func (r *recordingWebhook) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.messages...)
+}
+
+func TestLivenessWatchdogNotifiesPastThreshold(t *testing.T) {
+	webhook := &recordingWebhook{}
+	server := httptest.NewServer(webhook)
+	defer server.Close()
+
+	c := DefaultConfig()
+	c.LivenessTimeout = 30
+	c.WebhookURL = server.URL
+	c.NotificationsEnabled = true
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	watchdog := c.NewLivenessWatchdog(start, c.NewNotifier())
+	ctx := context.Background()
+
+	if watchdog.Check(ctx, start.Add(30*time.Minute), true) {
+		t.Fatal("watchdog degraded at the threshold")
+	}
+	if got := webhook.received(); len(got) != 0 {
+		t.Fatalf("notifications before the threshold: %v", got)
+	}
+
+	if !watchdog.Check(ctx, start.Add(31*time.Minute), true) {
+		t.Fatal("watchdog not degraded past the threshold")
+	}
+	if !watchdog.Degraded() {
+		t.Error("Degraded = false after the alert")
+	}
+	got := webhook.received()
+	if len(got) != 1 || !strings.Contains(got[0], "WARN") {
+		t.Fatalf("notifications = %v, want one WARN", got)
+	}
+
+	// Staying degraded does not alert again
+	watchdog.Check(ctx, start.Add(40*time.Minute), true)
+	if got := webhook.received(); len(got) != 1 {
+		t.Errorf("notifications while still degraded = %d, want 1", len(got))
+	}
+
+	watchdog.RecordActivity(start.Add(41 * time.Minute))
+	if watchdog.Degraded() {
+		t.Error("Degraded = true after activity")
+	}
+}
+
+func TestLivenessWatchdogIgnoresInactiveTime(t *testing.T) {
+	c := DefaultConfig()
+	c.LivenessTimeout = 30
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	watchdog := c.NewLivenessWatchdog(start, nil)
+	ctx := context.Background()
+
+	if watchdog.Check(ctx, start.Add(2*time.Hour), false) {
+		t.Error("watchdog degraded outside the active window")
+	}
+	if watchdog.Check(ctx, start.Add(2*time.Hour+10*time.Minute), true) {
+		t.Error("idle time outside the active window counted toward the threshold")
+	}
+}
+
+func TestBotReportsDegradedOnHealthzUntilAnOrderIsAttempted(t *testing.T) {
+	webhook := &recordingWebhook{}
+	server := httptest.NewServer(webhook)
+	defer server.Close()
+
+	c := DefaultConfig()
+	c.Logging.LogConfigOnStart = false
+	c.CopyTrading.CopyDelay = 0
+	c.CopyTrading.CopyDelayJitter = 0
+	c.LivenessTimeout = 30
+	c.WebhookURL = server.URL
+	c.RiskManagement.MaxBaseAssetExposure = 1
+	bot, err := NewBot(c, &fakeExecutor{fillRatio: 1}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewBot: %v", err)
+	}
+	health := NewHealthServer()
+	health.EnableLiveness(bot)
+	handler := health.Handler()
+	status := func() string {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		var body map[string]interface{}
+		_ = json.NewDecoder(rec.Body).Decode(&body)
+		return body["status"].(string)
+	}
+
+	if !bot.CheckLiveness(context.Background(), time.Now().Add(31*time.Minute)) {
+		t.Fatal("bot not degraded after 31 idle minutes")
+	}
+	if got := status(); got != "degraded" {
+		t.Errorf("/healthz status = %q, want degraded", got)
+	}
+	if got := webhook.received(); len(got) != 1 {
+		t.Errorf("notifications = %v, want one WARN", got)
+	}
+
+	if _, err := bot.HandleSignal(context.Background(), testSignal()); err != nil {
+		t.Fatalf("HandleSignal: %v", err)
+	}
+	if got := status(); got != "ok" {
+		t.Errorf("/healthz status = %q after an order, want ok", got)
+	}
+}
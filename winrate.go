@@ -0,0 +1,126 @@
+package main
+
+import "sync"
+
+// defaultWinRateWindow is the number of recent outcomes kept when no window is given
+const defaultWinRateWindow = 500
+
+// WinRateTracker records trade outcomes and reports all-time and rolling win rates.
+// Break-even trades count toward the total but are neither wins nor losses. All-time
+// statistics are kept as running totals; only the last window outcomes are stored.
+type WinRateTracker struct {
+	mu        sync.Mutex
+	window    int
+	recent    []float64
+	total     int
+	wins      int
+	losses    int
+	winTotal  float64
+	lossTotal float64
+}
+
+// NewWinRateTracker creates an empty win rate tracker keeping the last window outcomes, or
+// defaultWinRateWindow when window is not positive
+func NewWinRateTracker(window int) *WinRateTracker {
+	if window <= 0 {
+		window = defaultWinRateWindow
+	}
+	return &WinRateTracker{window: window}
+}
+
+// RecordTrade records the profit of a closed trade
+func (t *WinRateTracker) RecordTrade(profit float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.recent = append(t.recent, profit)
+	if len(t.recent) > t.window {
+		t.recent = t.recent[len(t.recent)-t.window:]
+	}
+	t.total++
+	switch {
+	case profit > 0:
+		t.wins++
+		t.winTotal += profit
+	case profit < 0:
+		t.losses++
+		t.lossTotal -= profit
+	}
+}
+
+// WinRate returns the all-time win rate, 0 when no trades were recorded
+func (t *WinRateTracker) WinRate() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.total == 0 {
+		return 0
+	}
+	return float64(t.wins) / float64(t.total)
+}
+
+// RollingWinRate returns the win rate over the last n trades, or all kept trades if fewer.
+// n is capped at the tracker's window.
+func (t *WinRateTracker) RollingWinRate(n int) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if n <= 0 || len(t.recent) == 0 {
+		return 0
+	}
+	recent := t.recent
+	if len(recent) > n {
+		recent = recent[len(recent)-n:]
+	}
+
+	wins := 0
+	for _, profit := range recent {
+		if profit > 0 {
+			wins++
+		}
+	}
+	return float64(wins) / float64(len(recent))
+}
+
+// AverageWin returns the mean profit of winning trades, 0 without wins
+func (t *WinRateTracker) AverageWin() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.wins == 0 {
+		return 0
+	}
+	return t.winTotal / float64(t.wins)
+}
+
+// AverageLoss returns the mean size of losing trades as a positive amount, 0 without losses
+func (t *WinRateTracker) AverageLoss() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.losses == 0 {
+		return 0
+	}
+	return t.lossTotal / float64(t.losses)
+}
+
+// TotalTrades returns the number of recorded trades
+func (t *WinRateTracker) TotalTrades() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.total
+}
+
+// Wins returns the number of profitable trades
+func (t *WinRateTracker) Wins() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.wins
+}
+
+// Losses returns the number of losing trades
+func (t *WinRateTracker) Losses() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.losses
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestWinRateTrackerCounts(t *testing.T) {
+	tracker := NewWinRateTracker(0)
+	for _, profit := range []float64{10, -5, 0, 20, -15} {
+		tracker.RecordTrade(profit)
+	}
+	if got := tracker.TotalTrades(); got != 5 {
+		t.Errorf("TotalTrades = %d, want 5", got)
+	}
+	if got := tracker.Wins(); got != 2 {
+		t.Errorf("Wins = %d, want 2", got)
+	}
+	if got := tracker.Losses(); got != 2 {
+		t.Errorf("Losses = %d, want 2", got)
+	}
+	if got := tracker.WinRate(); math.Abs(got-0.4) > 1e-9 {
+		t.Errorf("WinRate = %f, want 0.4", got)
+	}
+	if got := tracker.AverageWin(); math.Abs(got-15) > 1e-9 {
+		t.Errorf("AverageWin = %f, want 15", got)
+	}
+	if got := tracker.AverageLoss(); math.Abs(got-10) > 1e-9 {
+		t.Errorf("AverageLoss = %f, want 10", got)
+	}
+}
+
+func TestWinRateTrackerEmpty(t *testing.T) {
+	tracker := NewWinRateTracker(0)
+	if tracker.WinRate() != 0 || tracker.RollingWinRate(10) != 0 || tracker.AverageWin() != 0 || tracker.AverageLoss() != 0 {
+		t.Error("empty tracker should report zero statistics")
+	}
+}
+
+func TestWinRateTrackerRollingWindow(t *testing.T) {
+	tracker := NewWinRateTracker(4)
+	// 6 losses followed by 4 wins: only the wins remain in the window
+	for i := 0; i < 6; i++ {
+		tracker.RecordTrade(-1)
+	}
+	for i := 0; i < 4; i++ {
+		tracker.RecordTrade(1)
+	}
+	if got := len(tracker.recent); got != 4 {
+		t.Errorf("kept %d outcomes, want 4", got)
+	}
+	if got := tracker.RollingWinRate(4); got != 1 {
+		t.Errorf("RollingWinRate(4) = %f, want 1", got)
+	}
+	if got := tracker.RollingWinRate(100); got != 1 {
+		t.Errorf("RollingWinRate beyond the window = %f, want 1", got)
+	}
+	if got := tracker.RollingWinRate(2); got != 1 {
+		t.Errorf("RollingWinRate(2) = %f, want 1", got)
+	}
+	// all-time statistics still cover every trade
+	if got := tracker.TotalTrades(); got != 10 {
+		t.Errorf("TotalTrades = %d, want 10", got)
+	}
+	if got := tracker.WinRate(); math.Abs(got-0.4) > 1e-9 {
+		t.Errorf("WinRate = %f, want 0.4", got)
+	}
+	if got := tracker.AverageLoss(); got != 1 {
+		t.Errorf("AverageLoss = %f, want 1", got)
+	}
+}
+
+func TestWinRateTrackerConcurrentRecords(t *testing.T) {
+	tracker := NewWinRateTracker(10)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tracker.RecordTrade(1)
+		}()
+	}
+	wg.Wait()
+	if got := tracker.TotalTrades(); got != 50 {
+		t.Errorf("TotalTrades = %d, want 50", got)
+	}
+	if got := len(tracker.recent); got != 10 {
+		t.Errorf("kept %d outcomes, want 10", got)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// SymbolEvaluator evaluates a symbol and returns the order it wants to place, or nil
+type SymbolEvaluator func(ctx context.Context, symbol string) (*Order, error)
+
+// SymbolResult holds the outcome of evaluating a single symbol
+type SymbolResult struct {
+	Symbol string
+	Order  *Order
+	Err    error
+}
+
+// EvaluateSymbols evaluates symbols concurrently with at most workers goroutines.
+// Results are returned in the order of symbols so that submitting the resulting orders
+// sequentially keeps exposure caps deterministic.
+func EvaluateSymbols(ctx context.Context, symbols []string, workers int, evaluate SymbolEvaluator) []SymbolResult {
+	if workers < 1 {
+		workers = 1
+	}
+
+	results := make([]SymbolResult, len(symbols))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				order, err := evaluate(ctx, symbols[i])
+				results[i] = SymbolResult{Symbol: symbols[i], Order: order, Err: err}
+			}
+		}()
+	}
+
+	for i := range symbols {
+		if ctx.Err() != nil {
+			results[i] = SymbolResult{Symbol: symbols[i], Err: ctx.Err()}
+			continue
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRefreshPricesEvaluatesSymbolsConcurrently(t *testing.T) {
+	bot := newTestBot(t, &fakeExecutor{fillRatio: 1})
+	bot.config.Load().Trading.Workers = 3
+	symbols := []string{"ADAUSDT", "BNBUSDT", "BTCUSDT", "DOGEUSDT", "ETHUSDT", "SOLUSDT", "XRPUSDT"}
+
+	var running, peak int32
+	fetch := func(ctx context.Context, symbol string) (float64, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if symbol == "DOGEUSDT" {
+			return 0, fmt.Errorf("unavailable")
+		}
+		return 300, nil
+	}
+
+	// Signals are copied while the prices refresh, sharing the bot's state
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, symbol := range []string{"BNBUSDT", "ETHUSDT"} {
+			signal := testSignal()
+			signal.Symbol = symbol
+			if _, err := bot.HandleSignal(context.Background(), signal); err != nil {
+				t.Errorf("HandleSignal(%s): %v", symbol, err)
+			}
+		}
+	}()
+	err := bot.RefreshPrices(context.Background(), symbols, fetch)
+	wg.Wait()
+
+	if err == nil {
+		t.Error("expected the failed DOGEUSDT fetch to be reported")
+	}
+	if peak < 2 || peak > 3 {
+		t.Errorf("peak concurrency = %d, want 2 to 3 workers", peak)
+	}
+	for _, symbol := range symbols {
+		_, ok := bot.LastPrice(symbol)
+		if ok != (symbol != "DOGEUSDT") {
+			t.Errorf("LastPrice(%s) recorded = %t", symbol, ok)
+		}
+	}
+	if open := bot.OpenPositions(); len(open) != 2 {
+		t.Errorf("open positions = %d, want both copies", len(open))
+	}
+}